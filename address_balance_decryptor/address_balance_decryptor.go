@@ -8,8 +8,11 @@ import (
 	"mc/cryptography/crypto"
 	"mc/cryptography/crypto/balance_decryptor"
 	"mc/helpers/generics"
+	"mc/helpers/metrics"
 )
 
+var metricsQueueDepth = metrics.NewGauge("balance_decryptor_queue_depth", "Balances waiting to be decrypted")
+
 type AddressBalanceDecryptor struct {
 	all                   *generics.Map[string, *addressBalanceDecryptorWork]
 	previousValues        *generics.Map[string, uint64]
@@ -43,6 +46,7 @@ func (decryptor *AddressBalanceDecryptor) DecryptBalance(decryptionName string,
 
 	foundWork, loaded := decryptor.all.LoadOrStore(string(publicKey)+"_"+string(encryptedBalance), &addressBalanceDecryptorWork{balancePoint, previousValue, make(chan struct{}), ADDRESS_BALANCE_DECRYPTED_INIT, 0, nil, ctx, statusCallback})
 	if !loaded {
+		metricsQueueDepth.Add(1)
 		decryptor.newWorkCn <- foundWork
 	}
 
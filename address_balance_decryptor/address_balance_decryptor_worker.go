@@ -23,6 +23,7 @@ func (worker *AddressBalanceDecryptorWorker) run() {
 		foundWork.result = &addressBalanceDecryptorWorkResult{}
 
 		foundWork.result.decryptedBalance, foundWork.result.err = worker.processWork(foundWork)
+		metricsQueueDepth.Add(-1)
 
 		foundWork.time = time.Now().Unix()
 		atomic.StoreInt32(&foundWork.status, ADDRESS_BALANCE_DECRYPTED_PROCESSED)
@@ -0,0 +1,32 @@
+package addresses
+
+import (
+	"bytes"
+)
+
+const PAYMENT_ID_LENGTH = 8
+
+// PAYMENT_ID_DATA_MAGIC marks the encrypted transaction data that starts with the PaymentID of an integrated address
+// The layout is PAYMENT_ID_DATA_MAGIC | PaymentID (PAYMENT_ID_LENGTH bytes) | message
+// A single byte was ambiguous as arbitrary messages can start with it
+var PAYMENT_ID_DATA_MAGIC = []byte{0x00, 'P', 'I', 'D'}
+
+func EncodePaymentIDData(paymentID, message []byte) []byte {
+	out := make([]byte, 0, len(PAYMENT_ID_DATA_MAGIC)+len(paymentID)+len(message))
+	out = append(out, PAYMENT_ID_DATA_MAGIC...)
+	out = append(out, paymentID...)
+	return append(out, message...)
+}
+
+func HasPaymentIDData(data, paymentID []byte) bool {
+	return len(data) >= len(PAYMENT_ID_DATA_MAGIC)+PAYMENT_ID_LENGTH && bytes.HasPrefix(data, PAYMENT_ID_DATA_MAGIC) && bytes.Equal(data[len(PAYMENT_ID_DATA_MAGIC):len(PAYMENT_ID_DATA_MAGIC)+PAYMENT_ID_LENGTH], paymentID)
+}
+
+// DecodePaymentIDData splits the PaymentID from the message. The message is returned unchanged, including the padding of the encrypted data
+func DecodePaymentIDData(data []byte) (paymentID, message []byte) {
+	if len(data) >= len(PAYMENT_ID_DATA_MAGIC)+PAYMENT_ID_LENGTH && bytes.HasPrefix(data, PAYMENT_ID_DATA_MAGIC) {
+		start := len(PAYMENT_ID_DATA_MAGIC)
+		return data[start : start+PAYMENT_ID_LENGTH], data[start+PAYMENT_ID_LENGTH:]
+	}
+	return nil, data
+}
@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"pandora-pay/blockchain/blockchain_sync"
 	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/blocks/block"
 	"pandora-pay/blockchain/blocks/block/difficulty"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/data_storage"
@@ -52,6 +53,28 @@ type Blockchain struct {
 	NextBlockCreatedCn                      chan *forging_block_work.ForgingWork
 }
 
+// isStakingRewardTx returns true for the tx with the staking and the reward payloads.
+// The optional third payload pays the delegator node commission, only after the activation height
+func isStakingRewardTx(txBase *transaction_zether.TransactionZether, blockHeight uint64) bool {
+	return (len(txBase.Payloads) == 2 || (len(txBase.Payloads) == 3 && blockHeight >= config.NETWORK_SELECTED_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT && txBase.Payloads[2].PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING_REWARD)) && txBase.Payloads[0].PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING && txBase.Payloads[1].PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING_REWARD
+}
+
+// checkStakingRewards verifies that the reward and the delegator commission together don't exceed the forger reward
+func checkStakingRewards(txBase *transaction_zether.TransactionZether, finalForgerReward uint64) (err error) {
+
+	var payloadsReward uint64
+	for _, payload := range txBase.Payloads[1:] {
+		if err = helpers.SafeUint64Add(&payloadsReward, payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraStakingReward).Reward); err != nil {
+			return
+		}
+	}
+
+	if payloadsReward > finalForgerReward {
+		return fmt.Errorf("Payload Reward %d is bigger than it should be %d", payloadsReward, finalForgerReward)
+	}
+	return
+}
+
 func (chain *Blockchain) validateBlocks(blocksComplete []*block_complete.BlockComplete) (err error) {
 
 	if len(blocksComplete) == 0 {
@@ -228,7 +251,7 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 					for index, tx := range blkComplete.Txs {
 						if tx.Version == transaction_type.TX_ZETHER {
 							txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
-							if len(txBase.Payloads) == 2 && txBase.Payloads[0].PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING && txBase.Payloads[1].PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING_REWARD {
+							if isStakingRewardTx(txBase, blkComplete.Height) {
 								if foundStakingRewardTx != nil {
 									return errors.New("Multiple txs with staking & reward payloads")
 								}
@@ -272,8 +295,8 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						return
 					}
 
-					if foundStakingRewardTxBase.Payloads[1].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraStakingReward).Reward > finalForgerReward {
-						return fmt.Errorf("Payload Reward %d is bigger than it should be %d", foundStakingRewardTxBase.Payloads[1].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraStakingReward).Reward, finalForgerReward)
+					if err = checkStakingRewards(foundStakingRewardTxBase, finalForgerReward); err != nil {
+						return
 					}
 
 					//increase supply
@@ -311,10 +334,30 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						return errors.New("Timestamp is too much into the future")
 					}
 
+					var medianTimePast uint64
+					if medianTimePast, err = newChainData.computeMedianTimePast(writer); err != nil {
+						return
+					}
+					if medianTimePast > 0 && blkComplete.Block.Timestamp <= medianTimePast {
+						return errors.New("Timestamp has to be greater than the median time past")
+					}
+
+					if blkComplete.Block.Height >= config.NETWORK_SELECTED_SLASHING_ACTIVATION_HEIGHT {
+						if err = blkComplete.Block.VerifyStakingSignature(); err != nil {
+							return
+						}
+					} else if blkComplete.Block.Version != block.BLOCK_VERSION {
+						return errors.New("Block version is not activated yet")
+					}
+
 					if err = blkComplete.IncludeBlockComplete(dataStorage); err != nil {
 						return fmt.Errorf("Error including block %d into Blockchain: %s", blkComplete.Height, err.Error())
 					}
 
+					if err = chain.includeSlashingEvidences(writer, blkComplete, newChainData, dataStorage); err != nil {
+						return errors.New("Error Including Slashing Evidences: " + err.Error())
+					}
+
 					if err = dataStorage.ProcessPendingStakes(blkComplete.Height); err != nil {
 						return errors.New("Error Processing Pending Stakes: " + err.Error())
 					}
@@ -8,6 +8,7 @@ import (
 	"pandora-pay/config"
 	"pandora-pay/gui"
 	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
 	"strconv"
 )
 
@@ -54,6 +55,30 @@ func (chainData *BlockchainData) computeNextTargetBig(reader store_db_interface.
 	return difficulty.NextTargetBig(deltaTotalDifficulty, deltaTime)
 }
 
+// computeMedianTimePast returns the median timestamp of the last BLOCK_MEDIAN_TIME_PAST_WINDOW blocks which the timestamp of the next block must exceed
+// It returns 0 before the activation height or while there are not enough blocks
+func (chainData *BlockchainData) computeMedianTimePast(reader store_db_interface.StoreDBTransactionInterface) (uint64, error) {
+
+	if chainData.Height < config.NETWORK_SELECTED_MEDIAN_TIME_PAST_ACTIVATION_HEIGHT || config.BLOCK_MEDIAN_TIME_PAST_WINDOW > chainData.Height {
+		return 0, nil
+	}
+
+	var err error
+	timestamps := make([]uint64, config.BLOCK_MEDIAN_TIME_PAST_WINDOW)
+	for i := range timestamps {
+		//totalDifficulty of height h stores the timestamp of the block h-1
+		if _, timestamps[i], err = chainData.LoadTotalDifficultyExtra(reader, chainData.Height-uint64(i)); err != nil {
+			return 0, err
+		}
+	}
+
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+
+	return timestamps[len(timestamps)/2], nil
+}
+
 func (chainData *BlockchainData) updateChainInfo() {
 	gui.GUI.Info2Update("Blocks", strconv.FormatUint(chainData.Height, 10))
 	gui.GUI.Info2Update("Chain  Hash", base64.StdEncoding.EncodeToString(chainData.Hash))
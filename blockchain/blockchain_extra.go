@@ -207,10 +207,30 @@ func (chain *Blockchain) createNextBlockForForging(chainData *BlockchainData, ne
 				PrevKernelHash: chainData.KernelHash,
 				Timestamp:      chainData.Timestamp,
 			}
+
+			//the timestamp of the new block must be greater than the median time past
+			var medianTimePast uint64
+			if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+				medianTimePast, err = chainData.computeMedianTimePast(reader)
+				return
+			}); err != nil {
+				gui.GUI.Error("Error computing median time past", err)
+				return
+			}
+			if medianTimePast > 0 && blk.Timestamp <= medianTimePast {
+				blk.Timestamp = medianTimePast + 1
+			}
 		}
 
 		blk.StakingNonce = make([]byte, 32)
 
+		//the placeholders are replaced by SignStaking when the block is forged
+		if chainData.Height >= config.NETWORK_SELECTED_SLASHING_ACTIVATION_HEIGHT {
+			blk.Version = block.BLOCK_VERSION_STAKING_SIGNATURE
+			blk.StakingPoint = make([]byte, 33)
+			blk.StakingSignature = make([]byte, cryptography.SignatureSize)
+		}
+
 		blk.BloomSerializedNow(blk.SerializeManualToBytes())
 
 		blkComplete := &block_complete.BlockComplete{
@@ -0,0 +1,201 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"golang.org/x/exp/slices"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/pending_stakes_list/pending_stakes"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_stake"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+)
+
+// burnStake applies the changes of the staking payload to the balances of its senders. Only the staker has a change of -StakingAmount,
+// the other ring members receive encryptions of 0, hence the staker loses the entire stake without being revealed.
+// In case the staker already spent part of the stake, the balance becomes a debt which is paid by the next incoming funds
+func (chain *Blockchain) burnStake(txBase *transaction_zether.TransactionZether, dataStorage *data_storage.DataStorage) (uint64, error) {
+
+	payload := txBase.Payloads[0]
+
+	accs, err := dataStorage.AccsCollection.GetMap(payload.Asset)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, publicKey := range txBase.Bloom.PublicKeyLists[0] {
+		if (i%2 == 0) != payload.Parity { //recipient
+			continue
+		}
+
+		acc, err := accs.Get(string(publicKey))
+		if err != nil {
+			return 0, err
+		}
+		if acc == nil {
+			return 0, errors.New("Slashed staking account doesn't exist")
+		}
+
+		acc.Balance.Amount = acc.GetBalance().Add(crypto.ConstructElGamal(payload.Statement.C[i], payload.Statement.D))
+		if err = accs.Update(string(publicKey), acc); err != nil {
+			return 0, err
+		}
+	}
+
+	return payload.BurnValue, nil
+}
+
+// removeStakingRewardPendingStakes removes the reward and the delegator commission of the staking reward tx from the pending stakes.
+// They were added as pending stakes to all recipients of their rings
+func removeStakingRewardPendingStakes(txBase *transaction_zether.TransactionZether, pendingStakes *pending_stakes.PendingStakes) (reward uint64) {
+
+	rewardPending := make(map[string][][]byte)
+	for t, payload := range txBase.Payloads[1:] {
+		for i, publicKey := range txBase.Bloom.PublicKeyLists[t+1] {
+			if (i%2 == 0) != payload.Parity {
+				rewardPending[string(publicKey)] = append(rewardPending[string(publicKey)], crypto.ConstructElGamal(payload.Statement.C[i], payload.Statement.D).Serialize())
+			}
+		}
+		reward += payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraStakingReward).Reward
+	}
+
+	pending := pendingStakes.Pending[:0]
+	for _, pendingStake := range pendingStakes.Pending {
+		if amounts := rewardPending[string(pendingStake.PublicKey)]; len(amounts) > 0 {
+			if index := slices.IndexFunc(amounts, func(amount []byte) bool { return bytes.Equal(amount, pendingStake.PendingAmount) }); index != -1 {
+				rewardPending[string(pendingStake.PublicKey)] = slices.Delete(amounts, index, index+1)
+				continue
+			}
+		}
+		pending = append(pending, pendingStake)
+	}
+	pendingStakes.Pending = pending
+
+	return
+}
+
+// confiscateStake penalizes the staker of the block forged at blockHeight. The still pending reward and commission of the block are removed
+// and the staked amount is burned
+func (chain *Blockchain) confiscateStake(reader store_db_interface.StoreDBTransactionInterface, blockHeight uint64, stakingNonce []byte, dataStorage *data_storage.DataStorage) (reward, burned uint64, err error) {
+
+	blockHeightStr := strconv.FormatUint(blockHeight, 10)
+
+	hash := reader.Get("blockHash_ByHeight" + blockHeightStr)
+	if hash == nil {
+		return 0, 0, errors.New("Slashed block hash was not found")
+	}
+
+	blk := block.CreateEmptyBlock()
+	if err = blk.Deserialize(advanced_buffers.NewBufferReader(reader.Get("block_ByHash" + string(hash)))); err != nil {
+		return
+	}
+
+	if !bytes.Equal(blk.StakingNonce, stakingNonce) {
+		return 0, 0, errors.New("Slashed block was not forged by the slashed staker")
+	}
+
+	txHashes := [][]byte{}
+	if err = msgpack.Unmarshal(reader.Get("blockTxs"+blockHeightStr), &txHashes); err != nil {
+		return
+	}
+	if len(txHashes) == 0 {
+		return 0, 0, errors.New("Slashed block has no transactions")
+	}
+
+	//staking reward tx is always the last one
+	tx := &transaction.Transaction{}
+	if err = tx.Deserialize(advanced_buffers.NewBufferReader(reader.Get("tx:" + string(txHashes[len(txHashes)-1])))); err != nil {
+		return
+	}
+	if err = tx.BloomAll(); err != nil {
+		return
+	}
+
+	txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+
+	if burned, err = chain.burnStake(txBase, dataStorage); err != nil {
+		return
+	}
+
+	pendingHeight := blockHeight + config_stake.GetPendingStakeWindow(blockHeight)
+
+	pendingStakes, err := dataStorage.PendingStakes.GetPendingStakes(pendingHeight)
+	if err != nil {
+		return
+	}
+	if pendingStakes == nil {
+		return 0, 0, errors.New("Pending stakes of the slashed block were not found")
+	}
+
+	reward = removeStakingRewardPendingStakes(txBase, pendingStakes)
+
+	if err = dataStorage.PendingStakes.Update(strconv.FormatUint(pendingHeight, 10), pendingStakes); err != nil {
+		return
+	}
+
+	return
+}
+
+// includeSlashingEvidences penalizes the stakers proven to equivocate by the evidences included in the block
+func (chain *Blockchain) includeSlashingEvidences(reader store_db_interface.StoreDBTransactionInterface, blkComplete *block_complete.BlockComplete, newChainData *BlockchainData, dataStorage *data_storage.DataStorage) error {
+
+	for _, tx := range blkComplete.Txs {
+
+		if tx.Version != transaction_type.TX_SIMPLE {
+			continue
+		}
+
+		txBase := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+		if txBase.TxScript != transaction_simple.SCRIPT_SLASHING_EVIDENCE {
+			continue
+		}
+
+		evidence := txBase.Extra.(*transaction_simple_extra.TransactionSimpleExtraSlashingEvidence)
+
+		confiscated, burned, err := chain.confiscateStake(reader, evidence.Block1.Height, evidence.Block1.StakingNonce, dataStorage)
+		if err != nil {
+			return err
+		}
+
+		slash, err := dataStorage.Slashings.Get(string(evidence.Block1.StakingNonce))
+		if err != nil {
+			return err
+		}
+		slash.Confiscated = confiscated
+		slash.Burned = burned
+		if err = dataStorage.Slashings.Update(string(evidence.Block1.StakingNonce), slash); err != nil {
+			return err
+		}
+
+		ast, err := dataStorage.Asts.Get(string(config_coins.NATIVE_ASSET_FULL))
+		if err != nil {
+			return err
+		}
+		if err = helpers.SafeUint64Add(&confiscated, burned); err != nil {
+			return err
+		}
+		if err = ast.AddNativeSupply(false, confiscated); err != nil {
+			return err
+		}
+		if err = dataStorage.Asts.Update(string(config_coins.NATIVE_ASSET_FULL), ast); err != nil {
+			return err
+		}
+
+		newChainData.Supply = ast.Supply
+	}
+
+	return nil
+}
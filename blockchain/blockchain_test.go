@@ -0,0 +1,141 @@
+package blockchain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"pandora-pay/blockchain/data_storage/pending_stakes_list/pending_stakes"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/config"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"testing"
+)
+
+func createStakingRewardTestTx(scripts []transaction_zether_payload_script.PayloadScriptType, rewards []uint64) *transaction_zether.TransactionZether {
+
+	txBase := &transaction_zether.TransactionZether{Bloom: &transaction_zether.TransactionZetherBloom{}}
+
+	for t, script := range scripts {
+
+		payload := &transaction_zether_payload.TransactionZetherPayload{PayloadScript: script, Statement: &crypto.Statement{}}
+		if script == transaction_zether_payload_script.SCRIPT_STAKING_REWARD {
+			payload.Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraStakingReward{Reward: rewards[t]}
+		}
+
+		publicKeys := make([][]byte, 4)
+		for i := range publicKeys {
+			publicKeys[i] = helpers.RandomBytes(33)
+			payload.Statement.C = append(payload.Statement.C, new(bn256.G1).ScalarMult(crypto.G, crypto.RandomScalarBNRed().BigInt()))
+		}
+		payload.Statement.D = new(bn256.G1).ScalarMult(crypto.G, crypto.RandomScalarBNRed().BigInt())
+
+		txBase.Payloads = append(txBase.Payloads, payload)
+		txBase.Bloom.PublicKeyLists = append(txBase.Bloom.PublicKeyLists, publicKeys)
+	}
+
+	return txBase
+}
+
+func TestIsStakingRewardTx(t *testing.T) {
+
+	activationHeight := config.NETWORK_SELECTED_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT
+	config.NETWORK_SELECTED_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT = 100
+	defer func() {
+		config.NETWORK_SELECTED_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT = activationHeight
+	}()
+
+	staking, reward, transfer := transaction_zether_payload_script.SCRIPT_STAKING, transaction_zether_payload_script.SCRIPT_STAKING_REWARD, transaction_zether_payload_script.SCRIPT_TRANSFER
+
+	for _, test := range []struct {
+		name    string
+		scripts []transaction_zether_payload_script.PayloadScriptType
+		height  uint64
+		valid   bool
+	}{
+		{"reward", []transaction_zether_payload_script.PayloadScriptType{staking, reward}, 10, true},
+		{"reward after activation", []transaction_zether_payload_script.PayloadScriptType{staking, reward}, 100, true},
+		{"commission before activation", []transaction_zether_payload_script.PayloadScriptType{staking, reward, reward}, 99, false},
+		{"commission at activation", []transaction_zether_payload_script.PayloadScriptType{staking, reward, reward}, 100, true},
+		{"commission after activation", []transaction_zether_payload_script.PayloadScriptType{staking, reward, reward}, 1000, true},
+		{"third payload transfer", []transaction_zether_payload_script.PayloadScriptType{staking, reward, transfer}, 1000, false},
+		{"four payloads", []transaction_zether_payload_script.PayloadScriptType{staking, reward, reward, reward}, 1000, false},
+		{"missing reward", []transaction_zether_payload_script.PayloadScriptType{staking}, 1000, false},
+		{"missing staking", []transaction_zether_payload_script.PayloadScriptType{transfer, reward, reward}, 1000, false},
+		{"swapped", []transaction_zether_payload_script.PayloadScriptType{reward, staking}, 1000, false},
+	} {
+		rewards := make([]uint64, len(test.scripts))
+		assert.Equal(t, test.valid, isStakingRewardTx(createStakingRewardTestTx(test.scripts, rewards), test.height), test.name)
+	}
+}
+
+func TestCheckStakingRewards(t *testing.T) {
+
+	scripts := []transaction_zether_payload_script.PayloadScriptType{transaction_zether_payload_script.SCRIPT_STAKING, transaction_zether_payload_script.SCRIPT_STAKING_REWARD, transaction_zether_payload_script.SCRIPT_STAKING_REWARD}
+
+	for _, test := range []struct {
+		name              string
+		rewards           []uint64
+		finalForgerReward uint64
+		valid             bool
+	}{
+		{"reward", []uint64{100}, 100, true},
+		{"reward too big", []uint64{101}, 100, false},
+		{"commission", []uint64{90, 10}, 100, true},
+		{"commission below", []uint64{80, 10}, 100, true},
+		{"summed reward too big", []uint64{90, 11}, 100, false},
+		{"commission alone too big", []uint64{0, 101}, 100, false},
+		{"overflow", []uint64{math.MaxUint64, 2}, math.MaxUint64, false},
+	} {
+		txBase := createStakingRewardTestTx(scripts[:len(test.rewards)+1], append([]uint64{0}, test.rewards...))
+		if test.valid {
+			assert.NoError(t, checkStakingRewards(txBase, test.finalForgerReward), test.name)
+		} else {
+			assert.Error(t, checkStakingRewards(txBase, test.finalForgerReward), test.name)
+		}
+	}
+}
+
+func TestRemoveStakingRewardPendingStakes(t *testing.T) {
+
+	scripts := []transaction_zether_payload_script.PayloadScriptType{transaction_zether_payload_script.SCRIPT_STAKING, transaction_zether_payload_script.SCRIPT_STAKING_REWARD, transaction_zether_payload_script.SCRIPT_STAKING_REWARD}
+	txBase := createStakingRewardTestTx(scripts, []uint64{0, 90, 10})
+
+	txBase.Payloads[2].Parity = true
+
+	//the forger receives both the reward and the commission
+	txBase.Bloom.PublicKeyLists[2][1] = txBase.Bloom.PublicKeyLists[1][0]
+
+	pendingStakes := &pending_stakes.PendingStakes{}
+	expected := []*pending_stakes.PendingStake{}
+
+	for p, payload := range txBase.Payloads[1:] {
+		for i, publicKey := range txBase.Bloom.PublicKeyLists[p+1] {
+			amount := crypto.ConstructElGamal(payload.Statement.C[i], payload.Statement.D).Serialize()
+			if (i%2 == 0) != payload.Parity {
+				pendingStakes.Pending = append(pendingStakes.Pending, &pending_stakes.PendingStake{publicKey, amount})
+			}
+		}
+	}
+
+	//the pending stakes of the other blocks are kept, even for the same public keys
+	for _, publicKey := range [][]byte{txBase.Bloom.PublicKeyLists[1][0], helpers.RandomBytes(33)} {
+		other := crypto.ConstructElGamal(new(bn256.G1).ScalarMult(crypto.G, crypto.RandomScalarBNRed().BigInt()), txBase.Payloads[1].Statement.D).Serialize()
+		pendingStake := &pending_stakes.PendingStake{publicKey, other}
+		pendingStakes.Pending = append(pendingStakes.Pending, pendingStake)
+		expected = append(expected, pendingStake)
+	}
+
+	assert.Len(t, pendingStakes.Pending, 6)
+
+	reward := removeStakingRewardPendingStakes(txBase, pendingStakes)
+	assert.Equal(t, uint64(100), reward)
+	assert.Equal(t, expected, pendingStakes.Pending)
+
+	//nothing else is removed the second time
+	assert.Equal(t, uint64(100), removeStakingRewardPendingStakes(txBase, pendingStakes))
+	assert.Equal(t, expected, pendingStakes.Pending)
+}
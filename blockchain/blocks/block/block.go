@@ -1,20 +1,28 @@
 package block
 
 import (
+	"bytes"
+	"errors"
+	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
+	"strconv"
 )
 
 type Block struct {
 	*BlockHeader
-	MerkleHash     []byte      `json:"merkleHash" msgpack:"merkleHash"`          //32 byte
-	PrevHash       []byte      `json:"prevHash"  msgpack:"prevHash"`             //32 byte
-	PrevKernelHash []byte      `json:"prevKernelHash"  msgpack:"prevKernelHash"` //32 byte
-	Timestamp      uint64      `json:"timestamp" msgpack:"timestamp"`
-	StakingAmount  uint64      `json:"stakingAmount" msgpack:"stakingAmount"`
-	StakingNonce   []byte      `json:"stakingNonce" msgpack:"stakingNonce"` // 33 byte public key can also be found into the accounts tree
-	Bloom          *BlockBloom `json:"bloom" msgpack:"bloom"`
+	MerkleHash       []byte      `json:"merkleHash" msgpack:"merkleHash"`          //32 byte
+	PrevHash         []byte      `json:"prevHash"  msgpack:"prevHash"`             //32 byte
+	PrevKernelHash   []byte      `json:"prevKernelHash"  msgpack:"prevKernelHash"` //32 byte
+	Timestamp        uint64      `json:"timestamp" msgpack:"timestamp"`
+	StakingAmount    uint64      `json:"stakingAmount" msgpack:"stakingAmount"`
+	StakingNonce     []byte      `json:"stakingNonce" msgpack:"stakingNonce"`                             // 33 byte public key can also be found into the accounts tree
+	StakingPoint     []byte      `json:"stakingPoint,omitempty" msgpack:"stakingPoint,omitempty"`         //33 byte, StakingNonce is its hash. Only for BLOCK_VERSION_STAKING_SIGNATURE
+	StakingSignature []byte      `json:"stakingSignature,omitempty" msgpack:"stakingSignature,omitempty"` //64 byte, signed using the StakingPoint
+	Bloom            *BlockBloom `json:"bloom" msgpack:"bloom"`
 }
 
 func CreateEmptyBlock() *Block {
@@ -70,6 +78,52 @@ func (blk *Block) AdvancedSerialization(w *advanced_buffers.BufferWriter, kernel
 
 	w.Write(blk.StakingNonce)
 
+	if !kernelHash && blk.Version == BLOCK_VERSION_STAKING_SIGNATURE {
+		w.Write(blk.StakingPoint)
+		if inclSignature {
+			w.Write(blk.StakingSignature)
+		}
+	}
+
+}
+
+// StakingNonceBase returns the point which multiplied by the staking private key gives the StakingPoint. It is the same point used by the staking proof
+func StakingNonceBase(prevKernelHash []byte) *bn256.G1 {
+	uinput := append([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT), prevKernelHash...)
+	uinput = append(uinput, config_coins.NATIVE_ASSET_FULL...)
+	uinput = append(uinput, strconv.Itoa(0)...)
+	return crypto.HashToPoint(crypto.HashtoNumber(uinput))
+}
+
+// SignStaking signs the block with the staking private key. Only the StakingPoint is revealed which is also revealed by the staking proof
+func (blk *Block) SignStaking(privateKey []byte) (err error) {
+
+	base := StakingNonceBase(blk.PrevKernelHash)
+
+	blk.StakingPoint = new(bn256.G1).ScalarMult(base, new(crypto.BNRed).SetBytes(privateKey).BigInt()).EncodeCompressed()
+	blk.StakingSignature, err = crypto.SignMessageBase(blk.SerializeForSigning(), privateKey, base)
+	return
+}
+
+// VerifyStakingSignature verifies that the block was signed by the owner of the staking nonce
+func (blk *Block) VerifyStakingSignature() error {
+
+	if blk.Version != BLOCK_VERSION_STAKING_SIGNATURE {
+		return errors.New("Block is not signed")
+	}
+	if !bytes.Equal(cryptography.SHA3(blk.StakingPoint), blk.StakingNonce) {
+		return errors.New("Staking Point doesn't match the Staking Nonce")
+	}
+
+	var u bn256.G1
+	if err := u.DecodeCompressed(blk.StakingPoint); err != nil {
+		return err
+	}
+
+	if !crypto.VerifySignatureBase(blk.SerializeForSigning(), blk.StakingSignature, StakingNonceBase(blk.PrevKernelHash), &u) {
+		return errors.New("Staking Signature is invalid")
+	}
+	return nil
 }
 
 func (blk *Block) SerializeForForging(w *advanced_buffers.BufferWriter) {
@@ -111,6 +165,14 @@ func (blk *Block) Deserialize(r *advanced_buffers.BufferReader) (err error) {
 	if blk.StakingNonce, err = r.ReadBytes(32); err != nil {
 		return
 	}
+	if blk.Version == BLOCK_VERSION_STAKING_SIGNATURE {
+		if blk.StakingPoint, err = r.ReadBytes(33); err != nil {
+			return
+		}
+		if blk.StakingSignature, err = r.ReadBytes(cryptography.SignatureSize); err != nil {
+			return
+		}
+	}
 
 	serialized := r.Buf[first:r.Position]
 	blk.BloomSerializedNow(serialized)
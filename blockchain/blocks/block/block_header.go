@@ -5,13 +5,18 @@ import (
 	"pandora-pay/helpers/advanced_buffers"
 )
 
+const (
+	BLOCK_VERSION                   uint64 = 0
+	BLOCK_VERSION_STAKING_SIGNATURE uint64 = 1 //the block is signed with the staking key
+)
+
 type BlockHeader struct {
 	Version uint64 `json:"version" msgpack:"version"`
 	Height  uint64 `json:"height" msgpack:"height"`
 }
 
 func (blockHeader *BlockHeader) Validate() error {
-	if blockHeader.Version != 0 {
+	if blockHeader.Version != BLOCK_VERSION && blockHeader.Version != BLOCK_VERSION_STAKING_SIGNATURE {
 		return errors.New("Invalid Block")
 	}
 	return nil
@@ -4,6 +4,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"pandora-pay/addresses"
 	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
 	"testing"
@@ -19,15 +21,13 @@ var (
 func TestBlock_Serialize(t *testing.T) {
 	var err error
 
-	privateKey := addresses.GenerateNewPrivateKey()
-	publicKey := privateKey.GeneratePublicKey()
-
 	blk := Block{
 		BlockHeader:    &BlockHeader{Version: 0, Height: 0},
 		MerkleHash:     merkleHash,
 		PrevHash:       prevHash,
 		PrevKernelHash: prevKernelHash,
 		Timestamp:      uint64(time.Now().Unix()),
+		StakingNonce:   cryptography.SHA3([]byte("StakingNonce")),
 	}
 
 	buf := blk.SerializeManualToBytes()
@@ -48,7 +48,6 @@ func TestBlock_SerializeForSigning(t *testing.T) {
 	var err error
 
 	privateKey := addresses.GenerateNewPrivateKey()
-	publicKey := privateKey.GeneratePublicKey()
 
 	blockHeader := &BlockHeader{Version: 0, Height: 0}
 	blk := Block{
@@ -68,3 +67,75 @@ func TestBlock_SerializeForSigning(t *testing.T) {
 
 	assert.NotEqual(t, signature, helpers.EmptyBytes(cryptography.SignatureSize), "Invalid signature")
 }
+
+func createStakingSignedBlock(t *testing.T, privateKey *addresses.PrivateKey) *Block {
+
+	blk := &Block{
+		BlockHeader:    &BlockHeader{Version: BLOCK_VERSION_STAKING_SIGNATURE, Height: 10},
+		MerkleHash:     merkleHash,
+		PrevHash:       prevHash,
+		PrevKernelHash: prevKernelHash,
+		Timestamp:      uint64(time.Now().Unix()),
+		StakingAmount:  1000,
+	}
+
+	stakingPoint := new(bn256.G1).ScalarMult(StakingNonceBase(prevKernelHash), new(crypto.BNRed).SetBytes(privateKey.Key).BigInt())
+	blk.StakingNonce = cryptography.SHA3(stakingPoint.EncodeCompressed())
+
+	assert.NoError(t, blk.SignStaking(privateKey.Key))
+	assert.Equal(t, stakingPoint.EncodeCompressed(), blk.StakingPoint)
+
+	return blk
+}
+
+func TestBlock_SerializeStakingSignature(t *testing.T) {
+
+	privateKey := addresses.GenerateNewPrivateKey()
+	blk := createStakingSignedBlock(t, privateKey)
+
+	assert.NoError(t, blk.VerifyStakingSignature())
+
+	buf := blk.SerializeManualToBytes()
+
+	blk2 := CreateEmptyBlock()
+	assert.NoError(t, blk2.Deserialize(advanced_buffers.NewBufferReader(buf)))
+	assert.Equal(t, buf, blk2.SerializeManualToBytes(), "Serialization/Deserialization doesn't work")
+	assert.Equal(t, blk.StakingPoint, blk2.StakingPoint)
+	assert.Equal(t, blk.StakingSignature, blk2.StakingSignature)
+	assert.NoError(t, blk2.VerifyStakingSignature())
+
+	//the kernel hash doesn't include the staking point and signature
+	assert.Equal(t, blk.ComputeKernelHash(), blk2.ComputeKernelHash())
+}
+
+func TestBlock_VerifyStakingSignature(t *testing.T) {
+
+	privateKey := addresses.GenerateNewPrivateKey()
+	otherPrivateKey := addresses.GenerateNewPrivateKey()
+
+	for _, test := range []struct {
+		name   string
+		change func(blk *Block)
+	}{
+		{"timestamp", func(blk *Block) { blk.Timestamp += 1 }},
+		{"staking amount", func(blk *Block) { blk.StakingAmount += 1 }},
+		{"merkle hash", func(blk *Block) { blk.MerkleHash = cryptography.SHA3([]byte("OtherMerkleHash")) }},
+		{"signature", func(blk *Block) {
+			blk.StakingSignature = append([]byte{}, blk.StakingSignature...)
+			blk.StakingSignature[len(blk.StakingSignature)-1] ^= 1
+		}},
+		{"staking point", func(blk *Block) {
+			other := createStakingSignedBlock(t, otherPrivateKey)
+			blk.StakingPoint = other.StakingPoint
+		}},
+		{"other staker", func(blk *Block) {
+			//the other staker can not sign with the staking nonce of the first one
+			assert.NoError(t, blk.SignStaking(otherPrivateKey.Key))
+		}},
+		{"not signed", func(blk *Block) { blk.Version = BLOCK_VERSION }},
+	} {
+		blk := createStakingSignedBlock(t, privateKey)
+		test.change(blk)
+		assert.Error(t, blk.VerifyStakingSignature(), test.name)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
 	"pandora-pay/blockchain/data_storage/registrations"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/blockchain/data_storage/slashings"
 	"pandora-pay/config/config_asset_fee"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography/crypto"
@@ -31,6 +32,9 @@ type DataStorage struct {
 	ConditionalPaymentsCollection *conditional_payments_list.ConditionalPaymentsCollection
 	Asts                          *assets.Assets
 	AstsFeeLiquidityCollection    *assets.AssetsFeeLiquidityCollection
+	Slashings                     *slashings.Slashings
+	MaturedPendingStakes          []*pending_stakes.PendingStakes //pending stakes which became active, used for notifications
+	maturedPendingStakesChanges   []*pending_stakes.PendingStakes
 }
 
 func (dataStorage *DataStorage) GetOrCreateAccount(assetId, publicKey []byte, validateRegistration bool) (*accounts.Accounts, *account.Account, error) {
@@ -212,6 +216,7 @@ func (dataStorage *DataStorage) ProcessPendingStakes(blockHeight uint64) error {
 	}
 
 	dataStorage.PendingStakes.Delete(strconv.FormatUint(blockHeight, 10))
+	dataStorage.maturedPendingStakesChanges = append(dataStorage.maturedPendingStakesChanges, pendingStakes)
 	return nil
 }
 
@@ -407,6 +412,9 @@ func NewDataStorage(dbTx store_db_interface.StoreDBTransactionInterface) (out *D
 		conditional_payments_list.NewConditionalPaymentsCollection(dbTx),
 		assets.NewAssets(dbTx),
 		assets.NewAssetsFeeLiquidityCollection(dbTx),
+		slashings.NewSlashings(dbTx),
+		nil,
+		nil,
 	}
 
 	return
@@ -19,6 +19,7 @@ func (dataStorage *DataStorage) GetListWithoutCollections() (list []hash_map.Has
 		dataStorage.PlainAccs.HashMap,
 		dataStorage.PendingStakes.HashMap,
 		dataStorage.Asts.HashMap,
+		dataStorage.Slashings.HashMap,
 	}
 }
 
@@ -36,6 +37,7 @@ func (dataStorage *DataStorage) GetList(computeChangesSize bool) (list []hash_ma
 	if !computeChangesSize {
 		list = append(list, dataStorage.AstsFeeLiquidityCollection.GetAllHashmaps()...)
 		list = append(list, dataStorage.ConditionalPaymentsCollection.GetAllHashmaps()...)
+		list = append(list, dataStorage.Slashings.HashMap)
 	}
 
 	return
@@ -61,6 +63,7 @@ func (dataStorage *DataStorage) Rollback() {
 	for _, it := range list {
 		it.Rollback()
 	}
+	dataStorage.maturedPendingStakesChanges = nil
 }
 
 func (dataStorage *DataStorage) CommitChanges() (err error) {
@@ -70,6 +73,8 @@ func (dataStorage *DataStorage) CommitChanges() (err error) {
 			return
 		}
 	}
+	dataStorage.MaturedPendingStakes = append(dataStorage.MaturedPendingStakes, dataStorage.maturedPendingStakesChanges...)
+	dataStorage.maturedPendingStakesChanges = nil
 	return
 }
 
@@ -1,6 +1,7 @@
 package pending_stakes_list
 
 import (
+	"bytes"
 	"pandora-pay/blockchain/data_storage/pending_stakes_list/pending_stakes"
 	"pandora-pay/store/hash_map"
 	"pandora-pay/store/store_db/store_db_interface"
@@ -27,6 +28,35 @@ func (this *PendingStakesList) GetPendingStakes(blockHeight uint64) (*pending_st
 	return this.Get(strconv.FormatUint(blockHeight, 10))
 }
 
+// GetPublicKeyPendingStakes returns the pending stakes of the publicKey activating between startHeight and endHeight
+func (this *PendingStakesList) GetPublicKeyPendingStakes(publicKey []byte, startHeight, endHeight uint64) ([]*pending_stakes.PendingStakes, error) {
+
+	out := []*pending_stakes.PendingStakes{}
+	for height := startHeight; height <= endHeight; height++ {
+
+		pendingStakes, err := this.GetPendingStakes(height)
+		if err != nil {
+			return nil, err
+		}
+		if pendingStakes == nil {
+			continue
+		}
+
+		var list []*pending_stakes.PendingStake
+		for _, pending := range pendingStakes.Pending {
+			if bytes.Equal(pending.PublicKey, publicKey) {
+				list = append(list, pending)
+			}
+		}
+
+		if len(list) > 0 {
+			out = append(out, &pending_stakes.PendingStakes{nil, height, list})
+		}
+	}
+
+	return out, nil
+}
+
 func NewPendingStakesList(tx store_db_interface.StoreDBTransactionInterface) (this *PendingStakesList) {
 
 	this = &PendingStakesList{
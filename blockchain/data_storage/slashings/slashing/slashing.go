@@ -0,0 +1,65 @@
+package slashing
+
+import (
+	"errors"
+	"pandora-pay/helpers/advanced_buffers"
+)
+
+type Slashing struct {
+	StakingNonce []byte `json:"-" msgpack:"-"` //hashMap key
+	Version      uint64 `json:"version" msgpack:"version"`
+	Height       uint64 `json:"height" msgpack:"height"`
+	Confiscated  uint64 `json:"confiscated" msgpack:"confiscated"` //pending reward and commission removed
+	Burned       uint64 `json:"burned" msgpack:"burned"`           //staked amount burned from the staker balance
+}
+
+func (slashing *Slashing) IsDeletable() bool {
+	return false
+}
+
+func (slashing *Slashing) SetKey(key []byte) {
+	slashing.StakingNonce = key
+}
+
+func (slashing *Slashing) SetIndex(value uint64) {
+}
+
+func (slashing *Slashing) GetIndex() uint64 {
+	return 0
+}
+
+func (slashing *Slashing) Validate() error {
+	if slashing.Version != 0 {
+		return errors.New("Slashing Version is invalid")
+	}
+	return nil
+}
+
+func (slashing *Slashing) Serialize(w *advanced_buffers.BufferWriter) {
+	w.WriteUvarint(slashing.Version)
+	w.WriteUvarint(slashing.Height)
+	w.WriteUvarint(slashing.Confiscated)
+	w.WriteUvarint(slashing.Burned)
+}
+
+func (slashing *Slashing) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+	if slashing.Version, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	if slashing.Height, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	if slashing.Confiscated, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	if slashing.Burned, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	return
+}
+
+func NewSlashing(stakingNonce []byte) *Slashing {
+	return &Slashing{
+		StakingNonce: stakingNonce,
+	}
+}
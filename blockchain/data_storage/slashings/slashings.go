@@ -0,0 +1,36 @@
+package slashings
+
+import (
+	"pandora-pay/blockchain/data_storage/slashings/slashing"
+	"pandora-pay/cryptography"
+	"pandora-pay/store/hash_map"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+// Slashings keeps the staking nonces for which an equivocation evidence was already included
+type Slashings struct {
+	*hash_map.HashMap[*slashing.Slashing]
+}
+
+// WARNING: should NOT be used manually without being called from DataStorage
+func (this *Slashings) CreateNewSlashing(stakingNonce []byte, blockHeight uint64) (*slashing.Slashing, error) {
+	slash := slashing.NewSlashing(stakingNonce)
+	slash.Height = blockHeight
+	if err := this.HashMap.Create(string(stakingNonce), slash); err != nil {
+		return nil, err
+	}
+	return slash, nil
+}
+
+func NewSlashings(tx store_db_interface.StoreDBTransactionInterface) (this *Slashings) {
+
+	this = &Slashings{
+		hash_map.CreateNewHashMap[*slashing.Slashing](tx, "slashings", cryptography.HashSize, false),
+	}
+
+	this.HashMap.CreateObject = func(key []byte, index uint64) (*slashing.Slashing, error) {
+		return slashing.NewSlashing(key), nil
+	}
+
+	return
+}
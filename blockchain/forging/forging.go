@@ -6,6 +6,7 @@ import (
 	"pandora-pay/blockchain/blockchain_types"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/forging/forging_block_work"
+	"pandora-pay/blockchain/forging/forging_stats"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/config"
 	"pandora-pay/gui"
@@ -27,6 +28,10 @@ type Forging struct {
 
 func CreateForging(mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor) (*Forging, error) {
 
+	if err := forging_stats.ForgingStatsInit(); err != nil {
+		return nil, err
+	}
+
 	forging := &Forging{
 		mempool,
 		addressBalanceDecryptor,
@@ -62,6 +67,7 @@ func (forging *Forging) InitializeForging(createForgingTransactions func(*block_
 	forging.Wallet.workersDestroyedCn = forging.forgingThread.workersDestroyedCn
 
 	forging.Wallet.initialized.Set()
+	forging_stats.ForgingStats.ProcessForgingStats(updateNewChainUpdate)
 	recovery.SafeGo(forging.Wallet.runProcessUpdates)
 	recovery.SafeGo(forging.Wallet.runDecryptBalanceAndNotifyWorkers)
 
@@ -0,0 +1,8 @@
+package forging
+
+import "pandora-pay/helpers/metrics"
+
+var (
+	metricsForgingHashes   = metrics.NewCounterVec("forging_hashes_total", "Kernel hashes computed by forging worker", "worker")
+	metricsForgingAttempts = metrics.NewCounterVec("forging_attempts_total", "Solutions found by forging worker", "worker")
+)
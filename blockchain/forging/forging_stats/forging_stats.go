@@ -0,0 +1,328 @@
+package forging_stats
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/helpers/multicast"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FORGING_STATS_CONFIRMATIONS is the number of blocks after which a forged block is no longer checked for reorgs
+const FORGING_STATS_CONFIRMATIONS = uint64(100)
+
+type ForgingAddressStats struct {
+	PublicKey        helpers.Base64 `json:"publicKey" msgpack:"publicKey"`
+	ForgedBlocks     uint64         `json:"forgedBlocks" msgpack:"forgedBlocks"`
+	Rewards          uint64         `json:"rewards" msgpack:"rewards"` //rewards of the forged blocks which were not orphaned
+	OrphanedBlocks   uint64         `json:"orphanedBlocks" msgpack:"orphanedBlocks"`
+	OrphanedRewards  uint64         `json:"orphanedRewards" msgpack:"orphanedRewards"`
+	EligibleSeconds  uint64         `json:"eligibleSeconds" msgpack:"eligibleSeconds"` //time in which the staked balance was at least the minimum stake
+	LastForgedHeight uint64         `json:"lastForgedHeight" msgpack:"lastForgedHeight"`
+}
+
+type ForgingLedgerEntry struct {
+	PublicKey helpers.Base64 `json:"publicKey" msgpack:"publicKey"`
+	Height    uint64         `json:"height" msgpack:"height"`
+	BlockHash helpers.Base64 `json:"blockHash" msgpack:"blockHash"`
+	Timestamp uint64         `json:"timestamp" msgpack:"timestamp"`
+	Reward    uint64         `json:"reward" msgpack:"reward"`
+	Orphaned  bool           `json:"orphaned" msgpack:"orphaned"`
+}
+
+// ForgingStatsType is stored in StoreSettings. The forged blocks stay in Pending until they are confirmed or orphaned and after they are moved to the ledger
+type ForgingStatsType struct {
+	Addresses   map[string]*ForgingAddressStats `json:"addresses" msgpack:"addresses"`
+	Pending     []*ForgingLedgerEntry           `json:"pending" msgpack:"pending"`
+	LedgerCount uint64                          `json:"ledgerCount" msgpack:"ledgerCount"`
+	changed     bool
+	lock        sync.RWMutex
+}
+
+var ForgingStats *ForgingStatsType
+
+func (stats *ForgingStatsType) getAddress(publicKey []byte) *ForgingAddressStats {
+	addr := stats.Addresses[string(publicKey)]
+	if addr == nil {
+		addr = &ForgingAddressStats{PublicKey: publicKey}
+		stats.Addresses[string(publicKey)] = addr
+	}
+	return addr
+}
+
+// GetStakingReward returns the reward of the SCRIPT_STAKING_REWARD payload of the forging transaction
+func GetStakingReward(tx *transaction.Transaction) uint64 {
+	if tx == nil || tx.Version != transaction_type.TX_ZETHER {
+		return 0
+	}
+	for _, payload := range tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads {
+		if payload.PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING_REWARD {
+			return payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraStakingReward).Reward
+		}
+	}
+	return 0
+}
+
+// BlockForged is called after the blockchain accepted the block forged by the address
+func (stats *ForgingStatsType) BlockForged(publicKey []byte, height uint64, blockHash []byte, timestamp, reward uint64) {
+
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	addr := stats.getAddress(publicKey)
+	addr.ForgedBlocks += 1
+	addr.Rewards += reward
+	addr.LastForgedHeight = height
+
+	stats.Pending = append(stats.Pending, &ForgingLedgerEntry{publicKey, height, blockHash, timestamp, reward, false})
+
+	if err := stats.saveStats(nil); err != nil {
+		gui.GUI.Error("Error saving forging stats", err)
+	}
+	stats.updateGUI()
+}
+
+// AddEligibleTime adds the time to the addresses which were eligible to forge
+func (stats *ForgingStatsType) AddEligibleTime(publicKeys []string, seconds uint64) {
+
+	if len(publicKeys) == 0 {
+		return
+	}
+
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	for _, publicKey := range publicKeys {
+		stats.getAddress([]byte(publicKey)).EligibleSeconds += seconds
+	}
+	stats.changed = true
+}
+
+// processChainUpdate moves the pending blocks to the ledger. A block is orphaned when the hash at its height is no longer the same
+func (stats *ForgingStatsType) processChainUpdate(chainHeight uint64) error {
+
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	if len(stats.Pending) == 0 {
+		return nil
+	}
+
+	resolved := make([]*ForgingLedgerEntry, 0)
+	pending := make([]*ForgingLedgerEntry, 0, len(stats.Pending))
+
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		for _, entry := range stats.Pending {
+			if hash := reader.Get("blockHash_ByHeight" + strconv.FormatUint(entry.Height, 10)); hash != nil && !bytes.Equal(hash, entry.BlockHash) {
+				entry.Orphaned = true
+			} else if hash == nil && entry.Height < chainHeight {
+				entry.Orphaned = true
+			}
+
+			if entry.Orphaned || entry.Height+FORGING_STATS_CONFIRMATIONS < chainHeight {
+				resolved = append(resolved, entry)
+			} else {
+				pending = append(pending, entry)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(resolved) == 0 {
+		return nil
+	}
+
+	for _, entry := range resolved {
+		if entry.Orphaned {
+			addr := stats.getAddress(entry.PublicKey)
+			addr.OrphanedBlocks += 1
+			addr.OrphanedRewards += entry.Reward
+			addr.Rewards -= entry.Reward
+			gui.GUI.Warning("Forged block was orphaned", entry.Height)
+		}
+	}
+
+	stats.Pending = pending
+	if err := stats.saveStats(resolved); err != nil {
+		return err
+	}
+
+	stats.updateGUI()
+	return nil
+}
+
+// GetAddresses returns a copy of the statistics sorted by the forged blocks
+func (stats *ForgingStatsType) GetAddresses() []*ForgingAddressStats {
+
+	stats.lock.RLock()
+	defer stats.lock.RUnlock()
+
+	list := make([]*ForgingAddressStats, 0, len(stats.Addresses))
+	for _, addr := range stats.Addresses {
+		clone := *addr
+		list = append(list, &clone)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ForgedBlocks == list[j].ForgedBlocks {
+			return bytes.Compare(list[i].PublicKey, list[j].PublicKey) < 0
+		}
+		return list[i].ForgedBlocks > list[j].ForgedBlocks
+	})
+	return list
+}
+
+// GetPending returns the forged blocks which are not confirmed yet
+func (stats *ForgingStatsType) GetPending() []*ForgingLedgerEntry {
+
+	stats.lock.RLock()
+	defer stats.lock.RUnlock()
+
+	list := make([]*ForgingLedgerEntry, len(stats.Pending))
+	for i, entry := range stats.Pending {
+		clone := *entry
+		list[i] = &clone
+	}
+	return list
+}
+
+// GetLedger returns the resolved forged blocks, most recent first
+func (stats *ForgingStatsType) GetLedger(start, count uint64) (list []*ForgingLedgerEntry, total uint64, err error) {
+
+	stats.lock.RLock()
+	total = stats.LedgerCount
+	stats.lock.RUnlock()
+
+	list = make([]*ForgingLedgerEntry, 0)
+	err = store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		for i := start; i < total && uint64(len(list)) < count; i++ {
+			data := reader.Get("forgingLedger_" + strconv.FormatUint(total-1-i, 10))
+			if data == nil {
+				return errors.New("Forging ledger entry was not found")
+			}
+			entry := &ForgingLedgerEntry{}
+			if err := msgpack.Unmarshal(data, entry); err != nil {
+				return err
+			}
+			list = append(list, entry)
+		}
+		return nil
+	})
+	return
+}
+
+func (stats *ForgingStatsType) updateGUI() {
+
+	var forged, orphaned, rewards uint64
+	for _, addr := range stats.Addresses {
+		forged += addr.ForgedBlocks
+		orphaned += addr.OrphanedBlocks
+		rewards += addr.Rewards
+	}
+
+	gui.GUI.Info3Update("Addresses", strconv.Itoa(len(stats.Addresses)))
+	gui.GUI.Info3Update("Forged", strconv.FormatUint(forged, 10))
+	gui.GUI.Info3Update("Orphaned", strconv.FormatUint(orphaned, 10))
+	gui.GUI.Info3Update("Pending", strconv.Itoa(len(stats.Pending)))
+	gui.GUI.Info3Update("Rewards", strconv.FormatUint(rewards, 10))
+}
+
+// saveStats writes the resolved entries to the ledger. It must be locked before
+func (stats *ForgingStatsType) saveStats(resolved []*ForgingLedgerEntry) error {
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		for _, entry := range resolved {
+			data, err := msgpack.Marshal(entry)
+			if err != nil {
+				return err
+			}
+			writer.Put("forgingLedger_"+strconv.FormatUint(stats.LedgerCount, 10), data)
+			stats.LedgerCount += 1
+		}
+
+		data, err := msgpack.Marshal(stats)
+		if err != nil {
+			return err
+		}
+		writer.Put("forgingStats", data)
+
+		stats.changed = false
+		return nil
+	})
+}
+
+func (stats *ForgingStatsType) loadStats() error {
+	return store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		if data := reader.Get("forgingStats"); data != nil {
+			return msgpack.Unmarshal(data, stats)
+		}
+		return nil
+	})
+}
+
+// ProcessForgingStats checks the pending blocks on every new chain and saves the eligible time every minute
+func (stats *ForgingStatsType) ProcessForgingStats(updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) {
+
+	recovery.SafeGo(func() {
+
+		updateNewChainCn := updateNewChainUpdate.AddListener()
+		defer updateNewChainUpdate.RemoveChannel(updateNewChainCn)
+
+		for {
+			update, ok := <-updateNewChainCn
+			if !ok {
+				return
+			}
+			if err := stats.processChainUpdate(update.BlockHeight); err != nil {
+				gui.GUI.Error("Error processing forging stats", err)
+			}
+		}
+	})
+
+	recovery.SafeGo(func() {
+		for {
+			time.Sleep(time.Minute)
+
+			stats.lock.Lock()
+			if stats.changed {
+				if err := stats.saveStats(nil); err != nil {
+					gui.GUI.Error("Error saving forging stats", err)
+				}
+			}
+			stats.lock.Unlock()
+		}
+	})
+}
+
+func ForgingStatsInit() error {
+
+	ForgingStats = &ForgingStatsType{
+		Addresses: make(map[string]*ForgingAddressStats),
+		Pending:   make([]*ForgingLedgerEntry, 0),
+	}
+
+	if err := ForgingStats.loadStats(); err != nil {
+		return err
+	}
+	if ForgingStats.Addresses == nil {
+		ForgingStats.Addresses = make(map[string]*ForgingAddressStats)
+	}
+
+	ForgingStats.updateGUI()
+	return nil
+}
@@ -0,0 +1,160 @@
+package forging_stats
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"strconv"
+	"testing"
+)
+
+var (
+	forgingStatsTestPublicKey1 = cryptography.SHA3([]byte("PublicKey1"))
+	forgingStatsTestPublicKey2 = cryptography.SHA3([]byte("PublicKey2"))
+)
+
+func initForgingStatsTest(t *testing.T) {
+
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.NoError(t, err)
+
+	settingsDB, err := store_db_memory.CreateStoreDBMemory("settings")
+	assert.NoError(t, err)
+	store.StoreSettings = &store.Store{"settings", true, settingsDB}
+
+	blockchainDB, err := store_db_memory.CreateStoreDBMemory("blockchain")
+	assert.NoError(t, err)
+	store.StoreBlockchain = &store.Store{"blockchain", true, blockchainDB}
+
+	assert.NoError(t, ForgingStatsInit())
+}
+
+func forgingStatsTestBlockHash(height uint64) []byte {
+	return cryptography.SHA3([]byte("Block" + strconv.FormatUint(height, 10)))
+}
+
+func forgingStatsTestChain(t *testing.T, blocks map[uint64][]byte) {
+	assert.NoError(t, store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		for height, hash := range blocks {
+			writer.Put("blockHash_ByHeight"+strconv.FormatUint(height, 10), hash)
+		}
+		return nil
+	}))
+}
+
+func forgingStatsTestAddresses() map[string]*ForgingAddressStats {
+	addresses := make(map[string]*ForgingAddressStats)
+	for _, addr := range ForgingStats.GetAddresses() {
+		addresses[string(addr.PublicKey)] = addr
+	}
+	return addresses
+}
+
+func TestForgingStatsRecord(t *testing.T) {
+
+	initForgingStatsTest(t)
+
+	ForgingStats.BlockForged(forgingStatsTestPublicKey1, 10, forgingStatsTestBlockHash(10), 1000, 50)
+	ForgingStats.BlockForged(forgingStatsTestPublicKey2, 11, forgingStatsTestBlockHash(11), 1010, 60)
+	ForgingStats.BlockForged(forgingStatsTestPublicKey1, 12, forgingStatsTestBlockHash(12), 1020, 70)
+	ForgingStats.AddEligibleTime([]string{string(forgingStatsTestPublicKey1), string(forgingStatsTestPublicKey2)}, 60)
+	ForgingStats.AddEligibleTime([]string{string(forgingStatsTestPublicKey1)}, 30)
+
+	//sorted by the forged blocks
+	list := ForgingStats.GetAddresses()
+	assert.Len(t, list, 2)
+	assert.Equal(t, &ForgingAddressStats{forgingStatsTestPublicKey1, 2, 120, 0, 0, 90, 12}, list[0])
+	assert.Equal(t, &ForgingAddressStats{forgingStatsTestPublicKey2, 1, 60, 0, 0, 60, 11}, list[1])
+
+	//the copies don't change the statistics
+	list[0].ForgedBlocks = 100
+	assert.Equal(t, uint64(2), ForgingStats.GetAddresses()[0].ForgedBlocks)
+
+	pending := ForgingStats.GetPending()
+	assert.Len(t, pending, 3)
+	assert.Equal(t, &ForgingLedgerEntry{forgingStatsTestPublicKey2, 11, forgingStatsTestBlockHash(11), 1010, 60, false}, pending[1])
+
+	//the eligible time is saved only by the periodic save
+	assert.True(t, ForgingStats.changed)
+	ForgingStats.lock.Lock()
+	assert.NoError(t, ForgingStats.saveStats(nil))
+	ForgingStats.lock.Unlock()
+	assert.False(t, ForgingStats.changed)
+
+	//the statistics survive restarts
+	assert.NoError(t, ForgingStatsInit())
+	assert.Equal(t, list[1], ForgingStats.GetAddresses()[1])
+	assert.Equal(t, uint64(90), ForgingStats.GetAddresses()[0].EligibleSeconds)
+	assert.Len(t, ForgingStats.GetPending(), 3)
+}
+
+func TestForgingStatsRollback(t *testing.T) {
+
+	initForgingStatsTest(t)
+
+	ForgingStats.BlockForged(forgingStatsTestPublicKey1, 10, forgingStatsTestBlockHash(10), 1000, 50)
+	ForgingStats.BlockForged(forgingStatsTestPublicKey1, 11, forgingStatsTestBlockHash(11), 1010, 60)
+	ForgingStats.BlockForged(forgingStatsTestPublicKey2, 12, forgingStatsTestBlockHash(12), 1020, 70)
+	ForgingStats.BlockForged(forgingStatsTestPublicKey2, 13, forgingStatsTestBlockHash(13), 1030, 80)
+
+	//block 11 was replaced by a reorg and block 12 was removed
+	forgingStatsTestChain(t, map[uint64][]byte{
+		10: forgingStatsTestBlockHash(10),
+		11: cryptography.SHA3([]byte("Other")),
+		13: forgingStatsTestBlockHash(13),
+	})
+
+	//the chain is at height 12, so block 12 can still be added
+	assert.NoError(t, ForgingStats.processChainUpdate(12))
+	assert.Len(t, ForgingStats.GetPending(), 3)
+
+	assert.NoError(t, ForgingStats.processChainUpdate(14))
+
+	pending := ForgingStats.GetPending()
+	assert.Len(t, pending, 2)
+	assert.Equal(t, uint64(10), pending[0].Height)
+	assert.Equal(t, uint64(13), pending[1].Height)
+
+	addresses := forgingStatsTestAddresses()
+	assert.Equal(t, &ForgingAddressStats{forgingStatsTestPublicKey1, 2, 50, 1, 60, 0, 11}, addresses[string(forgingStatsTestPublicKey1)])
+	assert.Equal(t, &ForgingAddressStats{forgingStatsTestPublicKey2, 2, 80, 1, 70, 0, 13}, addresses[string(forgingStatsTestPublicKey2)])
+
+	ledger, total, err := ForgingStats.GetLedger(0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), total)
+	assert.Len(t, ledger, 2)
+	assert.Equal(t, &ForgingLedgerEntry{forgingStatsTestPublicKey2, 12, forgingStatsTestBlockHash(12), 1020, 70, true}, ledger[0])
+	assert.Equal(t, &ForgingLedgerEntry{forgingStatsTestPublicKey1, 11, forgingStatsTestBlockHash(11), 1010, 60, true}, ledger[1])
+
+	//the blocks are confirmed after FORGING_STATS_CONFIRMATIONS
+	assert.NoError(t, ForgingStats.processChainUpdate(10+FORGING_STATS_CONFIRMATIONS))
+	assert.Len(t, ForgingStats.GetPending(), 2)
+	assert.NoError(t, ForgingStats.processChainUpdate(13+FORGING_STATS_CONFIRMATIONS+1))
+	assert.Len(t, ForgingStats.GetPending(), 0)
+
+	addresses = forgingStatsTestAddresses()
+	assert.Equal(t, uint64(50), addresses[string(forgingStatsTestPublicKey1)].Rewards)
+	assert.Equal(t, uint64(80), addresses[string(forgingStatsTestPublicKey2)].Rewards)
+
+	//the ledger is returned from the most recent entry
+	ledger, total, err = ForgingStats.GetLedger(0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), total)
+	assert.Len(t, ledger, 4)
+	assert.Equal(t, uint64(13), ledger[0].Height)
+	assert.False(t, ledger[0].Orphaned)
+
+	ledger, _, err = ForgingStats.GetLedger(1, 2)
+	assert.NoError(t, err)
+	assert.Len(t, ledger, 2)
+	assert.Equal(t, uint64(10), ledger[0].Height)
+
+	ledger, _, err = ForgingStats.GetLedger(4, 2)
+	assert.NoError(t, err)
+	assert.Len(t, ledger, 0)
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"pandora-pay/address_balance_decryptor"
 	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/blocks/block"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/forging/forging_block_work"
+	"pandora-pay/blockchain/forging/forging_stats"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config/config_stake"
 	"pandora-pay/gui"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/helpers/generics"
@@ -28,6 +31,7 @@ type ForgingThread struct {
 	workersCreatedCn          chan []*ForgingWorkerThread
 	workersDestroyedCn        chan struct{}
 	lastPrevKernelHash        *generics.Value[[]byte]
+	publishedStakingNonces    map[string]uint64 //staking nonces already used to publish a block, used to avoid equivocation
 	createForgingTransactions func(*block_complete.BlockComplete, []byte, uint64, []*transaction.Transaction) (*transaction.Transaction, error)
 }
 
@@ -53,11 +57,17 @@ func (thread *ForgingThread) startForging() {
 		for {
 
 			s := ""
+			eligible := []string{}
 			for i := 0; i < thread.threads; i++ {
 				hashesPerSecond := atomic.SwapUint32(&thread.workers[i].hashes, 0)
 				s += strconv.FormatUint(uint64(hashesPerSecond), 10) + " "
+				thread.workers[i].eligible.Range(func(publicKeyStr string, _ bool) bool {
+					eligible = append(eligible, publicKeyStr)
+					return true
+				})
 			}
 			gui.GUI.InfoUpdate("Hashes/s", s)
+			forging_stats.ForgingStats.AddEligibleTime(eligible, 1)
 
 			time.Sleep(time.Second)
 		}
@@ -78,11 +88,18 @@ func (thread *ForgingThread) startForging() {
 				continue
 			}
 
+			//the same staking nonce can not be used to forge two different blocks, otherwise the stake would be slashed
+			if _, found := thread.publishedStakingNonces[string(solution.stakingNonce)]; found {
+				gui.GUI.Error(fmt.Errorf("Refusing to forge block %d. Staking nonce was already used", solution.blkComplete.Height))
+				continue
+			}
+
 			if newKernelHash, err = thread.publishSolution(solution); err != nil {
 				gui.GUI.Error(fmt.Errorf("Error publishing solution: %d error: %s ", solution.blkComplete.Height, err))
 			} else {
 				gui.GUI.Info(fmt.Errorf("Block was forged! %d ", solution.blkComplete.Height))
 				thread.lastPrevKernelHash.Store(newKernelHash)
+				thread.storePublishedStakingNonce(solution.stakingNonce, solution.blkComplete.Height)
 			}
 
 		}
@@ -107,6 +124,15 @@ func (thread *ForgingThread) startForging() {
 
 }
 
+func (thread *ForgingThread) storePublishedStakingNonce(stakingNonce []byte, blockHeight uint64) {
+	for key, height := range thread.publishedStakingNonces {
+		if height+config_stake.GetPendingStakeWindow(height) < blockHeight {
+			delete(thread.publishedStakingNonces, key)
+		}
+	}
+	thread.publishedStakingNonces[string(stakingNonce)] = blockHeight
+}
+
 func (thread *ForgingThread) publishSolution(solution *ForgingSolution) ([]byte, error) {
 
 	newBlk := block_complete.CreateEmptyBlockComplete()
@@ -129,6 +155,12 @@ func (thread *ForgingThread) publishSolution(solution *ForgingSolution) ([]byte,
 
 	newBlk.Block.MerkleHash = newBlk.MerkleHash()
 
+	if newBlk.Block.Version == block.BLOCK_VERSION_STAKING_SIGNATURE {
+		if err = newBlk.Block.SignStaking(solution.privateKey.Key); err != nil {
+			return nil, err
+		}
+	}
+
 	newBlk.Bloom = nil
 	if err = newBlk.BloomAll(); err != nil {
 		return nil, err
@@ -142,6 +174,10 @@ func (thread *ForgingThread) publishSolution(solution *ForgingSolution) ([]byte,
 	}
 
 	res := <-result
+	if res.Err == nil {
+		forging_stats.ForgingStats.BlockForged(solution.publicKey, newBlk.Height, newBlk.Bloom.Hash, newBlk.Timestamp, forging_stats.GetStakingReward(txStakingReward))
+	}
+
 	return res.ChainKernelHash, res.Err
 }
 
@@ -156,6 +192,7 @@ func createForgingThread(threads int, createForgingTransactions func(*block_comp
 		make(chan []*ForgingWorkerThread),
 		make(chan struct{}),
 		&generics.Value[[]byte]{},
+		make(map[string]uint64),
 		createForgingTransactions,
 	}
 }
@@ -5,16 +5,17 @@ import (
 	"encoding/binary"
 	"math/big"
 	"pandora-pay/address_balance_decryptor"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/blocks/block"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/forging/forging_block_work"
 	"pandora-pay/config"
-	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
 	"pandora-pay/cryptography/bn256"
-	"pandora-pay/cryptography/crypto"
 	"pandora-pay/gui"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"strconv"
 	"sync/atomic"
 	"time"
@@ -27,6 +28,7 @@ type ForgingSolution struct {
 	blkComplete             *block_complete.BlockComplete
 	stakingAmount           uint64
 	stakingNonce            []byte
+	privateKey              *addresses.PrivateKey
 }
 
 type ForgingWorkerThread struct {
@@ -37,6 +39,9 @@ type ForgingWorkerThread struct {
 	workerSolutionCn        chan *ForgingSolution
 	addWalletAddressCn      chan *ForgingWalletAddress
 	removeWalletAddressCn   chan string //publicKey
+	metricsHashes           *metrics.Counter
+	metricsAttempts         *metrics.Counter
+	eligible                *generics.Map[string, bool] //addresses having the minimum stake for the current work
 }
 
 type ForgingWorkerThreadAddress struct {
@@ -53,10 +58,7 @@ func (worker *ForgingWorkerThread) computeStakingAmount(threadAddr *ForgingWorke
 		if threadAddr.walletAdr.decryptedStakingBalance >= work.MinimumStake {
 
 			if !bytes.Equal(threadAddr.stakingNoncePrevChainKernelHash, work.BlkComplete.PrevKernelHash) {
-				uinput := append([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT), work.BlkComplete.PrevKernelHash[:]...)
-				uinput = append(uinput, config_coins.NATIVE_ASSET_FULL...)
-				uinput = append(uinput, strconv.Itoa(0)...)
-				u := new(bn256.G1).ScalarMult(crypto.HashToPoint(crypto.HashtoNumber(uinput)), threadAddr.walletAdr.privateKeyPoint)
+				u := new(bn256.G1).ScalarMult(block.StakingNonceBase(work.BlkComplete.PrevKernelHash), threadAddr.walletAdr.privateKeyPoint)
 				threadAddr.stakingNonce = cryptography.SHA3(u.EncodeCompressed())
 				threadAddr.stakingNoncePrevChainKernelHash = work.BlkComplete.PrevKernelHash
 			}
@@ -126,6 +128,9 @@ func (worker *ForgingWorkerThread) forge() {
 			if worker.computeStakingAmount(walletAddr, work) {
 				walletsStaked[walletAddr.walletAdr.publicKeyStr] = walletAddr
 				walletsStakedTimestamp[walletAddr.walletAdr.publicKeyStr] = timestamp
+				worker.eligible.Store(walletAddr.walletAdr.publicKeyStr, true)
+			} else {
+				worker.eligible.Delete(walletAddr.walletAdr.publicKeyStr)
 			}
 		}
 
@@ -150,6 +155,7 @@ func (worker *ForgingWorkerThread) forge() {
 			if walletAddr.walletAdr.chainHash == nil || bytes.Equal(walletAddr.walletAdr.chainHash, work.BlkComplete.PrevHash) {
 				oldDecryptedStakingBalance := walletAddr.stakingAmount
 				if worker.computeStakingAmount(walletAddr, work) {
+					worker.eligible.Store(walletAddr.walletAdr.publicKeyStr, true)
 					if !walletsStakedUsed[walletAddr.walletAdr.publicKeyStr] || walletAddr.stakingAmount > oldDecryptedStakingBalance {
 						walletsStaked[walletAddr.walletAdr.publicKeyStr] = walletAddr
 						walletsStakedTimestamp[walletAddr.walletAdr.publicKeyStr] = timestamp
@@ -157,6 +163,7 @@ func (worker *ForgingWorkerThread) forge() {
 					}
 				} else {
 					delete(walletsStaked, walletAddr.walletAdr.publicKeyStr)
+					worker.eligible.Delete(walletAddr.walletAdr.publicKeyStr)
 				}
 			}
 		}
@@ -169,6 +176,7 @@ func (worker *ForgingWorkerThread) forge() {
 			delete(wallets, publicKeyStr)
 			delete(walletsStaked, publicKeyStr)
 			delete(walletsStakedTimestamp, publicKeyStr)
+			worker.eligible.Delete(publicKeyStr)
 		}
 		validateWork()
 	}
@@ -251,6 +259,7 @@ func (worker *ForgingWorkerThread) forge() {
 							work.BlkComplete,
 							generics.Max(generics.Min(requireStakingAmount.Uint64()+1, address.stakingAmount), work.MinimumStake),
 							address.stakingNonce,
+							address.walletAdr.privateKey,
 						}
 
 						select {
@@ -268,6 +277,7 @@ func (worker *ForgingWorkerThread) forge() {
 								goto done
 							}
 						case worker.workerSolutionCn <- solution:
+							worker.metricsAttempts.Inc()
 							delete(walletsStaked, key)
 							walletsStakedUsed[key] = true
 						}
@@ -286,6 +296,7 @@ func (worker *ForgingWorkerThread) forge() {
 			return false
 		}()
 		atomic.AddUint32(&worker.hashes, uint32(hashes))
+		worker.metricsHashes.Add(uint64(hashes))
 
 		if hashes == 0 && !hasNewWork {
 			time.Sleep(time.Duration(((timeLimitMs/1000+1)*1000 - timeLimitMs) * 1000000))
@@ -303,5 +314,8 @@ func createForgingWorkerThread(index int, workerSolutionCn chan *ForgingSolution
 		workerSolutionCn:        workerSolutionCn,
 		addWalletAddressCn:      make(chan *ForgingWalletAddress),
 		removeWalletAddressCn:   make(chan string),
+		metricsHashes:           metricsForgingHashes.WithLabel(strconv.Itoa(index)),
+		metricsAttempts:         metricsForgingAttempts.WithLabel(strconv.Itoa(index)),
+		eligible:                &generics.Map[string, bool]{},
 	}
 }
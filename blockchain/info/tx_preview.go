@@ -56,6 +56,16 @@ func CreateTxPreviewFromTx(tx *transaction.Transaction) (*TxPreview, error) {
 				txBaseExtra.PayloadIndex,
 				txBaseExtra.Resolution,
 			}
+		case transaction_simple.SCRIPT_SLASHING_EVIDENCE:
+
+			txBaseExtra := txBase.Extra.(*transaction_simple_extra.TransactionSimpleExtraSlashingEvidence)
+
+			previewBase.Extra = &TxPreviewSimpleExtraSlashingEvidence{
+				txBaseExtra.Block1.Height,
+				txBaseExtra.Block1.StakingNonce,
+				txBaseExtra.Block1.Bloom.Hash,
+				txBaseExtra.Block2.Bloom.Hash,
+			}
 		}
 
 		base = previewBase
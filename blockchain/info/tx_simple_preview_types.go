@@ -11,6 +11,13 @@ type TxPreviewSimpleExtraResolutionConditionalPayment struct {
 	Resolution   bool   `json:"resolution" msgpack:"resolution"`
 }
 
+type TxPreviewSimpleExtraSlashingEvidence struct {
+	Height       uint64 `json:"height" msgpack:"height"`
+	StakingNonce []byte `json:"stakingNonce" msgpack:"stakingNonce"`
+	Hash1        []byte `json:"hash1" msgpack:"hash1"`
+	Hash2        []byte `json:"hash2" msgpack:"hash2"`
+}
+
 type TxPreviewSimple struct {
 	TxScript    transaction_simple.ScriptType           `json:"txScript" msgpack:"txScript"`
 	DataVersion transaction_data.TransactionDataVersion `json:"dataVersion" msgpack:"dataVersion"`
@@ -69,6 +69,11 @@ type json_Only_TransactionSimpleExtraResolutionConditionalPayment struct {
 	Signatures         [][]byte `json:"signatures"`
 }
 
+type json_Only_TransactionSimpleExtraSlashingEvidence struct {
+	Block1 []byte `json:"block1"`
+	Block2 []byte `json:"block2"`
+}
+
 type json_Only_TransactionZether struct {
 	ChainHeight     uint64                          `json:"chainHeight"  msgpack:"chainHeight"`
 	ChainKernelHash []byte                          `json:"chainKernelHash"  msgpack:"chainKernelHash"`
@@ -193,6 +198,12 @@ func marshalJSON(tx *Transaction, marshal func(any) ([]byte, error)) ([]byte, er
 				extra.MultisigPublicKeys,
 				extra.Signatures,
 			}
+		case transaction_simple.SCRIPT_SLASHING_EVIDENCE:
+			extra := base.Extra.(*transaction_simple_extra.TransactionSimpleExtraSlashingEvidence)
+			simpleJson.Extra = json_Only_TransactionSimpleExtraSlashingEvidence{
+				extra.Block1.SerializeManualToBytes(),
+				extra.Block2.SerializeManualToBytes(),
+			}
 		default:
 			return nil, errors.New("Invalid simple.TxScript")
 		}
@@ -410,6 +421,17 @@ func (tx *Transaction) UnmarshalJSON(data []byte) (err error) {
 				extraJson.MultisigPublicKeys,
 				extraJson.Signatures,
 			}
+		case transaction_simple.SCRIPT_SLASHING_EVIDENCE:
+			extraJson := &json_Only_TransactionSimpleExtraSlashingEvidence{}
+			if err = json.Unmarshal(data, extraJson); err != nil {
+				return
+			}
+
+			extra := &transaction_simple_extra.TransactionSimpleExtraSlashingEvidence{}
+			if err = extra.Deserialize(advanced_buffers.NewBufferReader(append(extraJson.Block1, extraJson.Block2...))); err != nil {
+				return
+			}
+			base.Extra = extra
 		default:
 			return errors.New("Invalid json Simple TxScript")
 		}
@@ -0,0 +1,157 @@
+package transaction_simple_extra
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/blocks/block/difficulty"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
+	"pandora-pay/config"
+	"pandora-pay/config/config_stake"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/msgpack"
+	"strconv"
+)
+
+// TransactionSimpleExtraSlashingEvidence proves that the same staking key forged two different blocks on top of the same parent
+// The staking nonce depends only on the prev kernel hash and the staker private key, hence two different blocks with the same height and staking nonce are an equivocation
+// Both blocks must be signed with the staking key, otherwise anybody could forge a second header reusing the published staking nonce
+type TransactionSimpleExtraSlashingEvidence struct {
+	TransactionSimpleExtraInterface
+	Block1 *block.Block
+	Block2 *block.Block
+}
+
+func (this *TransactionSimpleExtraSlashingEvidence) loadTarget(blockHeight uint64, dataStorage *data_storage.DataStorage) (*big.Int, error) {
+
+	data := dataStorage.DBTx.Get("blockchainInfo_" + strconv.FormatUint(blockHeight, 10))
+	if data == nil {
+		return nil, errors.New("Chain info was not found for the evidence height")
+	}
+
+	chainInfo := &struct {
+		Target *big.Int `msgpack:"target"`
+	}{}
+	if err := msgpack.Unmarshal(data, chainInfo); err != nil {
+		return nil, err
+	}
+	if chainInfo.Target == nil {
+		return nil, errors.New("Chain info target is missing")
+	}
+
+	return chainInfo.Target, nil
+}
+
+func (this *TransactionSimpleExtraSlashingEvidence) IncludeTransactionVin0(blockHeight uint64, plainAcc *plain_account.PlainAccount, dataStorage *data_storage.DataStorage) (err error) {
+
+	height := this.Block1.Height
+
+	if blockHeight < config.NETWORK_SELECTED_SLASHING_ACTIVATION_HEIGHT || height < config.NETWORK_SELECTED_SLASHING_ACTIVATION_HEIGHT {
+		return errors.New("Slashing evidence is not activated yet")
+	}
+	if height >= blockHeight {
+		return errors.New("Slashing evidence must be for a previous block")
+	}
+	if blockHeight >= height+config_stake.GetPendingStakeWindow(height) {
+		return errors.New("Slashing evidence expired")
+	}
+
+	//the stake can be confiscated only using the staking tx of the block included in the chain
+	hash := dataStorage.DBTx.Get("blockHash_ByHeight" + strconv.FormatUint(height, 10))
+	if !bytes.Equal(hash, this.Block1.Bloom.Hash) && !bytes.Equal(hash, this.Block2.Bloom.Hash) {
+		return errors.New("Slashing evidence blocks were not included in the chain")
+	}
+
+	prevKernelHash := dataStorage.DBTx.Get("blockKernelHash_ByHeight" + strconv.FormatUint(height-1, 10))
+	if prevKernelHash == nil {
+		return errors.New("Prev Kernel Hash of the evidence was not found")
+	}
+
+	target, err := this.loadTarget(height, dataStorage)
+	if err != nil {
+		return
+	}
+
+	for _, blk := range []*block.Block{this.Block1, this.Block2} {
+		if !bytes.Equal(blk.PrevKernelHash, prevKernelHash) {
+			return errors.New("Slashing evidence block is not forged on top of the chain")
+		}
+		if blk.StakingAmount < config_stake.GetRequiredStake(height) {
+			return errors.New("Slashing evidence block staked amount is not enough")
+		}
+		if !difficulty.CheckKernelHashBig(blk.Bloom.KernelHashStaked, target) {
+			return errors.New("Slashing evidence block KernelHash Difficulty is not met")
+		}
+	}
+
+	var exists bool
+	if exists, err = dataStorage.Slashings.Exists(string(this.Block1.StakingNonce)); err != nil {
+		return
+	}
+	if exists {
+		return errors.New("Staking nonce was already slashed")
+	}
+
+	_, err = dataStorage.Slashings.CreateNewSlashing(this.Block1.StakingNonce, height)
+	return
+}
+
+func (this *TransactionSimpleExtraSlashingEvidence) Validate(fee uint64) (err error) {
+
+	if fee == 0 {
+		return errors.New("Fee should be greater than zero")
+	}
+	if this.Block1 == nil || this.Block2 == nil {
+		return errors.New("Slashing evidence requires two blocks")
+	}
+
+	for _, blk := range []*block.Block{this.Block1, this.Block2} {
+		if err = blk.Validate(); err != nil {
+			return
+		}
+		if err = blk.BloomNow(); err != nil {
+			return
+		}
+		if err = blk.VerifyStakingSignature(); err != nil {
+			return
+		}
+	}
+
+	if this.Block1.Height == 0 {
+		return errors.New("Genesis can not be slashed")
+	}
+	if this.Block1.Height != this.Block2.Height {
+		return errors.New("Slashing evidence blocks have different heights")
+	}
+	if !bytes.Equal(this.Block1.StakingNonce, this.Block2.StakingNonce) {
+		return errors.New("Slashing evidence blocks have different staking nonces")
+	}
+	if !bytes.Equal(this.Block1.StakingPoint, this.Block2.StakingPoint) {
+		return errors.New("Slashing evidence blocks have different staking points")
+	}
+	//the hash includes the randomized signature, hence the same block signed twice has different hashes
+	if bytes.Equal(this.Block1.SerializeForSigning(), this.Block2.SerializeForSigning()) {
+		return errors.New("Slashing evidence blocks are identical")
+	}
+
+	return
+}
+
+func (this *TransactionSimpleExtraSlashingEvidence) Serialize(w *advanced_buffers.BufferWriter, inclSignature bool) {
+	w.Write(this.Block1.SerializeManualToBytes())
+	w.Write(this.Block2.SerializeManualToBytes())
+}
+
+func (this *TransactionSimpleExtraSlashingEvidence) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+	this.Block1 = block.CreateEmptyBlock()
+	if err = this.Block1.Deserialize(r); err != nil {
+		return
+	}
+	this.Block2 = block.CreateEmptyBlock()
+	if err = this.Block2.Deserialize(r); err != nil {
+		return
+	}
+	return
+}
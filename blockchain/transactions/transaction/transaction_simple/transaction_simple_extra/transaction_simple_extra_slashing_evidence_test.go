@@ -0,0 +1,156 @@
+package transaction_simple_extra
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/config"
+	"pandora-pay/config/config_stake"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"strconv"
+	"testing"
+)
+
+const slashingEvidenceTestHeight = uint64(10)
+
+var slashingEvidenceTestPrevKernelHash = cryptography.SHA3([]byte("PrevKernelHash"))
+
+func createSlashingEvidenceTestBlock(t *testing.T, privateKey *addresses.PrivateKey, height, timestamp uint64) *block.Block {
+
+	blk := &block.Block{
+		BlockHeader:    &block.BlockHeader{Version: block.BLOCK_VERSION_STAKING_SIGNATURE, Height: height},
+		MerkleHash:     cryptography.SHA3([]byte("MerkleHash")),
+		PrevHash:       cryptography.SHA3([]byte("PrevHash")),
+		PrevKernelHash: slashingEvidenceTestPrevKernelHash,
+		Timestamp:      timestamp,
+		StakingAmount:  config_stake.GetRequiredStake(height),
+	}
+
+	stakingPoint := new(bn256.G1).ScalarMult(block.StakingNonceBase(blk.PrevKernelHash), new(crypto.BNRed).SetBytes(privateKey.Key).BigInt())
+	blk.StakingNonce = cryptography.SHA3(stakingPoint.EncodeCompressed())
+
+	assert.NoError(t, blk.SignStaking(privateKey.Key))
+	return blk
+}
+
+func TestSlashingEvidenceValidate(t *testing.T) {
+
+	privateKey := addresses.GenerateNewPrivateKey()
+	otherPrivateKey := addresses.GenerateNewPrivateKey()
+
+	for _, test := range []struct {
+		name   string
+		fee    uint64
+		create func() (*block.Block, *block.Block)
+		valid  bool
+	}{
+		{"equivocation", 1, func() (*block.Block, *block.Block) {
+			return createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1000), createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1001)
+		}, true},
+		{"no fee", 0, func() (*block.Block, *block.Block) {
+			return createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1000), createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1001)
+		}, false},
+		{"missing block", 1, func() (*block.Block, *block.Block) {
+			return createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1000), nil
+		}, false},
+		{"different heights", 1, func() (*block.Block, *block.Block) {
+			return createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1000), createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight+1, 1000)
+		}, false},
+		{"different nonces", 1, func() (*block.Block, *block.Block) {
+			return createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1000), createSlashingEvidenceTestBlock(t, otherPrivateKey, slashingEvidenceTestHeight, 1001)
+		}, false},
+		{"equal hashes", 1, func() (*block.Block, *block.Block) {
+			return createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1000), createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1000)
+		}, false},
+		{"bad signature", 1, func() (*block.Block, *block.Block) {
+			blk2 := createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1001)
+			blk2.StakingSignature[len(blk2.StakingSignature)-1] ^= 1
+			return createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1000), blk2
+		}, false},
+		{"reused staking nonce", 1, func() (*block.Block, *block.Block) {
+			//anybody can copy the published staking nonce, but only the staker can sign with it
+			blk2 := createSlashingEvidenceTestBlock(t, otherPrivateKey, slashingEvidenceTestHeight, 1001)
+			blk1 := createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1000)
+			blk2.StakingNonce = blk1.StakingNonce
+			return blk1, blk2
+		}, false},
+		{"genesis", 1, func() (*block.Block, *block.Block) {
+			return createSlashingEvidenceTestBlock(t, privateKey, 0, 1000), createSlashingEvidenceTestBlock(t, privateKey, 0, 1001)
+		}, false},
+	} {
+		blk1, blk2 := test.create()
+		evidence := &TransactionSimpleExtraSlashingEvidence{nil, blk1, blk2}
+		if test.valid {
+			assert.NoError(t, evidence.Validate(test.fee), test.name)
+		} else {
+			assert.Error(t, evidence.Validate(test.fee), test.name)
+		}
+	}
+}
+
+func TestSlashingEvidenceIncludeTransactionVin0(t *testing.T) {
+
+	activationHeight := config.NETWORK_SELECTED_SLASHING_ACTIVATION_HEIGHT
+	config.NETWORK_SELECTED_SLASHING_ACTIVATION_HEIGHT = 0
+	defer func() {
+		config.NETWORK_SELECTED_SLASHING_ACTIVATION_HEIGHT = activationHeight
+	}()
+
+	privateKey := addresses.GenerateNewPrivateKey()
+	otherPrivateKey := addresses.GenerateNewPrivateKey()
+
+	createEvidence := func(privateKey *addresses.PrivateKey) *TransactionSimpleExtraSlashingEvidence {
+		evidence := &TransactionSimpleExtraSlashingEvidence{nil, createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1000), createSlashingEvidenceTestBlock(t, privateKey, slashingEvidenceTestHeight, 1001)}
+		assert.NoError(t, evidence.Validate(1))
+		return evidence
+	}
+
+	evidence := createEvidence(privateKey)
+	otherEvidence := createEvidence(otherPrivateKey)
+
+	db, err := store_db_memory.CreateStoreDBMemory("slashing")
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+
+		chainInfo, err := msgpack.Marshal(&struct {
+			Target *big.Int `msgpack:"target"`
+		}{config.BIG_INT_MAX_256})
+		if err != nil {
+			return err
+		}
+
+		dbTx.Put("blockHash_ByHeight"+strconv.FormatUint(slashingEvidenceTestHeight, 10), evidence.Block1.Bloom.Hash)
+		dbTx.Put("blockKernelHash_ByHeight"+strconv.FormatUint(slashingEvidenceTestHeight-1, 10), slashingEvidenceTestPrevKernelHash)
+		dbTx.Put("blockchainInfo_"+strconv.FormatUint(slashingEvidenceTestHeight, 10), chainInfo)
+
+		dataStorage := data_storage.NewDataStorage(dbTx)
+
+		assert.Error(t, evidence.IncludeTransactionVin0(slashingEvidenceTestHeight, nil, dataStorage), "evidence must be for a previous block")
+		assert.Error(t, evidence.IncludeTransactionVin0(slashingEvidenceTestHeight+config_stake.GetPendingStakeWindow(slashingEvidenceTestHeight), nil, dataStorage), "evidence expired")
+
+		assert.NoError(t, evidence.IncludeTransactionVin0(slashingEvidenceTestHeight+1, nil, dataStorage))
+
+		slash, err := dataStorage.Slashings.Get(string(evidence.Block1.StakingNonce))
+		assert.NoError(t, err)
+		assert.Equal(t, slashingEvidenceTestHeight, slash.Height)
+
+		//the same staking nonce can not be slashed twice, not even using other blocks
+		assert.Error(t, evidence.IncludeTransactionVin0(slashingEvidenceTestHeight+2, nil, dataStorage), "already slashed")
+		assert.Error(t, createEvidence(privateKey).IncludeTransactionVin0(slashingEvidenceTestHeight+2, nil, dataStorage), "already slashed")
+
+		//the penalty requires the staking tx of the block included in the chain
+		assert.Error(t, otherEvidence.IncludeTransactionVin0(slashingEvidenceTestHeight+1, nil, dataStorage), "not included")
+		dbTx.Put("blockHash_ByHeight"+strconv.FormatUint(slashingEvidenceTestHeight, 10), otherEvidence.Block2.Bloom.Hash)
+		assert.NoError(t, otherEvidence.IncludeTransactionVin0(slashingEvidenceTestHeight+1, nil, dataStorage))
+
+		return nil
+	}))
+}
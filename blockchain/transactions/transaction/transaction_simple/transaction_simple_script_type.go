@@ -5,6 +5,7 @@ type ScriptType uint64
 const (
 	SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY ScriptType = iota
 	SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT
+	SCRIPT_SLASHING_EVIDENCE
 )
 
 func (t ScriptType) String() string {
@@ -13,6 +14,8 @@ func (t ScriptType) String() string {
 		return "SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY"
 	case SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
 		return "SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT"
+	case SCRIPT_SLASHING_EVIDENCE:
+		return "SCRIPT_SLASHING_EVIDENCE"
 	default:
 		return "Unknown ScriptType"
 	}
@@ -0,0 +1,277 @@
+package transaction_zether_payment_proof
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"strings"
+)
+
+const PAYMENT_PROOF_PREFIX = "PAYPROOF"
+const PAYMENT_PROOF_VERSION = uint64(0)
+
+// PaymentProof proves that the commitment C[RecipientIndex] of the payload is crediting exactly Amount.
+// C[k] - G*Amount = Publickeylist[k]*r and D = G*r, so (G, Publickeylist[k], D, C[k] - G*Amount) must be a Diffie-Hellman tuple.
+// The sender knows r, the recipient knows the private key. A Chaum-Pedersen proof shows the tuple without revealing any of them
+type PaymentProof struct {
+	Version        uint64
+	Type           PaymentProofType
+	TxHash         []byte
+	PayloadIndex   uint64
+	RecipientIndex uint64
+	Amount         uint64
+	Challenge      *big.Int
+	Response       *big.Int
+}
+
+type PaymentProofOutput struct {
+	Type         string         `json:"type" msgpack:"type"`
+	TxHash       helpers.Base64 `json:"txHash" msgpack:"txHash"`
+	PayloadIndex uint64         `json:"payloadIndex" msgpack:"payloadIndex"`
+	Recipient    string         `json:"recipient" msgpack:"recipient"`
+	Amount       uint64         `json:"amount" msgpack:"amount"`
+	Asset        helpers.Base64 `json:"asset" msgpack:"asset"`
+}
+
+// bases returns base1, point1, base2, point2 such that point1 = base1*witness and point2 = base2*witness
+func (proof *PaymentProof) bases(payload *transaction_zether_payload.TransactionZetherPayload) (*bn256.G1, *bn256.G1, *bn256.G1, *bn256.G1, error) {
+
+	statement := payload.Statement
+
+	if proof.RecipientIndex >= uint64(len(statement.Publickeylist)) || proof.RecipientIndex >= uint64(len(statement.C)) {
+		return nil, nil, nil, nil, errors.New("Recipient index is invalid")
+	}
+	if (proof.RecipientIndex%2 == 0) == payload.Parity {
+		return nil, nil, nil, nil, errors.New("Recipient index is not matching the parity")
+	}
+	if statement.D == nil {
+		return nil, nil, nil, nil, errors.New("Statement is invalid")
+	}
+
+	publicKey := statement.Publickeylist[proof.RecipientIndex]
+
+	x := new(bn256.G1).ScalarMult(crypto.G, new(big.Int).Neg(new(big.Int).SetUint64(proof.Amount)))
+	x.Add(new(bn256.G1).Set(x), statement.C[proof.RecipientIndex])
+
+	switch proof.Type {
+	case PAYMENT_PROOF_SENDER:
+		return crypto.G, statement.D, publicKey, x, nil
+	case PAYMENT_PROOF_RECIPIENT:
+		return crypto.G, publicKey, statement.D, x, nil
+	default:
+		return nil, nil, nil, nil, errors.New("Invalid Payment Proof Type")
+	}
+}
+
+func (proof *PaymentProof) computeChallenge(base1, point1, base2, point2, r1, r2 *bn256.G1) *big.Int {
+
+	w := advanced_buffers.NewBufferWriter()
+	w.Write([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT + PAYMENT_PROOF_PREFIX))
+	w.WriteUvarint(proof.Version)
+	w.WriteByte(byte(proof.Type))
+	w.Write(proof.TxHash)
+	w.WriteUvarint(proof.PayloadIndex)
+	w.WriteUvarint(proof.RecipientIndex)
+	w.WriteUvarint(proof.Amount)
+	for _, point := range []*bn256.G1{base1, point1, base2, point2, r1, r2} {
+		w.Write(point.EncodeCompressed())
+	}
+
+	return crypto.ReducedHash(w.Bytes())
+}
+
+func getPayload(tx *transaction.Transaction, txHash []byte, payloadIndex uint64) (*transaction_zether_payload.TransactionZetherPayload, error) {
+
+	if tx == nil || tx.Version != transaction_type.TX_ZETHER {
+		return nil, errors.New("Only zether transactions have payment proofs")
+	}
+	if !bytes.Equal(tx.HashManual(), txHash) {
+		return nil, errors.New("Transaction is not matching the proof")
+	}
+
+	txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+	if payloadIndex >= uint64(len(txBase.Payloads)) {
+		return nil, errors.New("Payload index is invalid")
+	}
+
+	return txBase.Payloads[payloadIndex], nil
+}
+
+// CreatePaymentProof proves the payment using either r of the payload for PAYMENT_PROOF_SENDER or the private key of the recipient for PAYMENT_PROOF_RECIPIENT
+func CreatePaymentProof(tx *transaction.Transaction, proofType PaymentProofType, payloadIndex, recipientIndex int, amount uint64, witness *big.Int) (*PaymentProof, error) {
+
+	if payloadIndex < 0 || recipientIndex < 0 {
+		return nil, errors.New("Indexes are invalid")
+	}
+
+	proof := &PaymentProof{
+		Version:        PAYMENT_PROOF_VERSION,
+		Type:           proofType,
+		TxHash:         tx.HashManual(),
+		PayloadIndex:   uint64(payloadIndex),
+		RecipientIndex: uint64(recipientIndex),
+		Amount:         amount,
+	}
+
+	payload, err := getPayload(tx, proof.TxHash, proof.PayloadIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	base1, point1, base2, point2, err := proof.bases(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if new(bn256.G1).ScalarMult(base1, witness).String() != point1.String() || new(bn256.G1).ScalarMult(base2, witness).String() != point2.String() {
+		return nil, errors.New("Witness is not matching the statement")
+	}
+
+	k := crypto.RandomScalarFixed()
+	proof.Challenge = proof.computeChallenge(base1, point1, base2, point2, new(bn256.G1).ScalarMult(base1, k), new(bn256.G1).ScalarMult(base2, k))
+
+	proof.Response = new(big.Int).Mul(proof.Challenge, witness)
+	proof.Response.Sub(k, proof.Response)
+	proof.Response.Mod(proof.Response, bn256.Order)
+
+	return proof, nil
+}
+
+// Verify checks the proof against the statement of the transaction
+func (proof *PaymentProof) Verify(tx *transaction.Transaction) (*PaymentProofOutput, error) {
+
+	if proof.Version != PAYMENT_PROOF_VERSION {
+		return nil, errors.New("Invalid Payment Proof Version")
+	}
+	if proof.Challenge == nil || proof.Response == nil {
+		return nil, errors.New("Payment Proof is incomplete")
+	}
+
+	payload, err := getPayload(tx, proof.TxHash, proof.PayloadIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	base1, point1, base2, point2, err := proof.bases(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	r1 := new(bn256.G1).Add(new(bn256.G1).ScalarMult(base1, proof.Response), new(bn256.G1).ScalarMult(point1, proof.Challenge))
+	r2 := new(bn256.G1).Add(new(bn256.G1).ScalarMult(base2, proof.Response), new(bn256.G1).ScalarMult(point2, proof.Challenge))
+
+	if proof.computeChallenge(base1, point1, base2, point2, r1, r2).Cmp(proof.Challenge) != 0 {
+		return nil, errors.New("Payment Proof is invalid")
+	}
+
+	recipient, err := addresses.CreateAddr(payload.Statement.Publickeylist[proof.RecipientIndex].EncodeCompressed(), false, nil, nil, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaymentProofOutput{
+		Type:         proof.Type.String(),
+		TxHash:       proof.TxHash,
+		PayloadIndex: proof.PayloadIndex,
+		Recipient:    recipient.EncodeAddr(),
+		Amount:       proof.Amount,
+		Asset:        payload.Asset,
+	}, nil
+}
+
+func (proof *PaymentProof) Serialize(w *advanced_buffers.BufferWriter) {
+	w.WriteUvarint(proof.Version)
+	w.WriteByte(byte(proof.Type))
+	w.Write(proof.TxHash)
+	w.WriteUvarint(proof.PayloadIndex)
+	w.WriteUvarint(proof.RecipientIndex)
+	w.WriteUvarint(proof.Amount)
+	w.Write(crypto.ConvertBigIntToByte(proof.Challenge))
+	w.Write(crypto.ConvertBigIntToByte(proof.Response))
+}
+
+func (proof *PaymentProof) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+
+	if proof.Version, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	if proof.Version != PAYMENT_PROOF_VERSION {
+		return errors.New("Invalid Payment Proof Version")
+	}
+
+	var n byte
+	if n, err = r.ReadByte(); err != nil {
+		return
+	}
+	proof.Type = PaymentProofType(n)
+
+	switch proof.Type {
+	case PAYMENT_PROOF_SENDER, PAYMENT_PROOF_RECIPIENT:
+	default:
+		return errors.New("Invalid Payment Proof Type")
+	}
+
+	if proof.TxHash, err = r.ReadBytes(cryptography.HashSize); err != nil {
+		return
+	}
+	if proof.PayloadIndex, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	if proof.RecipientIndex, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	if proof.Amount, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	if proof.Challenge, err = r.ReadBigInt(); err != nil {
+		return
+	}
+	if proof.Response, err = r.ReadBigInt(); err != nil {
+		return
+	}
+
+	return
+}
+
+// Encode returns the portable format of the proof
+func (proof *PaymentProof) Encode() string {
+	w := advanced_buffers.NewBufferWriter()
+	proof.Serialize(w)
+	return PAYMENT_PROOF_PREFIX + base64.RawURLEncoding.EncodeToString(w.Bytes())
+}
+
+func DecodePaymentProof(input string) (*PaymentProof, error) {
+
+	input = strings.TrimSpace(input)
+	if !strings.HasPrefix(input, PAYMENT_PROOF_PREFIX) {
+		return nil, errors.New("Invalid Payment Proof prefix")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(input[len(PAYMENT_PROOF_PREFIX):])
+	if err != nil {
+		return nil, err
+	}
+
+	r := advanced_buffers.NewBufferReader(data)
+
+	proof := &PaymentProof{}
+	if err = proof.Deserialize(r); err != nil {
+		return nil, err
+	}
+	if r.Position != len(data) {
+		return nil, errors.New("Payment Proof has extra bytes")
+	}
+
+	return proof, nil
+}
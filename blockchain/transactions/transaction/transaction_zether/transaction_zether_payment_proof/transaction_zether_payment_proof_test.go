@@ -0,0 +1,236 @@
+package transaction_zether_payment_proof
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"pandora-pay/txs_builder/wizard"
+	"testing"
+)
+
+func TestPaymentProofEncode(t *testing.T) {
+
+	proof := &PaymentProof{
+		PAYMENT_PROOF_VERSION,
+		PAYMENT_PROOF_RECIPIENT,
+		helpers.RandomBytes(32),
+		2,
+		5,
+		123456789,
+		crypto.RandomScalarFixed(),
+		crypto.RandomScalarFixed(),
+	}
+
+	encoded := proof.Encode()
+	assert.True(t, len(encoded) > len(PAYMENT_PROOF_PREFIX))
+
+	decoded, err := DecodePaymentProof(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, proof.Type, decoded.Type)
+	assert.Equal(t, proof.TxHash, decoded.TxHash)
+	assert.Equal(t, proof.PayloadIndex, decoded.PayloadIndex)
+	assert.Equal(t, proof.RecipientIndex, decoded.RecipientIndex)
+	assert.Equal(t, proof.Amount, decoded.Amount)
+	assert.Equal(t, 0, proof.Challenge.Cmp(decoded.Challenge))
+	assert.Equal(t, 0, proof.Response.Cmp(decoded.Response))
+
+	_, err = DecodePaymentProof(encoded[1:])
+	assert.Error(t, err)
+
+	_, err = DecodePaymentProof(encoded + "AA")
+	assert.Error(t, err)
+}
+
+type paymentProofTestTransfer struct {
+	recipientPrivateKey *addresses.PrivateKey
+	recipientPublicKey  []byte
+	amount              uint64
+}
+
+func createPaymentProofTestTx(t *testing.T, count int) (*transaction.Transaction, *addresses.PrivateKey, []*paymentProofTestTransfer) {
+
+	senderPrivateKey := addresses.GenerateNewPrivateKey()
+	senderAddress, err := senderPrivateKey.GenerateAddress(false, nil, true, nil, 0, nil)
+	assert.NoError(t, err)
+
+	amount := uint64(1000000)
+
+	ringSize := 4
+	emap := map[string]map[string][]byte{config_coins.NATIVE_ASSET_FULL_STRING: {}}
+	ringsSenders := make([][]*bn256.G1, count)
+	ringsReceivers := make([][]*bn256.G1, count)
+	publicKeyIndexes := make(map[string]*wizard.WizardZetherPublicKeyIndex)
+	fees := make([]*wizard.WizardTransactionFee, count)
+	transfers := make([]*wizard.WizardZetherTransfer, count)
+	out := make([]*paymentProofTestTransfer, count)
+
+	addMember := func(addr *addresses.Address, balance uint64) *bn256.G1 {
+		point, err := addr.GetPoint()
+		assert.NoError(t, err)
+		publicKeyIndexes[string(addr.PublicKey)] = &wizard.WizardZetherPublicKeyIndex{false, 0, false, nil, addr.Registration}
+		emap[config_coins.NATIVE_ASSET_FULL_STRING][point.G1().String()] = crypto.ConstructElGamal(point.G1(), crypto.ElGamal_BASE_G).Plus(new(big.Int).SetUint64(balance)).Serialize()
+		return point.G1()
+	}
+
+	senderPoint := addMember(senderAddress, amount)
+
+	for i := range transfers {
+
+		recipientPrivateKey := addresses.GenerateNewPrivateKey()
+		recipientAddress, err := recipientPrivateKey.GenerateAddress(false, nil, true, nil, 0, nil)
+		assert.NoError(t, err)
+
+		out[i] = &paymentProofTestTransfer{recipientPrivateKey, recipientAddress.PublicKey, uint64(1000 + i)}
+
+		transfers[i] = &wizard.WizardZetherTransfer{
+			Asset:                  config_coins.NATIVE_ASSET_FULL,
+			SenderPrivateKey:       senderPrivateKey.Key,
+			SenderDecryptedBalance: amount,
+			Recipient:              recipientAddress.EncodeAddr(),
+			Amount:                 out[i].amount,
+			Data:                   &wizard.WizardTransactionData{[]byte{}, false},
+			WitnessIndexes:         helpers.ShuffleArray_for_Zether(ringSize),
+		}
+		amount -= out[i].amount
+
+		ringsSenders[i] = []*bn256.G1{senderPoint, nil}
+		ringsReceivers[i] = []*bn256.G1{addMember(recipientAddress, 0), nil}
+
+		for c := 0; c <= 1; c++ {
+			ringMemberAddress, err := addresses.GenerateNewPrivateKey().GenerateAddress(false, nil, true, nil, 0, nil)
+			assert.NoError(t, err)
+			if c == 0 {
+				ringsSenders[i][1] = addMember(ringMemberAddress, 0)
+			} else {
+				ringsReceivers[i][1] = addMember(ringMemberAddress, 0)
+			}
+		}
+
+		fees[i] = &wizard.WizardTransactionFee{0, 0, 0, false}
+	}
+
+	tx, err := wizard.CreateZetherTx(transfers, emap, map[string]bool{}, ringsSenders, ringsReceivers, 0, helpers.RandomBytes(32), publicKeyIndexes, fees, context.Background(), func(status string) {})
+	assert.NoError(t, err)
+	assert.NoError(t, tx.BloomAll())
+
+	return tx, senderPrivateKey, out
+}
+
+func getPaymentProofTestRecipientIndex(t *testing.T, tx *transaction.Transaction, payloadIndex int, publicKey []byte) int {
+	for k, it := range tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads[payloadIndex].Statement.Publickeylist {
+		if bytes.Equal(it.EncodeCompressed(), publicKey) {
+			return k
+		}
+	}
+	assert.Fail(t, "recipient is not part of the ring")
+	return -1
+}
+
+// getPaymentProofTestR computes r of the payload the same way the wizard does
+func getPaymentProofTestR(tx *transaction.Transaction, payloadIndex int, senderPrivateKey *addresses.PrivateKey) *big.Int {
+
+	txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+
+	rinputs := append([]byte{}, txBase.ChainKernelHash...)
+	for _, publicKey := range txBase.Bloom.PublicKeyLists[payloadIndex] {
+		rinputs = append(rinputs, publicKey...)
+	}
+
+	secret := new(crypto.BNRed).SetBytes(senderPrivateKey.Key).BigInt()
+	rencrypted := new(bn256.G1).ScalarMult(crypto.HashToPoint(crypto.HashtoNumber(append([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT), rinputs...))), secret)
+	return crypto.ReducedHash(rencrypted.EncodeCompressed())
+}
+
+func TestPaymentProofVerify(t *testing.T) {
+
+	tx, senderPrivateKey, transfers := createPaymentProofTestTx(t, 2)
+	otherTx, _, _ := createPaymentProofTestTx(t, 2)
+
+	for payloadIndex, transfer := range transfers {
+
+		recipientIndex := getPaymentProofTestRecipientIndex(t, tx, payloadIndex, transfer.recipientPublicKey)
+
+		recipientProof, err := CreatePaymentProof(tx, PAYMENT_PROOF_RECIPIENT, payloadIndex, recipientIndex, transfer.amount, new(crypto.BNRed).SetBytes(transfer.recipientPrivateKey.Key).BigInt())
+		assert.NoError(t, err)
+
+		senderProof, err := CreatePaymentProof(tx, PAYMENT_PROOF_SENDER, payloadIndex, recipientIndex, transfer.amount, getPaymentProofTestR(tx, payloadIndex, senderPrivateKey))
+		assert.NoError(t, err)
+
+		for _, proof := range []*PaymentProof{recipientProof, senderProof} {
+
+			decoded, err := DecodePaymentProof(proof.Encode())
+			assert.NoError(t, err)
+
+			output, err := decoded.Verify(tx)
+			assert.NoError(t, err)
+			assert.Equal(t, transfer.amount, output.Amount)
+			assert.Equal(t, uint64(payloadIndex), output.PayloadIndex)
+			assert.Equal(t, config_coins.NATIVE_ASSET_FULL, []byte(output.Asset))
+
+			recipient, err := addresses.DecodeAddr(output.Recipient)
+			assert.NoError(t, err)
+			assert.Equal(t, transfer.recipientPublicKey, recipient.PublicKey)
+
+			//wrong tx
+			_, err = decoded.Verify(otherTx)
+			assert.Error(t, err)
+
+			tampered := *decoded
+			tampered.TxHash = otherTx.HashManual()
+			_, err = tampered.Verify(otherTx)
+			assert.Error(t, err)
+
+			//wrong payload
+			tampered = *decoded
+			tampered.PayloadIndex = uint64(1 - payloadIndex)
+			_, err = tampered.Verify(tx)
+			assert.Error(t, err)
+
+			//modified amount
+			tampered = *decoded
+			tampered.Amount += 1
+			_, err = tampered.Verify(tx)
+			assert.Error(t, err)
+
+			//wrong recipient, the other member of the recipient ring
+			tampered = *decoded
+			tampered.RecipientIndex = uint64(getPaymentProofTestOtherRecipientIndex(tx, payloadIndex, recipientIndex))
+			_, err = tampered.Verify(tx)
+			assert.Error(t, err)
+
+			//a proof can not be changed into the other type
+			tampered = *decoded
+			tampered.Type = PaymentProofType(1 - byte(decoded.Type))
+			_, err = tampered.Verify(tx)
+			assert.Error(t, err)
+		}
+
+		//proofs can not be created for a different amount, recipient or payload
+		_, err = CreatePaymentProof(tx, PAYMENT_PROOF_RECIPIENT, payloadIndex, recipientIndex, transfer.amount+1, new(crypto.BNRed).SetBytes(transfer.recipientPrivateKey.Key).BigInt())
+		assert.Error(t, err)
+
+		_, err = CreatePaymentProof(tx, PAYMENT_PROOF_SENDER, payloadIndex, getPaymentProofTestOtherRecipientIndex(tx, payloadIndex, recipientIndex), transfer.amount, getPaymentProofTestR(tx, payloadIndex, senderPrivateKey))
+		assert.Error(t, err)
+
+		_, err = CreatePaymentProof(tx, PAYMENT_PROOF_SENDER, 1-payloadIndex, getPaymentProofTestRecipientIndex(t, tx, 1-payloadIndex, transfers[1-payloadIndex].recipientPublicKey), transfer.amount, getPaymentProofTestR(tx, payloadIndex, senderPrivateKey))
+		assert.Error(t, err)
+	}
+}
+
+func getPaymentProofTestOtherRecipientIndex(tx *transaction.Transaction, payloadIndex, recipientIndex int) int {
+	payload := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads[payloadIndex]
+	for k := range payload.Statement.Publickeylist {
+		if k != recipientIndex && (k%2 == 0) != payload.Parity {
+			return k
+		}
+	}
+	return -1
+}
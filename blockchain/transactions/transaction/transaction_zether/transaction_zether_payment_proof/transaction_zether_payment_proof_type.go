@@ -0,0 +1,19 @@
+package transaction_zether_payment_proof
+
+type PaymentProofType byte
+
+const (
+	PAYMENT_PROOF_SENDER PaymentProofType = iota
+	PAYMENT_PROOF_RECIPIENT
+)
+
+func (t PaymentProofType) String() string {
+	switch t {
+	case PAYMENT_PROOF_SENDER:
+		return "PAYMENT_PROOF_SENDER"
+	case PAYMENT_PROOF_RECIPIENT:
+		return "PAYMENT_PROOF_RECIPIENT"
+	default:
+		return "Unknown PaymentProofType"
+	}
+}
@@ -61,12 +61,15 @@ func initialize() {
 				}),
 				"setWalletNonHardening": js.FuncOf(setWalletNonHardening),
 			}),
-			"decryptMessageWalletAddress":     js.FuncOf(decryptMessageWalletAddress),
-			"signMessageWalletAddress":        js.FuncOf(signMessageWalletAddress),
-			"deriveSharedStakedWalletAddress": js.FuncOf(deriveSharedStakedWalletAddress),
-			"tryDecryptBalance":               js.FuncOf(tryDecryptBalance),
-			"getPrivateKeysWalletAddress":     js.FuncOf(getPrivateKeysWalletAddress),
-			"decryptTx":                       js.FuncOf(decryptTx),
+			"decryptMessageWalletAddress":        js.FuncOf(decryptMessageWalletAddress),
+			"signMessageWalletAddress":           js.FuncOf(signMessageWalletAddress),
+			"deriveSharedStakedWalletAddress":    js.FuncOf(deriveSharedStakedWalletAddress),
+			"createDelegatorNotifyWalletAddress": js.FuncOf(createDelegatorNotifyWalletAddress),
+			"tryDecryptBalance":                  js.FuncOf(tryDecryptBalance),
+			"getPrivateKeysWalletAddress":        js.FuncOf(getPrivateKeysWalletAddress),
+			"decryptTx":                          js.FuncOf(decryptTx),
+			"createPaymentProofWalletAddress":    js.FuncOf(createPaymentProofWalletAddress),
+			"createBalanceProofWalletAddress":    js.FuncOf(createBalanceProofWalletAddress),
 		}),
 		"addresses": js.ValueOf(map[string]any{
 			"createAddress":      js.FuncOf(createAddress),
@@ -111,6 +114,7 @@ func initialize() {
 			"getNetworkMempool":                      js.FuncOf(getNetworkMempool),
 			"postNetworkMempoolBroadcastTransaction": js.FuncOf(postNetworkMempoolBroadcastTransaction),
 			"getNetworkFeeLiquidity":                 js.FuncOf(getNetworkFeeLiquidity),
+			"getNetworkStakingEstimate":              js.FuncOf(getNetworkStakingEstimate),
 			"subscribeNetwork":                       js.FuncOf(subscribeNetwork),
 			"unsubscribeNetwork":                     js.FuncOf(unsubscribeNetwork),
 		}),
@@ -119,6 +123,8 @@ func initialize() {
 				"createSimpleTx": js.FuncOf(createSimpleTx),
 			}),
 			"signResolutionConditionalPayment": js.FuncOf(signResolutionConditionalPayment),
+			"verifyPaymentProof":               js.FuncOf(verifyPaymentProof),
+			"verifyBalanceProof":               js.FuncOf(verifyBalanceProof),
 		}),
 		"mempool": js.ValueOf(map[string]any{
 			"mempoolRemoveTx": js.FuncOf(mempoolRemoveTx),
@@ -139,6 +145,7 @@ func initialize() {
 					"ScriptType": js.ValueOf(map[string]any{
 						"SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY":     js.ValueOf(uint64(transaction_simple.SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY)),
 						"SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT": js.ValueOf(uint64(transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT)),
+						"SCRIPT_SLASHING_EVIDENCE":              js.ValueOf(uint64(transaction_simple.SCRIPT_SLASHING_EVIDENCE)),
 					}),
 				}),
 				"transactionZether": js.ValueOf(map[string]any{
@@ -383,6 +383,18 @@ func getNetworkFeeLiquidity(this js.Value, args []js.Value) interface{} {
 	})
 }
 
+func getNetworkStakingEstimate(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		request := &api_common.APIStakingEstimateRequest{}
+		if err := webassembly_utils.UnmarshalBytes(args[0], request); err != nil {
+			return nil, err
+		}
+
+		return webassembly_utils.ConvertToJSONBytes(network.SendJSONAwaitAnswer[api_common.APIStakingEstimateReply]([]byte("staking/estimate"), request, nil, 0))
+	})
+}
+
 func subscribeNetwork(this js.Value, args []js.Value) interface{} {
 	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
 
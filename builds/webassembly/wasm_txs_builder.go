@@ -5,10 +5,15 @@ import (
 	"errors"
 	"mc/addresses"
 	"mc/app"
+	"mc/blockchain/transactions/transaction"
 	"mc/blockchain/transactions/transaction/transaction_simple"
 	"mc/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
+	"mc/blockchain/transactions/transaction/transaction_zether/transaction_zether_payment_proof"
 	"mc/builds/webassembly/webassembly_utils"
 	"mc/cryptography/crypto"
+	"mc/cryptography/crypto/balance_proof"
+	"mc/helpers"
+	"mc/helpers/advanced_buffers"
 	"mc/txs_builder/wizard"
 	"syscall/js"
 )
@@ -49,6 +54,8 @@ func createSimpleTx(this js.Value, args []js.Value) interface{} {
 			txData.Extra = &wizard.WizardTxSimpleExtraUpdateAssetFeeLiquidity{}
 		case transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
 			txData.Extra = &wizard.WizardTxSimpleExtraResolutionConditionalPayment{}
+		case transaction_simple.SCRIPT_SLASHING_EVIDENCE:
+			txData.Extra = &wizard.WizardTxSimpleExtraSlashingEvidence{}
 		default:
 			txData.Extra = nil
 			return nil, errors.New("Invalid Tx Simple Script")
@@ -136,3 +143,55 @@ func signResolutionConditionalPayment(this js.Value, args []js.Value) interface{
 
 	})
 }
+
+func verifyPaymentProof(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		tx := &transaction.Transaction{}
+		if err := tx.Deserialize(advanced_buffers.NewBufferReader(webassembly_utils.GetBytes(args[0]))); err != nil {
+			return nil, err
+		}
+
+		proof, err := transaction_zether_payment_proof.DecodePaymentProof(args[1].String())
+		if err != nil {
+			return nil, err
+		}
+
+		output, err := proof.Verify(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		return webassembly_utils.ConvertJSONBytes(output)
+	})
+}
+
+// verifyBalanceProof only checks the proof. The caller must compare the balance returned with the encrypted balance of the account at the height
+func verifyBalanceProof(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		proof, err := balance_proof.DecodeBalanceProof(args[0].String())
+		if err != nil {
+			return nil, err
+		}
+
+		if err = proof.Verify(); err != nil {
+			return nil, err
+		}
+
+		addr, err := addresses.CreateAddr(proof.PublicKey, false, nil, nil, nil, 0, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return webassembly_utils.ConvertJSONBytes(struct {
+			Address    string         `json:"address"`
+			Asset      helpers.Base64 `json:"asset"`
+			Height     uint64         `json:"height"`
+			BlockHash  helpers.Base64 `json:"blockHash"`
+			MinBalance uint64         `json:"minBalance"`
+			Challenge  helpers.Base64 `json:"challenge"`
+			Balance    helpers.Base64 `json:"balance"`
+		}{addr.EncodeAddr(), proof.Asset, proof.Height, proof.BlockHash, proof.MinBalance, proof.Challenge, proof.Balance})
+	})
+}
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"mc/app"
 	"mc/blockchain/transactions/transaction"
 	"mc/builds/webassembly/webassembly_utils"
+	"mc/config/config_nodes"
+	"mc/cryptography/crypto/balance_proof"
 	"mc/helpers"
 	"mc/helpers/advanced_buffers"
+	"mc/network/api_implementation/api_common/api_delegator_node"
 	"syscall/js"
 )
 
@@ -324,6 +330,54 @@ func deriveSharedStakedWalletAddress(this js.Value, args []js.Value) interface{}
 	})
 }
 
+// createDelegatorNotifyWalletAddress prepares the delegator-node/notify request. The commission advertised by delegator-node/info must not exceed the maximum fee rate accepted by the user
+func createDelegatorNotifyWalletAddress(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
+			return false, err
+		}
+
+		parameters := &struct {
+			Address             string                                        `json:"address"`
+			SharedStakedBalance uint64                                        `json:"sharedStakedBalance"`
+			DelegatorInfo       *api_delegator_node.ApiDelegatorNodeInfoReply `json:"delegatorInfo"`
+			MaximumFeeRate      uint64                                        `json:"maximumFeeRate"`
+		}{}
+
+		if err := webassembly_utils.UnmarshalBytes(args[1], parameters); err != nil {
+			return nil, err
+		}
+
+		if parameters.DelegatorInfo == nil {
+			return nil, errors.New("Delegator node info is missing")
+		}
+		if parameters.DelegatorInfo.FeeRate > 0 && parameters.DelegatorInfo.FeeDivisor != config_nodes.DELEGATOR_FEE_DIVISOR {
+			return nil, fmt.Errorf("Delegator node fee divisor %d is not supported", parameters.DelegatorInfo.FeeDivisor)
+		}
+		if parameters.DelegatorInfo.FeeRate > parameters.MaximumFeeRate {
+			return nil, fmt.Errorf("Delegator node commission %d is higher than the maximum accepted %d", parameters.DelegatorInfo.FeeRate, parameters.MaximumFeeRate)
+		}
+
+		addr, err := app.Wallet.GetWalletAddressByEncodedAddress(parameters.Address, true)
+		if err != nil {
+			return nil, err
+		}
+
+		sharedStaked, err := addr.DeriveSharedStaked()
+		if err != nil {
+			return nil, err
+		}
+
+		return webassembly_utils.ConvertJSONBytes(&api_delegator_node.ApiDelegatorNodeNotifyRequest{
+			sharedStaked.PrivateKey.Key,
+			parameters.SharedStakedBalance,
+			parameters.DelegatorInfo.FeeRate,
+		})
+
+	})
+}
+
 func tryDecryptBalance(this js.Value, args []js.Value) interface{} {
 	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
 
@@ -395,7 +449,7 @@ func decryptTx(this js.Value, args []js.Value) interface{} {
 			return nil, err
 		}
 
-		decrypted, err := app.Wallet.DecryptTx(tx, publicKey)
+		decrypted, err := app.Wallet.DecryptTx(tx, publicKey, true)
 		if err != nil {
 			return nil, err
 		}
@@ -404,6 +458,71 @@ func decryptTx(this js.Value, args []js.Value) interface{} {
 	})
 }
 
+func createPaymentProofWalletAddress(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		publicKey, err := base64.StdEncoding.DecodeString(args[2].String())
+		if err != nil {
+			return nil, err
+		}
+
+		tx := &transaction.Transaction{}
+		if err = tx.Deserialize(advanced_buffers.NewBufferReader(webassembly_utils.GetBytes(args[0]))); err != nil {
+			return nil, err
+		}
+
+		proof, err := app.Wallet.CreatePaymentProof(tx, args[1].Int(), publicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		return proof.Encode(), nil
+	})
+}
+
+func createBalanceProofWalletAddress(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		if err := app.Wallet.Encryption.CheckPassword(args[0].String(), false); err != nil {
+			return nil, err
+		}
+
+		parameters := &struct {
+			PublicKey  []byte `json:"publicKey"`
+			Asset      []byte `json:"asset"`
+			Balance    []byte `json:"balance"`
+			Height     uint64 `json:"height"`
+			BlockHash  []byte `json:"blockHash"`
+			MinBalance uint64 `json:"minBalance"`
+			Challenge  []byte `json:"challenge"`
+		}{}
+
+		if err := webassembly_utils.UnmarshalBytes(args[1], parameters); err != nil {
+			return nil, err
+		}
+
+		addr := app.Wallet.GetWalletAddressByPublicKey(parameters.PublicKey, true)
+		if addr == nil {
+			return nil, errors.New("Address was not found")
+		}
+		if addr.PrivateKey == nil {
+			return nil, errors.New("Private key is missing")
+		}
+
+		balance, err := app.Wallet.DecryptBalance(addr, parameters.Balance, parameters.Asset, false, 0, true, context.Background(), func(string) {})
+		if err != nil {
+			return nil, err
+		}
+
+		proof, err := balance_proof.CreateBalanceProof(addr.PrivateKey.Key, parameters.Asset, parameters.Height, parameters.BlockHash, parameters.Balance, balance, parameters.MinBalance, parameters.Challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		return proof.Encode(), nil
+	})
+}
+
 func setWalletNonHardening(this js.Value, args []js.Value) interface{} {
 	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
 		app.Wallet.SetNonHardening(args[0].Bool())
@@ -6,7 +6,7 @@ package arguments
 var commands = `MOLTENCHAIN WASM.
 
 Usage:
-  molten [--pprof] [--version] [--network=network] [--debug] [--gui-type=type] [--forging] [--new-devnet] [--node-name=name] [--set-genesis=genesis] [--store-wallet-type=type] [--store-chain-type=type] [--node-consensus=type] [--tcp-max-clients=limit] [--node-provide-extended-info-app=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--instance=prefix] [--instance-id=id] [--balance-decryptor-disable-init] [--tcp-connections-ready=threshold] [--exit]
+  molten [--pprof] [--version] [--network=network] [--activation-heights=args] [--debug] [--gui-type=type] [--forging] [--new-devnet] [--node-name=name] [--set-genesis=genesis] [--store-wallet-type=type] [--store-chain-type=type] [--node-consensus=type] [--tcp-max-clients=limit] [--node-provide-extended-info-app=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--wallet-import-secret-shares=shares] [--instance=prefix] [--instance-id=id] [--balance-decryptor-disable-init] [--tcp-connections-ready=threshold] [--exit]
   molten -h | --help
   molten -v | --version
 
@@ -17,6 +17,7 @@ Options:
   --instance=prefix                                  Prefix of the instance [default: 0].
   --instance-id=id                                   Number of forked instance (when you open multiple instances). It should be a string number like "1","2","3","4" etc
   --network=network                                  Select network. Accepted values: "mainnet|testnet|devnet". [default: mainnet]
+  --activation-heights=args                          Override the activation heights of the selected network, mainly for testing networks. Arguments must be a JSON "{'median-time-past': 0, 'slashing': 0, 'delegator-commission': 0}".
   --new-devnet                                       Create a new devnet genesis.
   --set-genesis=genesis                              Manually set the Genesis via a JSON. Used for devnet genesis in Browser.
   --store-wallet-type=type                           Set Wallet Store Type. Accepted values: "bunt-memory|memory|js". [default: js]
@@ -29,6 +30,7 @@ Options:
   --node-provide-extended-info-app=bool              Storing and serving additional info to wallet nodes. [default: true]. To enable, it requires full node
   --wallet-import-secret-mnemonic=mnemonic           Import Wallet from a given Mnemonic. It will delete your existing wallet. 
   --wallet-import-secret-entropy=entropy             Import Wallet from a given Entropy. It will delete your existing wallet.
+  --wallet-import-secret-shares=shares               Import Wallet from Secret Shares separated by comma. It will delete your existing wallet.
   --wallet-encrypt=args                              Encrypt wallet. Argument must be "password,difficulty".
   --wallet-decrypt=password                          Decrypt wallet.
   --wallet-remove-encryption                         Remove wallet encryption.
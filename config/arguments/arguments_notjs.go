@@ -8,7 +8,7 @@ package arguments
 var commands = `MOLTENCHAIN.
 
 Usage:
-  molten [--pprof] [--network=network] [--debug] [--gui-type=type] [--forging] [--new-devnet] [--run-testnet-script] [--node-name=name] [--tcp-server-port=port] [--tcp-server-address=address] [--tcp-server-auto-tls-certificate] [--tcp-server-tls-cert-file=path] [--tcp-server-tls-key-file=path] [--instance=prefix] [--instance-id=id] [--set-genesis=genesis] [--create-new-genesis=args] [--store-wallet-type=type] [--store-chain-type=type] [--node-consensus=type] [--tcp-max-clients=limit] [--tcp-max-server-sockets=limit] [--node-provide-extended-info-app=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--hcaptcha-secret=args] [--faucet-testnet-enabled=args] [--delegator-enabled=bool] [--delegator-require-auth=bool] [--delegates-maximum=args] [--auth-users=args] [--light-computations] [--balance-decryptor-disable-init] [--balance-decryptor-table-size=size] [--tcp-connections-ready=threshold] [--exit] [--skip-init-sync] [--tcp-server-url=url] [--tcp-proxy=PROXY]
+  molten [--pprof] [--network=network] [--activation-heights=args] [--debug] [--gui-type=type] [--forging] [--new-devnet] [--run-testnet-script] [--node-name=name] [--tcp-server-port=port] [--tcp-server-address=address] [--tcp-server-auto-tls-certificate] [--tcp-server-tls-cert-file=path] [--tcp-server-tls-key-file=path] [--instance=prefix] [--instance-id=id] [--set-genesis=genesis] [--create-new-genesis=args] [--store-wallet-type=type] [--store-chain-type=type] [--node-consensus=type] [--tcp-max-clients=limit] [--tcp-max-server-sockets=limit] [--node-provide-extended-info-app=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-export-history=args] [--wallet-invoice-confirmations=blocks] [--wallet-auto-lock=seconds] [--wallet-spend-unlock=seconds] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--wallet-import-secret-shares=shares] [--hcaptcha-secret=args] [--faucet-testnet-enabled=args] [--delegator-enabled=bool] [--delegator-require-auth=bool] [--delegates-maximum=args] [--delegator-fee=rate] [--delegator-fee-address=address] [--cosigner-enabled=bool] [--auth-users=args] [--auth-token-create=args] [--auth-token-revoke=name] [--api-wallet-public=bool] [--rate-limit-rate=rate] [--rate-limit-burst=burst] [--rate-limit-weights=args] [--rate-limit-config=path] [--light-computations] [--balance-decryptor-disable-init] [--balance-decryptor-table-size=size] [--tcp-connections-ready=threshold] [--exit] [--skip-init-sync] [--tcp-server-url=url] [--tcp-proxy=PROXY]
   molten -h | --help
   molten -v | --version
 
@@ -20,6 +20,7 @@ Options:
   --instance=prefix                                  Prefix of the instance [default: 0].
   --instance-id=id                                   Number of forked instance (when you open multiple instances). It should be a string number like "1","2","3","4" etc
   --network=network                                  Select network. Accepted values: "mainnet|testnet|devnet". [default: mainnet]
+  --activation-heights=args                          Override the activation heights of the selected network, mainly for testing networks. Arguments must be a JSON "{'median-time-past': 0, 'slashing': 0, 'delegator-commission': 0}".
   --new-devnet                                       Create a new devnet genesis.
   --run-testnet-script                               Run testnet script which will create dummy transactions in the network.
   --set-genesis=genesis                              Manually set the Genesis via a JSON. By using argument "file" it will read it via a file.
@@ -42,16 +43,31 @@ Options:
   --tcp-proxy=proxy                                  Proxy used for network.
   --wallet-import-secret-mnemonic=mnemonic           Import Wallet from a given Mnemonic. It will delete your existing wallet. 
   --wallet-import-secret-entropy=entropy             Import Wallet from a given Entropy. It will delete your existing wallet.
+  --wallet-import-secret-shares=shares               Import Wallet from Secret Shares separated by comma. It will delete your existing wallet.
   --wallet-encrypt=args                              Encrypt wallet. Argument must be "password,difficulty".
   --wallet-decrypt=password                          Decrypt wallet.
   --wallet-remove-encryption                         Remove wallet encryption.
   --wallet-export-shared-staked-address=args         Derive and export Staked address. Argument must be "account,nonce,path".
+  --wallet-export-history=args                       Export the wallet history to CSV or JSON (.json). Argument must be "file,from,to". Empty heights are unbounded.
+  --wallet-invoice-confirmations=blocks              Blocks required to confirm a wallet invoice payment. [default: 10].
+  --wallet-auto-lock=seconds                         Lock the encrypted wallet after seconds without activity. 0 disables it. [default: 0].
+  --wallet-spend-unlock=seconds                      Seconds after unlocking in which the spend scope API calls are allowed. 0 disables it. [default: 0].
   --hcaptcha-secret=args                             hcaptcha Secret.
   --faucet-testnet-enabled=args                      Enable Faucet Testnet. Use "true" to enable it
   --delegator-enabled=bool                           Enable Delegator. Will allow other users to Delegate to the node. Use "true" to enable it
   --delegator-require-auth=bool                      Delegator will require authentication.
   --delegates-maximum=args                           Maximum number of Delegates
-  --auth-users=args                                  Credential for Authenticated Users. Arguments must be a JSON "[{'user': 'username', 'pass': 'secret'}]".
+  --delegator-fee=rate                               Commission taken from the staking rewards forged with the delegated stakes, in basis points (1/10000). [default: 0].
+  --delegator-fee-address=address                    Address which receives the commission. The first wallet address is used in case it is missing.
+  --cosigner-enabled=bool                            Enable the Co-Signer. The node will hold Spend Private Keys and sign the spendings allowed by their policies. Use "true" to enable it
+  --auth-users=args                                  Deprecated, use API tokens instead. Credential for Authenticated Users with admin scope. Arguments must be a JSON "[{'user': 'username', 'pass': 'secret'}]".
+  --auth-token-create=args                           Create an API token. Arguments must be "name,scope|scope". Scopes: read, wallet, wallet-spend, delegator, cosigner, admin. The token is displayed only once.
+  --auth-token-revoke=name                           Revoke the API token with the given name.
+  --api-wallet-public=bool                           Allow wallet endpoints on non loopback addresses. A local reverse proxy or TOR makes all requests look local [default: false].
+  --rate-limit-rate=rate                             API requests tokens refilled per second for every IP or authenticated user. Use 0 to disable rate limiting.
+  --rate-limit-burst=burst                           API requests tokens that can be spent at once by an IP or authenticated user.
+  --rate-limit-weights=args                          Tokens spent by a route. Arguments must be a JSON "{'block-complete': 2, 'mempool/new-tx': 5}". Missing routes spend 1 token.
+  --rate-limit-config=path                           Load the rate limits from a JSON file "{'rate': 100, 'burst': 500, 'weights': {'faucet/coins': 50}}". Flags override the file.
   --light-computations                               Reduces the computations for a testnet node.
   --balance-decryptor-disable-init                   Disable first balance decryptor initialization. 
   --balance-decryptor-table-size=size                Balance Decryptor initial table size. [default: 23]
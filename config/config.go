@@ -1,14 +1,17 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/blang/semver/v4"
 	"math/big"
 	"math/rand"
 	"mc/config/arguments"
+	"mc/config/config_cosigner"
 	"mc/config/config_forging"
 	"mc/config/config_nodes"
 	"runtime"
+	"strconv"
 	"time"
 )
 
@@ -43,6 +46,7 @@ const (
 	NETWORK_BYTE_PREFIX_LENGTH             = 7
 	NETWORK_TIMESTAMP_DRIFT_MAX     uint64 = 10
 	NETWORK_TIMESTAMP_DRIFT_MAX_INT int64  = 10
+	NETWORK_CLOCK_DRIFT_WARNING_MS  int64  = 5000
 )
 
 const (
@@ -53,18 +57,49 @@ const (
 	FORK_MAX_DOWNLOAD       uint64 = 20
 )
 
+const (
+	BLOCK_MEDIAN_TIME_PAST_WINDOW               uint64 = 11
+	MAIN_NET_MEDIAN_TIME_PAST_ACTIVATION_HEIGHT uint64 = 500000
+	TEST_NET_MEDIAN_TIME_PAST_ACTIVATION_HEIGHT uint64 = 100000
+	DEV_NET_MEDIAN_TIME_PAST_ACTIVATION_HEIGHT  uint64 = 0
+)
+
+// from the activation height the blocks are signed with the staking key, and the blocks signed twice for the same staking nonce can be slashed
+const (
+	MAIN_NET_SLASHING_ACTIVATION_HEIGHT uint64 = 500000
+	TEST_NET_SLASHING_ACTIVATION_HEIGHT uint64 = 100000
+	DEV_NET_SLASHING_ACTIVATION_HEIGHT  uint64 = 0
+)
+
+// from the activation height the forging tx can have a third payload paying the commission of the delegator node
+const (
+	MAIN_NET_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT uint64 = 500000
+	TEST_NET_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT uint64 = 100000
+	DEV_NET_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT  uint64 = 0
+)
+
 var (
-	NETWORK_SELECTED                 = MAIN_NET_NETWORK_BYTE
-	NETWORK_SELECTED_BYTE_PREFIX     = MAIN_NET_NETWORK_BYTE_PREFIX
-	NETWORK_SELECTED_NAME            = MAIN_NET_NETWORK_NAME
-	NETWORK_SELECTED_SEEDS           = MAIN_NET_SEED_NODES
-	NETWORK_SELECTED_DELEGATOR_NODES = config_nodes.MAIN_NET_DELEGATOR_NODES
+	NETWORK_SELECTED                                        = MAIN_NET_NETWORK_BYTE
+	NETWORK_SELECTED_BYTE_PREFIX                            = MAIN_NET_NETWORK_BYTE_PREFIX
+	NETWORK_SELECTED_NAME                                   = MAIN_NET_NETWORK_NAME
+	NETWORK_SELECTED_SEEDS                                  = MAIN_NET_SEED_NODES
+	NETWORK_SELECTED_DELEGATOR_NODES                        = config_nodes.MAIN_NET_DELEGATOR_NODES
+	NETWORK_SELECTED_MEDIAN_TIME_PAST_ACTIVATION_HEIGHT     = MAIN_NET_MEDIAN_TIME_PAST_ACTIVATION_HEIGHT
+	NETWORK_SELECTED_SLASHING_ACTIVATION_HEIGHT             = MAIN_NET_SLASHING_ACTIVATION_HEIGHT
+	NETWORK_SELECTED_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT = MAIN_NET_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT
 )
 
 var (
 	API_MEMPOOL_MAX_TRANSACTIONS = 50
 	API_ACCOUNT_MAX_TXS          = uint64(10)
 	API_ASSETS_INFO_MAX_RESULTS  = 10
+	API_STAKING_ESTIMATE_BLOCKS  = uint64(100)
+)
+
+var (
+	WALLET_INVOICE_CONFIRMATIONS = uint64(10)
+	WALLET_AUTO_LOCK             = time.Duration(0) //0 means the encrypted wallet is never locked when idle
+	WALLET_SPEND_UNLOCK          = time.Duration(0) //0 means the spend-scope calls are allowed while the wallet is unlocked
 )
 
 var (
@@ -94,16 +129,41 @@ func InitConfig() (err error) {
 		NETWORK_SELECTED_DELEGATOR_NODES = config_nodes.TEST_NET_DELEGATOR_NODES
 		NETWORK_SELECTED_NAME = TEST_NET_NETWORK_NAME
 		NETWORK_SELECTED_BYTE_PREFIX = TEST_NET_NETWORK_BYTE_PREFIX
+		NETWORK_SELECTED_MEDIAN_TIME_PAST_ACTIVATION_HEIGHT = TEST_NET_MEDIAN_TIME_PAST_ACTIVATION_HEIGHT
+		NETWORK_SELECTED_SLASHING_ACTIVATION_HEIGHT = TEST_NET_SLASHING_ACTIVATION_HEIGHT
+		NETWORK_SELECTED_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT = TEST_NET_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT
 	} else if arguments.Arguments["--network"] == "devnet" {
 		NETWORK_SELECTED = DEV_NET_NETWORK_BYTE
 		NETWORK_SELECTED_SEEDS = DEV_NET_SEED_NODES
 		NETWORK_SELECTED_DELEGATOR_NODES = config_nodes.DEV_NET_DELEGATOR_NODES
 		NETWORK_SELECTED_NAME = DEV_NET_NETWORK_NAME
 		NETWORK_SELECTED_BYTE_PREFIX = DEV_NET_NETWORK_BYTE_PREFIX
+		NETWORK_SELECTED_MEDIAN_TIME_PAST_ACTIVATION_HEIGHT = DEV_NET_MEDIAN_TIME_PAST_ACTIVATION_HEIGHT
+		NETWORK_SELECTED_SLASHING_ACTIVATION_HEIGHT = DEV_NET_SLASHING_ACTIVATION_HEIGHT
+		NETWORK_SELECTED_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT = DEV_NET_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT
 	} else {
 		return errors.New("selected --network is invalid. Accepted only: mainnet, testnet, devnet")
 	}
 
+	if str := arguments.Arguments["--activation-heights"]; str != nil {
+		heights := map[string]uint64{}
+		if err = json.Unmarshal([]byte(str.(string)), &heights); err != nil {
+			return errors.New("--activation-heights is invalid")
+		}
+		for name, height := range heights {
+			switch name {
+			case "median-time-past":
+				NETWORK_SELECTED_MEDIAN_TIME_PAST_ACTIVATION_HEIGHT = height
+			case "slashing":
+				NETWORK_SELECTED_SLASHING_ACTIVATION_HEIGHT = height
+			case "delegator-commission":
+				NETWORK_SELECTED_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT = height
+			default:
+				return errors.New("--activation-heights has an invalid name " + name)
+			}
+		}
+	}
+
 	if arguments.Arguments["--debug"] == true {
 		DEBUG = true
 	}
@@ -127,6 +187,28 @@ func InitConfig() (err error) {
 		return errors.New("invalid consensus argument")
 	}
 
+	if str := arguments.Arguments["--wallet-invoice-confirmations"]; str != nil {
+		if WALLET_INVOICE_CONFIRMATIONS, err = strconv.ParseUint(str.(string), 10, 64); err != nil {
+			return errors.New("--wallet-invoice-confirmations is invalid")
+		}
+	}
+
+	if str := arguments.Arguments["--wallet-auto-lock"]; str != nil {
+		var seconds uint64
+		if seconds, err = strconv.ParseUint(str.(string), 10, 64); err != nil {
+			return errors.New("--wallet-auto-lock is invalid")
+		}
+		WALLET_AUTO_LOCK = time.Duration(seconds) * time.Second
+	}
+
+	if str := arguments.Arguments["--wallet-spend-unlock"]; str != nil {
+		var seconds uint64
+		if seconds, err = strconv.ParseUint(str.(string), 10, 64); err != nil {
+			return errors.New("--wallet-spend-unlock is invalid")
+		}
+		WALLET_SPEND_UNLOCK = time.Duration(seconds) * time.Second
+	}
+
 	if err = config_nodes.InitConfig(); err != nil {
 		return
 	}
@@ -139,6 +221,10 @@ func InitConfig() (err error) {
 		return
 	}
 
+	if err = config_cosigner.InitConfig(); err != nil {
+		return
+	}
+
 	return
 }
 
@@ -0,0 +1,28 @@
+package config_cosigner
+
+import (
+	"mc/config/arguments"
+	"time"
+)
+
+var (
+	/* COSIGNER_ENABLED
+	the node will hold Spend Private Keys and co-sign the spendings of the UPPOS accounts according to their policies
+	*/
+	COSIGNER_ENABLED = false
+
+	/* COSIGNER_TOTP_MAX_FAILED_ATTEMPTS
+	the confirmation codes of an account are rejected for COSIGNER_TOTP_LOCKOUT after this number of consecutive invalid codes
+	*/
+	COSIGNER_TOTP_MAX_FAILED_ATTEMPTS = 5
+	COSIGNER_TOTP_LOCKOUT             = 15 * time.Minute
+)
+
+func InitConfig() (err error) {
+
+	if arguments.Arguments["--cosigner-enabled"] == "true" {
+		COSIGNER_ENABLED = true
+	}
+
+	return
+}
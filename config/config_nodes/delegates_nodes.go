@@ -1,6 +1,7 @@
 package config_nodes
 
 import (
+	"fmt"
 	"mc/config/arguments"
 	"strconv"
 )
@@ -63,6 +64,15 @@ var (
 	DELEGATOR_ENABLED      = false
 	DELEGATOR_REQUIRE_AUTH = false
 	DELEGATES_MAXIMUM      = 10000
+	DELEGATES_CHECK_BLOCKS = uint64(10) //the delegated stakes below the required stake are revoked every DELEGATES_CHECK_BLOCKS
+
+	/* DELEGATOR_FEE
+	commission taken from the staking rewards forged with the delegated stakes, in DELEGATOR_FEE_DIVISOR parts.
+	The delegators agree to it when they delegate
+	*/
+	DELEGATOR_FEE         = uint64(0)
+	DELEGATOR_FEE_DIVISOR = uint64(10000)
+	DELEGATOR_FEE_ADDRESS = "" //empty uses the first wallet address
 )
 
 func InitConfig() (err error) {
@@ -81,5 +91,18 @@ func InitConfig() (err error) {
 		DELEGATOR_REQUIRE_AUTH = true
 	}
 
+	if arguments.Arguments["--delegator-fee"] != nil {
+		if DELEGATOR_FEE, err = strconv.ParseUint(arguments.Arguments["--delegator-fee"].(string), 10, 64); err != nil {
+			return
+		}
+		if DELEGATOR_FEE > DELEGATOR_FEE_DIVISOR {
+			return fmt.Errorf("Delegator fee can not exceed %d", DELEGATOR_FEE_DIVISOR)
+		}
+	}
+
+	if arguments.Arguments["--delegator-fee-address"] != nil {
+		DELEGATOR_FEE_ADDRESS = arguments.Arguments["--delegator-fee-address"].(string)
+	}
+
 	return nil
 }
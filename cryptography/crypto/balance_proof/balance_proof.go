@@ -0,0 +1,289 @@
+package balance_proof
+
+import (
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers/advanced_buffers"
+	"strings"
+)
+
+const BALANCE_PROOF_PREFIX = "BALPROOF"
+const BALANCE_PROOF_VERSION = uint64(0)
+const BALANCE_PROOF_CHALLENGE_MAX_LENGTH = 64
+
+// BalanceProof attests that the encrypted balance of an account is at least MinBalance.
+// It is a zether proof of a ring of 2 members in which the account spends nothing and pays MinBalance as open value,
+// so the range proof shows that the balance left is not negative. The second member is a public decoy with an empty balance
+type BalanceProof struct {
+	Version    uint64
+	PublicKey  []byte
+	Asset      []byte
+	Height     uint64
+	BlockHash  []byte
+	MinBalance uint64
+	Challenge  []byte //fresh data provided by the verifier to avoid replaying old proofs
+	Balance    []byte //encrypted balance of the account at the height
+	C          []*bn256.G1
+	D          *bn256.G1
+	Proof      *crypto.Proof
+	Signature  []byte //signed by the account private key
+}
+
+func getDecoy() *bn256.G1 {
+	return crypto.HashToPoint(crypto.HashtoNumber([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT + BALANCE_PROOF_PREFIX)))
+}
+
+func (proof *BalanceProof) statement() (*crypto.Statement, error) {
+
+	var publicKey bn256.G1
+	if err := publicKey.DecodeCompressed(proof.PublicKey); err != nil {
+		return nil, err
+	}
+
+	balance, err := new(crypto.ElGamal).Deserialize(proof.Balance)
+	if err != nil {
+		return nil, err
+	}
+
+	decoy := getDecoy()
+	balances := []*crypto.ElGamal{balance, crypto.ConstructElGamal(decoy, crypto.ElGamal_BASE_G)}
+
+	s := &crypto.Statement{
+		RingSize:      2,
+		Publickeylist: []*bn256.G1{&publicKey, decoy},
+		C:             proof.C,
+		D:             proof.D,
+	}
+	for i := range balances {
+		s.CLn = append(s.CLn, new(bn256.G1).Add(balances[i].Left, proof.C[i]))
+		s.CRn = append(s.CRn, new(bn256.G1).Add(balances[i].Right, proof.D))
+	}
+
+	return s, nil
+}
+
+// chainHash binds the nonce of the zether proof to the challenge, so it can't be linked with the transactions of the account
+func (proof *BalanceProof) chainHash() []byte {
+	return cryptography.SHA3(append(append([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT+BALANCE_PROOF_PREFIX), proof.BlockHash...), proof.Challenge...))
+}
+
+func (proof *BalanceProof) serializeStatement(w *advanced_buffers.BufferWriter) {
+	w.WriteUvarint(proof.Version)
+	w.Write(proof.PublicKey)
+	w.WriteAsset(proof.Asset)
+	w.WriteUvarint(proof.Height)
+	w.Write(proof.BlockHash)
+	w.WriteUvarint(proof.MinBalance)
+	w.WriteVariableBytes(proof.Challenge)
+	w.Write(proof.Balance)
+	w.Write(proof.C[0].EncodeCompressed())
+	w.Write(proof.C[1].EncodeCompressed())
+	w.Write(proof.D.EncodeCompressed())
+}
+
+func (proof *BalanceProof) hashStatement() []byte {
+	w := advanced_buffers.NewBufferWriter()
+	proof.serializeStatement(w)
+	return cryptography.SHA3(w.Bytes())
+}
+
+func (proof *BalanceProof) SerializeForSigning() []byte {
+	w := advanced_buffers.NewBufferWriter()
+	proof.serializeStatement(w)
+	proof.Proof.Serialize(w)
+	return cryptography.SHA3(w.Bytes())
+}
+
+// CreateBalanceProof requires the decrypted balance of the account
+func CreateBalanceProof(privateKey, asset []byte, height uint64, blockHash, encryptedBalance []byte, balance, minBalance uint64, challenge []byte) (*BalanceProof, error) {
+
+	if balance < minBalance {
+		return nil, errors.New("Balance is lower than the minimum balance")
+	}
+	if len(challenge) > BALANCE_PROOF_CHALLENGE_MAX_LENGTH {
+		return nil, errors.New("Challenge is too long")
+	}
+	if len(blockHash) != cryptography.HashSize {
+		return nil, errors.New("Block hash is invalid")
+	}
+
+	secret := new(crypto.BNRed).SetBytes(privateKey)
+	publicKey := crypto.GPoint.ScalarMult(secret).G1()
+
+	encrypted, err := new(crypto.ElGamal).Deserialize(encryptedBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	r := crypto.RandomScalarFixed()
+
+	proof := &BalanceProof{
+		Version:    BALANCE_PROOF_VERSION,
+		PublicKey:  publicKey.EncodeCompressed(),
+		Asset:      asset,
+		Height:     height,
+		BlockHash:  blockHash,
+		MinBalance: minBalance,
+		Challenge:  challenge,
+		Balance:    encrypted.Serialize(),
+		C: []*bn256.G1{
+			new(bn256.G1).Add(new(bn256.G1).ScalarMult(crypto.G, new(big.Int).Neg(new(big.Int).SetUint64(minBalance))), new(bn256.G1).ScalarMult(publicKey, r)),
+			new(bn256.G1).ScalarMult(getDecoy(), r),
+		},
+		D: new(bn256.G1).ScalarMult(crypto.G, r),
+	}
+
+	s, err := proof.statement()
+	if err != nil {
+		return nil, err
+	}
+
+	chainHash := proof.chainHash()
+
+	uinput := append([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT), chainHash...)
+	uinput = append(uinput, asset...)
+	uinput = append(uinput, "0"...)
+	u := new(bn256.G1).ScalarMult(crypto.HashToPoint(crypto.HashtoNumber(uinput)), secret.BigInt())
+
+	witness := &crypto.Witness{
+		SecretKey:      secret.BigInt(),
+		R:              r,
+		TransferAmount: 0,
+		Balance:        balance - minBalance,
+		Index:          []int{0, 1},
+	}
+
+	if proof.Proof, err = crypto.GenerateProof(asset, 0, chainHash, s, witness, u, proof.hashStatement(), minBalance); err != nil {
+		return nil, err
+	}
+
+	if proof.Signature, err = crypto.SignMessage(proof.SerializeForSigning(), privateKey); err != nil {
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+// Verify checks the proof against the encrypted balance included. The caller must check that Balance is the balance of the account at Height
+func (proof *BalanceProof) Verify() error {
+
+	if proof.Version != BALANCE_PROOF_VERSION {
+		return errors.New("Invalid Balance Proof Version")
+	}
+	if proof.Proof == nil || len(proof.C) != 2 || proof.D == nil {
+		return errors.New("Balance Proof is incomplete")
+	}
+
+	s, err := proof.statement()
+	if err != nil {
+		return err
+	}
+
+	if !proof.Proof.Parity() { //the account must be the sender
+		return errors.New("Balance Proof parity is invalid")
+	}
+
+	if !proof.Proof.Verify(proof.Asset, 0, proof.chainHash(), s, proof.hashStatement(), proof.MinBalance) {
+		return errors.New("Balance Proof is invalid")
+	}
+
+	if !crypto.VerifySignature(proof.SerializeForSigning(), proof.Signature, proof.PublicKey) {
+		return errors.New("Balance Proof signature is invalid")
+	}
+
+	return nil
+}
+
+func (proof *BalanceProof) Serialize(w *advanced_buffers.BufferWriter) {
+	proof.serializeStatement(w)
+	proof.Proof.Serialize(w)
+	w.Write(proof.Signature)
+}
+
+func (proof *BalanceProof) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+
+	if proof.Version, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	if proof.Version != BALANCE_PROOF_VERSION {
+		return errors.New("Invalid Balance Proof Version")
+	}
+	if proof.PublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+		return
+	}
+	if proof.Asset, err = r.ReadAsset(); err != nil {
+		return
+	}
+	if proof.Height, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	if proof.BlockHash, err = r.ReadBytes(cryptography.HashSize); err != nil {
+		return
+	}
+	if proof.MinBalance, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	if proof.Challenge, err = r.ReadVariableBytes(BALANCE_PROOF_CHALLENGE_MAX_LENGTH); err != nil {
+		return
+	}
+	if proof.Balance, err = r.ReadBytes(66); err != nil {
+		return
+	}
+
+	proof.C = make([]*bn256.G1, 2)
+	for i := range proof.C {
+		if proof.C[i], err = r.ReadBN256G1(); err != nil {
+			return
+		}
+	}
+	if proof.D, err = r.ReadBN256G1(); err != nil {
+		return
+	}
+
+	proof.Proof = &crypto.Proof{}
+	if err = proof.Proof.Deserialize(r, 1); err != nil {
+		return
+	}
+
+	if proof.Signature, err = r.ReadBytes(cryptography.SignatureSize); err != nil {
+		return
+	}
+
+	return
+}
+
+// Encode returns the portable format of the proof
+func (proof *BalanceProof) Encode() string {
+	w := advanced_buffers.NewBufferWriter()
+	proof.Serialize(w)
+	return BALANCE_PROOF_PREFIX + base64.RawURLEncoding.EncodeToString(w.Bytes())
+}
+
+func DecodeBalanceProof(input string) (*BalanceProof, error) {
+
+	input = strings.TrimSpace(input)
+	if !strings.HasPrefix(input, BALANCE_PROOF_PREFIX) {
+		return nil, errors.New("Invalid Balance Proof prefix")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(input[len(BALANCE_PROOF_PREFIX):])
+	if err != nil {
+		return nil, err
+	}
+
+	r := advanced_buffers.NewBufferReader(data)
+
+	proof := &BalanceProof{}
+	if err = proof.Deserialize(r); err != nil {
+		return nil, err
+	}
+	if r.Position != len(data) {
+		return nil, errors.New("Balance Proof has extra bytes")
+	}
+
+	return proof, nil
+}
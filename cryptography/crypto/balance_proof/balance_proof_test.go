@@ -0,0 +1,100 @@
+package balance_proof
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"testing"
+)
+
+func TestBalanceProof(t *testing.T) {
+
+	privateKey := crypto.RandomScalarBNRed()
+	publicKey := crypto.GPoint.ScalarMult(privateKey).G1()
+
+	r := crypto.RandomScalarFixed()
+	balance := uint64(5000)
+	encrypted := crypto.ConstructElGamal(new(bn256.G1).ScalarMult(publicKey, r), new(bn256.G1).ScalarMult(crypto.G, r)).Plus(new(big.Int).SetUint64(balance))
+
+	blockHash := helpers.RandomBytes(32)
+	challenge := []byte("exchange audit")
+
+	_, err := CreateBalanceProof(privateKey.ToBytes(), config_coins.NATIVE_ASSET_FULL, 10, blockHash, encrypted.Serialize(), balance, balance+1, challenge)
+	assert.Error(t, err)
+
+	proof, err := CreateBalanceProof(privateKey.ToBytes(), config_coins.NATIVE_ASSET_FULL, 10, blockHash, encrypted.Serialize(), balance, 3000, challenge)
+	assert.NoError(t, err)
+	assert.NoError(t, proof.Verify())
+
+	decoded, err := DecodeBalanceProof(proof.Encode())
+	assert.NoError(t, err)
+	assert.NoError(t, decoded.Verify())
+	assert.Equal(t, proof.PublicKey, decoded.PublicKey)
+	assert.Equal(t, proof.MinBalance, decoded.MinBalance)
+	assert.Equal(t, proof.Challenge, decoded.Challenge)
+
+	decoded.MinBalance = 4000
+	assert.Error(t, decoded.Verify())
+
+	decoded.MinBalance = proof.MinBalance
+	decoded.Challenge = []byte("another audit")
+	assert.Error(t, decoded.Verify())
+
+	_, err = DecodeBalanceProof(proof.Encode() + "AA")
+	assert.Error(t, err)
+}
+
+func TestBalanceProofTampered(t *testing.T) {
+
+	privateKey := crypto.RandomScalarBNRed()
+	publicKey := crypto.GPoint.ScalarMult(privateKey).G1()
+
+	r := crypto.RandomScalarFixed()
+	balance := uint64(5000)
+	encrypted := crypto.ConstructElGamal(new(bn256.G1).ScalarMult(publicKey, r), new(bn256.G1).ScalarMult(crypto.G, r)).Plus(new(big.Int).SetUint64(balance))
+
+	proof, err := CreateBalanceProof(privateKey.ToBytes(), config_coins.NATIVE_ASSET_FULL, 10, helpers.RandomBytes(32), encrypted.Serialize(), balance, 3000, []byte("exchange audit"))
+	assert.NoError(t, err)
+	assert.NoError(t, proof.Verify())
+
+	tamper := func(change func(proof *BalanceProof)) error {
+		decoded, err := DecodeBalanceProof(proof.Encode())
+		assert.NoError(t, err)
+		change(decoded)
+		return decoded.Verify()
+	}
+
+	//modified balance
+	assert.Error(t, tamper(func(proof *BalanceProof) {
+		proof.Balance = encrypted.Plus(big.NewInt(1)).Serialize()
+	}))
+	assert.Error(t, tamper(func(proof *BalanceProof) {
+		proof.Balance = crypto.ConstructElGamal(publicKey, crypto.ElGamal_BASE_G).Plus(new(big.Int).SetUint64(balance)).Serialize()
+	}))
+
+	//wrong height or block hash
+	assert.Error(t, tamper(func(proof *BalanceProof) {
+		proof.Height += 1
+	}))
+	assert.Error(t, tamper(func(proof *BalanceProof) {
+		proof.BlockHash = helpers.RandomBytes(32)
+	}))
+
+	//wrong public key
+	assert.Error(t, tamper(func(proof *BalanceProof) {
+		proof.PublicKey = crypto.GPoint.ScalarMult(crypto.RandomScalarBNRed()).G1().EncodeCompressed()
+	}))
+
+	//a proof of another account can not be moved to the public key
+	otherPrivateKey := crypto.RandomScalarBNRed()
+	otherPublicKey := crypto.GPoint.ScalarMult(otherPrivateKey).G1()
+	otherEncrypted := crypto.ConstructElGamal(new(bn256.G1).ScalarMult(otherPublicKey, r), new(bn256.G1).ScalarMult(crypto.G, r)).Plus(new(big.Int).SetUint64(balance))
+
+	other, err := CreateBalanceProof(otherPrivateKey.ToBytes(), config_coins.NATIVE_ASSET_FULL, 10, proof.BlockHash, otherEncrypted.Serialize(), balance, 3000, proof.Challenge)
+	assert.NoError(t, err)
+	other.PublicKey = proof.PublicKey
+	assert.Error(t, other.Verify())
+}
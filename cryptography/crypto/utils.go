@@ -9,15 +9,20 @@ import (
 )
 
 func SignMessage(message, key []byte) ([]byte, error) {
+	return SignMessageBase(message, key, G)
+}
+
+// SignMessageBase signs using key * base as public key. A base other than G allows signing with a key without revealing key * G
+func SignMessageBase(message, key []byte, base *bn256.G1) ([]byte, error) {
 
 	var tmppoint bn256.G1
 	tmpsecret := RandomScalar()
-	tmppoint.ScalarMult(G, tmpsecret)
+	tmppoint.ScalarMult(base, tmpsecret)
 
 	priv := new(BNRed).SetBytes(key)
-	pubKey := GPoint.ScalarMult(priv)
+	pubKey := new(bn256.G1).ScalarMult(base, priv.BigInt())
 
-	serialize := []byte(fmt.Sprintf("%s%s%s", pubKey.G1().String(), tmppoint.String(), string(message)))
+	serialize := []byte(fmt.Sprintf("%s%s%s", pubKey.String(), tmppoint.String(), string(message)))
 	c := ReducedHash(serialize)
 
 	s := new(big.Int).Mul(c, priv.BigInt()) // basically scalar mul add
@@ -47,15 +52,20 @@ func VerifySignature(message, signature, publicKey []byte) bool {
 }
 
 func VerifySignaturePoint(message, signature []byte, u *bn256.G1) bool {
+	return VerifySignatureBase(message, signature, G, u)
+}
+
+// VerifySignatureBase verifies a signature created by SignMessageBase. u is key * base
+func VerifySignatureBase(message, signature []byte, base, u *bn256.G1) bool {
 
-	if signature == nil {
+	if len(signature) != cryptography.SignatureSize {
 		return false
 	}
 
 	s := new(big.Int).SetBytes(signature[0:32])
 	c := new(big.Int).SetBytes(signature[32:64])
 
-	tmppoint := new(bn256.G1).Add(new(bn256.G1).ScalarMult(G, s), new(bn256.G1).ScalarMult(u, new(big.Int).Neg(c)))
+	tmppoint := new(bn256.G1).Add(new(bn256.G1).ScalarMult(base, s), new(bn256.G1).ScalarMult(u, new(big.Int).Neg(c)))
 	serialize := []byte(fmt.Sprintf("%s%s%s", u.String(), tmppoint.String(), string(message)))
 
 	cCalculated := ReducedHash(serialize)
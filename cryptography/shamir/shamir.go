@@ -0,0 +1,155 @@
+package shamir
+
+import (
+	"errors"
+	"io"
+)
+
+// Share is a point of the polynomials used to split a secret. Every byte of the secret has its own polynomial over GF(256)
+// and Value[i] is the evaluation of the polynomial of the byte i in Index. The Identifier is random and it is the same for all the shares of a split
+type Share struct {
+	Identifier uint16
+	Threshold  byte
+	Index      byte
+	Value      []byte
+}
+
+var expTable [255]byte
+var logTable [256]byte
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		expTable[i] = x
+		logTable[x] = byte(i)
+		x ^= gfMul2(x) //multiplication by the generator 3
+	}
+}
+
+func gfMul2(a byte) byte {
+	if a&0x80 != 0 {
+		return a<<1 ^ 0x1b
+	}
+	return a << 1
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])+int(logTable[b]))%255]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])-int(logTable[b])+255)%255]
+}
+
+// evaluate uses the Horner's method. coefficients[0] is the secret
+func evaluate(coefficients []byte, x byte) (y byte) {
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ coefficients[i]
+	}
+	return
+}
+
+// Split creates count shares of the secret and any threshold of them can recover it. The random reader is used for the coefficients and the identifier
+func Split(secret []byte, threshold, count int, random io.Reader) ([]*Share, error) {
+
+	if len(secret) == 0 {
+		return nil, errors.New("Secret is empty")
+	}
+	if threshold < 2 {
+		return nil, errors.New("Threshold must be at least 2")
+	}
+	if count < threshold {
+		return nil, errors.New("Shares count must be at least the threshold")
+	}
+	if count > 255 {
+		return nil, errors.New("Shares count must be at most 255")
+	}
+
+	id := make([]byte, 2)
+	if _, err := io.ReadFull(random, id); err != nil {
+		return nil, err
+	}
+
+	shares := make([]*Share, count)
+	for i := range shares {
+		shares[i] = &Share{
+			uint16(id[0])<<8 | uint16(id[1]),
+			byte(threshold),
+			byte(i + 1),
+			make([]byte, len(secret)),
+		}
+	}
+
+	coefficients := make([]byte, threshold)
+	for b := range secret {
+		coefficients[0] = secret[b]
+		if _, err := io.ReadFull(random, coefficients[1:]); err != nil {
+			return nil, err
+		}
+		for _, share := range shares {
+			share.Value[b] = evaluate(coefficients, share.Index)
+		}
+	}
+
+	for i := range coefficients {
+		coefficients[i] = 0
+	}
+
+	return shares, nil
+}
+
+// Combine recovers the secret using the Lagrange interpolation in 0. It requires at least Threshold distinct shares of the same split
+func Combine(shares []*Share) ([]byte, error) {
+
+	if len(shares) == 0 {
+		return nil, errors.New("No shares")
+	}
+
+	first := shares[0]
+	if first.Threshold < 2 || len(first.Value) == 0 {
+		return nil, errors.New("Share is invalid")
+	}
+
+	indexes := make(map[byte]bool)
+	for _, share := range shares {
+		if share.Identifier != first.Identifier || share.Threshold != first.Threshold || len(share.Value) != len(first.Value) {
+			return nil, errors.New("Shares are not from the same split")
+		}
+		if share.Index == 0 {
+			return nil, errors.New("Share index is invalid")
+		}
+		if indexes[share.Index] {
+			return nil, errors.New("Share is duplicated")
+		}
+		indexes[share.Index] = true
+	}
+
+	if len(shares) < int(first.Threshold) {
+		return nil, errors.New("Not enough shares")
+	}
+
+	shares = shares[:first.Threshold]
+
+	secret := make([]byte, len(first.Value))
+	for i, share := range shares {
+
+		basis := byte(1)
+		for j, other := range shares {
+			if i != j {
+				basis = gfMul(basis, gfDiv(other.Index, other.Index^share.Index))
+			}
+		}
+
+		for b := range secret {
+			secret[b] ^= gfMul(share.Value[b], basis)
+		}
+	}
+
+	return secret, nil
+}
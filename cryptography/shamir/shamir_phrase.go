@@ -0,0 +1,95 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"github.com/tyler-smith/go-bip39"
+	"pandora-pay/cryptography"
+	"strings"
+)
+
+const SHARE_PHRASE_CHECKSUM_PREFIX = "SHAMIR"
+
+const shareHeaderSize = 4
+
+func sharePhraseChecksum(data []byte) []byte {
+	return cryptography.SHA3(append([]byte(SHARE_PHRASE_CHECKSUM_PREFIX), data...))[:cryptography.ChecksumSize]
+}
+
+// EncodePhrase returns the share as words of the BIP39 word list. Every word stores 11 bits of identifier, threshold, index, value and checksum
+func (share *Share) EncodePhrase() string {
+
+	data := make([]byte, 0, shareHeaderSize+len(share.Value)+cryptography.ChecksumSize)
+	data = append(data, byte(share.Identifier>>8), byte(share.Identifier), share.Threshold, share.Index)
+	data = append(data, share.Value...)
+	data = append(data, sharePhraseChecksum(data)...)
+
+	list := bip39.GetWordList()
+	words := make([]string, 0, (len(data)*8+10)/11)
+
+	var acc, bits uint
+	for _, b := range data {
+		acc = acc<<8 | uint(b)
+		bits += 8
+		for bits >= 11 {
+			bits -= 11
+			words = append(words, list[acc>>bits&0x7ff])
+		}
+	}
+	if bits > 0 {
+		words = append(words, list[acc<<(11-bits)&0x7ff])
+	}
+
+	return strings.Join(words, " ")
+}
+
+// DecodeSharePhrase checks the checksum of the phrase. The length of the value is given by the number of words
+func DecodeSharePhrase(phrase string) (*Share, error) {
+
+	words := strings.Fields(strings.ToLower(phrase))
+	if len(words) == 0 {
+		return nil, errors.New("Share phrase is empty")
+	}
+
+	data := make([]byte, 0, len(words)*11/8+1)
+
+	var acc, bits uint
+	for _, word := range words {
+		index, ok := bip39.GetWordIndex(word)
+		if !ok {
+			return nil, errors.New("Share phrase has an invalid word: " + word)
+		}
+		acc = acc<<11 | uint(index)
+		bits += 11
+		for bits >= 8 {
+			bits -= 8
+			data = append(data, byte(acc>>bits))
+		}
+	}
+	if acc&(1<<bits-1) != 0 {
+		return nil, errors.New("Share phrase is invalid")
+	}
+
+	//the words may have more than 7 bits of padding, so the last byte can be padding as well
+	for size := len(data); size > shareHeaderSize+cryptography.ChecksumSize && (size*8+10)/11 == len(words); size-- {
+
+		payload, checksum := data[:size-cryptography.ChecksumSize], data[size-cryptography.ChecksumSize:size]
+		if !bytes.Equal(sharePhraseChecksum(payload), checksum) {
+			continue
+		}
+		for _, b := range data[size:] {
+			if b != 0 {
+				return nil, errors.New("Share phrase is invalid")
+			}
+		}
+
+		return &Share{
+			uint16(payload[0])<<8 | uint16(payload[1]),
+			payload[2],
+			payload[3],
+			append([]byte{}, payload[shareHeaderSize:]...),
+		}, nil
+	}
+
+	return nil, errors.New("Share phrase checksum is invalid")
+}
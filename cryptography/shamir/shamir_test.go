@@ -0,0 +1,137 @@
+package shamir
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/cryptography"
+	"strings"
+	"testing"
+)
+
+// deterministicReader returns the SHA3 chain of the seed, so the shares are always the same
+type deterministicReader struct {
+	state []byte
+	buf   []byte
+}
+
+func (r *deterministicReader) Read(p []byte) (int, error) {
+	for i := range p {
+		if len(r.buf) == 0 {
+			r.state = cryptography.SHA3(r.state)
+			r.buf = r.state
+		}
+		p[i], r.buf = r.buf[0], r.buf[1:]
+	}
+	return len(p), nil
+}
+
+func TestGF256(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		assert.Equal(t, byte(1), gfMul(byte(a), gfDiv(1, byte(a))))
+		for b := 1; b < 256; b += 17 {
+			assert.Equal(t, byte(a), gfDiv(gfMul(byte(a), byte(b)), byte(b)))
+		}
+	}
+	assert.Equal(t, byte(0xc1), gfMul(0x57, 0x83))
+}
+
+func TestSplitCombine(t *testing.T) {
+
+	secret, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+
+	shares, err := Split(secret, 3, 5, &deterministicReader{state: []byte("seed")})
+	assert.NoError(t, err)
+	assert.Len(t, shares, 5)
+
+	again, err := Split(secret, 3, 5, &deterministicReader{state: []byte("seed")})
+	assert.NoError(t, err)
+	assert.Equal(t, shares, again)
+
+	for i := 0; i < 5; i++ {
+		for j := i + 1; j < 5; j++ {
+			for k := j + 1; k < 5; k++ {
+				recovered, err := Combine([]*Share{shares[k], shares[i], shares[j]})
+				assert.NoError(t, err)
+				assert.Equal(t, secret, recovered)
+			}
+			_, err = Combine([]*Share{shares[i], shares[j]})
+			assert.Error(t, err)
+		}
+	}
+
+	_, err = Combine([]*Share{shares[0], shares[1], shares[1]})
+	assert.Error(t, err)
+
+	other, err := Split(secret, 3, 5, &deterministicReader{state: []byte("other")})
+	assert.NoError(t, err)
+	_, err = Combine([]*Share{shares[0], shares[1], other[2]})
+	assert.Error(t, err)
+
+	_, err = Split(secret, 1, 5, &deterministicReader{})
+	assert.Error(t, err)
+	_, err = Split(secret, 4, 3, &deterministicReader{})
+	assert.Error(t, err)
+}
+
+func TestSharePhrase(t *testing.T) {
+
+	for _, size := range []int{16, 32} {
+
+		secret := bytes.Repeat([]byte{0xa5}, size)
+		shares, err := Split(secret, 2, 3, &deterministicReader{state: []byte("phrase")})
+		assert.NoError(t, err)
+
+		phrases := make([]string, len(shares))
+		for i, share := range shares {
+			phrases[i] = share.EncodePhrase()
+
+			decoded, err := DecodeSharePhrase(strings.ToUpper(phrases[i]) + " ")
+			assert.NoError(t, err)
+			assert.Equal(t, share, decoded)
+		}
+
+		a, err := DecodeSharePhrase(phrases[2])
+		assert.NoError(t, err)
+		b, err := DecodeSharePhrase(phrases[0])
+		assert.NoError(t, err)
+		recovered, err := Combine([]*Share{a, b})
+		assert.NoError(t, err)
+		assert.Equal(t, secret, recovered)
+
+		words := strings.Fields(phrases[0])
+		words[5], words[6] = words[6], words[5]
+		_, err = DecodeSharePhrase(strings.Join(words, " "))
+		assert.Error(t, err)
+
+		_, err = DecodeSharePhrase(strings.Join(words[1:], " "))
+		assert.Error(t, err)
+	}
+
+	_, err := DecodeSharePhrase("abandon notaword")
+	assert.Error(t, err)
+}
+
+func TestSharePhraseVector(t *testing.T) {
+
+	secret, _ := hex.DecodeString("7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f")
+
+	shares, err := Split(secret, 2, 2, &deterministicReader{state: []byte("vector")})
+	assert.NoError(t, err)
+
+	phrases := []string{
+		"nice retreat leopard combine process bring travel biology fire obvious trip taste say bottom code similar surface day",
+		"nice retreat level food upgrade judge sniff garbage copper dice warfare satoshi island inquiry crisp lounge tray exotic",
+	}
+	for i, share := range shares {
+		assert.Equal(t, phrases[i], share.EncodePhrase())
+	}
+
+	a, err := DecodeSharePhrase(phrases[1])
+	assert.NoError(t, err)
+	b, err := DecodeSharePhrase(phrases[0])
+	assert.NoError(t, err)
+	recovered, err := Combine([]*Share{a, b})
+	assert.NoError(t, err)
+	assert.Equal(t, secret, recovered)
+}
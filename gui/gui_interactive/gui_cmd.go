@@ -39,16 +39,39 @@ var commands = []Command{
 	{Name: "Wallet", Text: "List Addresses"},
 	{Name: "Wallet", Text: "Scan Addresses"},
 	{Name: "Wallet", Text: "Create New Address"},
+	{Name: "Wallet", Text: "Create Co-Signed Address"},
 	{Name: "Wallet", Text: "Clear & Create new empty Wallet"},
 	{Name: "Wallet", Text: "Show Mnemnonic"},
 	{Name: "Wallet", Text: "Import Mnemnonic"},
 	{Name: "Wallet", Text: "Show Entropy"},
 	{Name: "Wallet", Text: "Import Entropy"},
+	{Name: "Wallet", Text: "Show Secret Shares"},
+	{Name: "Wallet", Text: "Import Secret Shares"},
 	{Name: "Wallet", Text: "Show Address Secret Key"},
 	{Name: "Wallet", Text: "Import Address Secret Key"},
 	{Name: "Wallet", Text: "Remove Address"},
 	{Name: "Wallet", Text: "Export Staked Staked Address"},
+	{Name: "Wallet", Text: "Show History"},
+	{Name: "Wallet", Text: "Export History"},
+	{Name: "Wallet", Text: "Create Invoice"},
+	{Name: "Wallet", Text: "Show Invoices"},
+	{Name: "Wallet", Text: "Create Payment Proof"},
+	{Name: "Wallet", Text: "Verify Payment Proof"},
+	{Name: "Wallet", Text: "List Contacts"},
+	{Name: "Wallet", Text: "Add Contact"},
+	{Name: "Wallet", Text: "Edit Contact"},
+	{Name: "Wallet", Text: "Remove Contact"},
+	{Name: "Wallet", Text: "Co-Signer Create Account"},
+	{Name: "Wallet", Text: "Co-Signer List Accounts"},
+	{Name: "Wallet", Text: "Co-Signer Audit Log"},
 	{Name: "Wallet:TX", Text: "Private Transfer"},
+	{Name: "Wallet:TX", Text: "Private Batch Transfer"},
+	{Name: "Wallet:TX", Text: "Private Transfer Prepare Offline"},
+	{Name: "Wallet:TX", Text: "Sign Offline Transaction"},
+	{Name: "Wallet:TX", Text: "Broadcast Offline Transaction"},
+	{Name: "Wallet:TX", Text: "Tx Privacy Report"},
+	{Name: "Wallet:TX", Text: "Create Balance Proofs"},
+	{Name: "Wallet:TX", Text: "Verify Balance Proof"},
 	{Name: "Wallet:TX", Text: "Private Delegate Stake"},
 	{Name: "Wallet:TX", Text: "Private Claim"},
 	{Name: "Wallet:TX", Text: "Private Asset Create"},
@@ -64,11 +87,20 @@ var commands = []Command{
 	{Name: "Wallet", Text: "Import Wallet JSON"},
 	{Name: "Wallet", Text: "Encrypt Wallet"},
 	{Name: "Wallet", Text: "Decrypt Wallet"},
+	{Name: "Wallet", Text: "Lock Wallet"},
 	{Name: "Wallet", Text: "Remove Encryption"},
+	{Name: "Wallet", Text: "List Wallets"},
+	{Name: "Wallet", Text: "Create Wallet"},
+	{Name: "Wallet", Text: "Load Wallet"},
+	{Name: "Wallet", Text: "Unload Wallet"},
+	{Name: "Wallet", Text: "Switch Wallet"},
 	{Name: "Utils", Text: "Create (PublicKey, PrivateKey) pair"},
 	{Name: "Utils", Text: "Sign message using PrivateKey"},
 	{Name: "Utils", Text: "Sign Resolution Conditional Payment"},
 	{Name: "Mempool", Text: "Show Txs"},
+	{Name: "App", Text: "API Tokens List"},
+	{Name: "App", Text: "API Token Create"},
+	{Name: "App", Text: "API Token Revoke"},
 	{Name: "App", Text: "Exit"},
 }
 var commandsLock sync.Mutex
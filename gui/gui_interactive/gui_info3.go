@@ -0,0 +1,32 @@
+package gui_interactive
+
+import (
+	"github.com/gizak/termui/v3/widgets"
+	"sort"
+)
+
+func (g *GUIInteractive) info3Render() {
+
+	rows := []string{}
+	g.info3Map.Range(func(key, value string) bool {
+		rows = append(rows, key+": "+value)
+		return true
+	})
+	sort.Strings(rows)
+	g.info3.Lock()
+	g.info3.Rows = rows
+	g.info3.Unlock()
+}
+
+func (g *GUIInteractive) Info3Update(key string, text string) {
+	if text == "" {
+		g.info3Map.Delete(key)
+		return
+	}
+	g.info3Map.Store(key, text)
+}
+
+func (g *GUIInteractive) info3Init() {
+	g.info3 = widgets.NewList()
+	g.info3.Title = "Forging"
+}
@@ -31,6 +31,9 @@ type GUIInteractive struct {
 	info2    *widgets.List
 	info2Map *generics.Map[string, string]
 
+	info3    *widgets.List
+	info3Map *generics.Map[string, string]
+
 	info    *widgets.List
 	infoMap *generics.Map[string, string]
 
@@ -61,6 +64,7 @@ func CreateGUIInteractive() (*GUIInteractive, error) {
 		logger:   logger,
 		infoMap:  &generics.Map[string, string]{},
 		info2Map: &generics.Map[string, string]{},
+		info3Map: &generics.Map[string, string]{},
 		cmdData:  &generics.Value[*GUIInteractiveData]{},
 	}
 
@@ -70,6 +74,7 @@ func CreateGUIInteractive() (*GUIInteractive, error) {
 
 	g.infoInit()
 	g.info2Init()
+	g.info3Init()
 	g.cmdInit()
 	g.logsInit()
 
@@ -79,8 +84,9 @@ func CreateGUIInteractive() (*GUIInteractive, error) {
 
 	grid.Set(
 		ui.NewRow(1.0/4,
-			ui.NewCol(1.0/2, g.info),
-			ui.NewCol(1.0/2, g.info2),
+			ui.NewCol(1.0/3, g.info),
+			ui.NewCol(1.0/3, g.info2),
+			ui.NewCol(1.0/3, g.info3),
 		),
 		ui.NewRow(1.0/4,
 			ui.NewCol(1.0/1, g.cmd),
@@ -116,9 +122,10 @@ func CreateGUIInteractive() (*GUIInteractive, error) {
 				}
 				g.infoRender()
 				g.info2Render()
+				g.info3Render()
 				g.logsRender()
 
-				ui.Render(g.info, g.info2, g.logs, g.cmd)
+				ui.Render(g.info, g.info2, g.info3, g.logs, g.cmd)
 			}
 
 		}
@@ -19,6 +19,7 @@ type GUIInterface interface {
 	Error(any ...any)
 	InfoUpdate(key string, text string)
 	Info2Update(key string, text string)
+	Info3Update(key string, text string)
 	OutputWrite(any ...any)
 	CommandDefineCallback(Text string, callback func(string, context.Context) error, useIt bool)
 	OutputReadString(text string) string
@@ -42,6 +42,9 @@ func (g *GUINonInteractive) InfoUpdate(key string, text string) {
 func (g *GUINonInteractive) Info2Update(key string, text string) {
 }
 
+func (g *GUINonInteractive) Info3Update(key string, text string) {
+}
+
 func (g *GUINonInteractive) OutputWrite(any ...interface{}) {
 }
 
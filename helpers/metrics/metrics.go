@@ -0,0 +1,235 @@
+package metrics
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const NAMESPACE = "mc_"
+
+type collector interface {
+	write(b *strings.Builder)
+}
+
+type registryType struct {
+	collectors []collector
+	lock       *sync.RWMutex
+}
+
+var registry = &registryType{
+	[]collector{},
+	&sync.RWMutex{},
+}
+
+func register(c collector) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	registry.collectors = append(registry.collectors, c)
+}
+
+func writeHeader(b *strings.Builder, name, help, metricType string) {
+	b.WriteString("# HELP " + name + " " + help + "\n")
+	b.WriteString("# TYPE " + name + " " + metricType + "\n")
+}
+
+func writeSample(b *strings.Builder, name, label, labelValue string, value float64) {
+	b.WriteString(name)
+	if label != "" {
+		b.WriteString("{" + label + "=" + strconv.Quote(labelValue) + "}")
+	}
+	b.WriteString(" " + strconv.FormatFloat(value, 'g', -1, 64) + "\n")
+}
+
+// Counter is a monotonically increasing value
+type Counter struct {
+	name  string
+	help  string
+	value uint64 //use atomic
+}
+
+func (c *Counter) Add(delta uint64) {
+	atomic.AddUint64(&c.value, delta)
+}
+
+func (c *Counter) Inc() {
+	atomic.AddUint64(&c.value, 1)
+}
+
+func (c *Counter) Get() uint64 {
+	return atomic.LoadUint64(&c.value)
+}
+
+func (c *Counter) write(b *strings.Builder) {
+	writeHeader(b, c.name, c.help, "counter")
+	writeSample(b, c.name, "", "", float64(c.Get()))
+}
+
+// CounterVec is a set of counters partitioned by the value of a single label
+type CounterVec struct {
+	name     string
+	help     string
+	label    string
+	counters map[string]*Counter
+	lock     *sync.RWMutex
+}
+
+func (v *CounterVec) WithLabel(labelValue string) *Counter {
+
+	v.lock.RLock()
+	c := v.counters[labelValue]
+	v.lock.RUnlock()
+	if c != nil {
+		return c
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if c = v.counters[labelValue]; c == nil {
+		c = &Counter{}
+		v.counters[labelValue] = c
+	}
+	return c
+}
+
+func (v *CounterVec) write(b *strings.Builder) {
+
+	v.lock.RLock()
+	labels := make([]string, 0, len(v.counters))
+	for labelValue := range v.counters {
+		labels = append(labels, labelValue)
+	}
+	v.lock.RUnlock()
+	sort.Strings(labels)
+
+	writeHeader(b, v.name, v.help, "counter")
+	for _, labelValue := range labels {
+		writeSample(b, v.name, v.label, labelValue, float64(v.WithLabel(labelValue).Get()))
+	}
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct {
+	name  string
+	help  string
+	value int64 //use atomic
+}
+
+func (g *Gauge) Add(delta int64) {
+	atomic.AddInt64(&g.value, delta)
+}
+
+func (g *Gauge) Set(value int64) {
+	atomic.StoreInt64(&g.value, value)
+}
+
+func (g *Gauge) Get() int64 {
+	return atomic.LoadInt64(&g.value)
+}
+
+func (g *Gauge) write(b *strings.Builder) {
+	writeHeader(b, g.name, g.help, "gauge")
+	writeSample(b, g.name, "", "", float64(g.Get()))
+}
+
+// GaugeFunc reads the value only when the metrics are collected
+type GaugeFunc struct {
+	name  string
+	help  string
+	label string
+	fn    func() map[string]float64
+}
+
+func (g *GaugeFunc) write(b *strings.Builder) {
+
+	values := g.fn()
+
+	labels := make([]string, 0, len(values))
+	for labelValue := range values {
+		labels = append(labels, labelValue)
+	}
+	sort.Strings(labels)
+
+	writeHeader(b, g.name, g.help, "gauge")
+	for _, labelValue := range labels {
+		writeSample(b, g.name, g.label, labelValue, values[labelValue])
+	}
+}
+
+// Summary tracks the count and the total duration of the observed events
+type Summary struct {
+	name  string
+	help  string
+	count uint64 //use atomic
+	sum   uint64 //nanoseconds, use atomic
+}
+
+func (s *Summary) Observe(duration time.Duration) {
+	atomic.AddUint64(&s.count, 1)
+	atomic.AddUint64(&s.sum, uint64(duration))
+}
+
+func (s *Summary) write(b *strings.Builder) {
+	writeHeader(b, s.name, s.help, "summary")
+	writeSample(b, s.name+"_sum", "", "", float64(atomic.LoadUint64(&s.sum))/float64(time.Second))
+	writeSample(b, s.name+"_count", "", "", float64(atomic.LoadUint64(&s.count)))
+}
+
+func NewCounter(name, help string) *Counter {
+	c := &Counter{NAMESPACE + name, help, 0}
+	register(c)
+	return c
+}
+
+func NewCounterVec(name, help, label string) *CounterVec {
+	v := &CounterVec{NAMESPACE + name, help, label, make(map[string]*Counter), &sync.RWMutex{}}
+	register(v)
+	return v
+}
+
+func NewGauge(name, help string) *Gauge {
+	g := &Gauge{NAMESPACE + name, help, 0}
+	register(g)
+	return g
+}
+
+func NewSummary(name, help string) *Summary {
+	s := &Summary{NAMESPACE + name, help, 0, 0}
+	register(s)
+	return s
+}
+
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	g := &GaugeFunc{NAMESPACE + name, help, "", func() map[string]float64 {
+		return map[string]float64{"": fn()}
+	}}
+	register(g)
+	return g
+}
+
+func NewGaugeVecFunc(name, help, label string, fn func() map[string]float64) *GaugeFunc {
+	g := &GaugeFunc{NAMESPACE + name, help, label, fn}
+	register(g)
+	return g
+}
+
+// Write outputs all the registered metrics using the Prometheus text format
+func Write(w io.Writer) error {
+
+	registry.lock.RLock()
+	collectors := make([]collector, len(registry.collectors))
+	copy(collectors, registry.collectors)
+	registry.lock.RUnlock()
+
+	b := &strings.Builder{}
+	for _, c := range collectors {
+		c.write(b)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
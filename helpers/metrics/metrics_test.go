@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_Write(t *testing.T) {
+
+	counter := NewCounterVec("test_messages_total", "Test messages", "route")
+	counter.WithLabel("b").Add(3)
+	counter.WithLabel("a").Inc()
+
+	gauge := NewGauge("test_queue", "Test queue")
+	gauge.Add(5)
+	gauge.Add(-2)
+
+	summary := NewSummary("test_latency_seconds", "Test latency")
+	summary.Observe(500 * time.Millisecond)
+	summary.Observe(time.Second)
+
+	NewGaugeVecFunc("test_peers", "Test peers", "direction", func() map[string]float64 {
+		return map[string]float64{"in": 2, "out": 7}
+	})
+
+	b := &strings.Builder{}
+	assert.Nil(t, Write(b))
+	out := b.String()
+
+	assert.Contains(t, out, "# TYPE mc_test_messages_total counter\nmc_test_messages_total{route=\"a\"} 1\nmc_test_messages_total{route=\"b\"} 3\n")
+	assert.Contains(t, out, "# TYPE mc_test_queue gauge\nmc_test_queue 3\n")
+	assert.Contains(t, out, "mc_test_latency_seconds_sum 1.5\nmc_test_latency_seconds_count 2\n")
+	assert.Contains(t, out, "mc_test_peers{direction=\"in\"} 2\nmc_test_peers{direction=\"out\"} 7\n")
+}
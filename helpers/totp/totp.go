@@ -0,0 +1,116 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RFC 6238 time based one time passwords, compatible with the usual authenticator apps
+
+const (
+	SECRET_SIZE = 20
+	PERIOD      = 30
+	DIGITS      = 6
+	SKEW        = 1 //periods accepted before and after the current one
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func GenerateSecret() ([]byte, error) {
+	secret := make([]byte, SECRET_SIZE)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// EncodeSecret returns the secret in base32 as it is typed in the authenticator apps
+func EncodeSecret(secret []byte) string {
+	return encoding.EncodeToString(secret)
+}
+
+func DecodeSecret(secret string) ([]byte, error) {
+	return encoding.DecodeString(strings.ToUpper(strings.ReplaceAll(secret, " ", "")))
+}
+
+func GenerateCodeCounter(secret []byte, counter uint64) string {
+
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, counter)
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(buf)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", DIGITS, value%1000000)
+}
+
+func GenerateCode(secret []byte, t time.Time) string {
+	return GenerateCodeCounter(secret, uint64(t.Unix())/PERIOD)
+}
+
+// validateCounter returns the period of the accepted code
+func validateCounter(secret []byte, code string, t time.Time) (uint64, bool) {
+
+	code = strings.TrimSpace(code)
+	if len(code) != DIGITS {
+		return 0, false
+	}
+
+	counter := uint64(t.Unix()) / PERIOD
+	for i := -SKEW; i <= SKEW; i++ {
+		if subtle.ConstantTimeCompare([]byte(GenerateCodeCounter(secret, counter+uint64(i))), []byte(code)) == 1 {
+			return counter + uint64(i), true
+		}
+	}
+	return 0, false
+}
+
+func Validate(secret []byte, code string, t time.Time) bool {
+	_, ok := validateCounter(secret, code, t)
+	return ok
+}
+
+// Guard remembers the period of the last accepted code to reject the replayed codes and locks the validation after too many failed attempts
+type Guard struct {
+	LastCounter    uint64 `json:"-" msgpack:"lastCounter"`
+	FailedAttempts int    `json:"-" msgpack:"failedAttempts"`
+	LockedUntil    int64  `json:"lockedUntil,omitempty" msgpack:"lockedUntil"`
+}
+
+func (guard *Guard) Validate(secret []byte, code string, t time.Time, maxFailedAttempts int, lockout time.Duration) error {
+
+	if guard.LockedUntil > t.Unix() {
+		return errors.New("Too many invalid confirmation codes. Try again later")
+	}
+
+	counter, ok := validateCounter(secret, code, t)
+	if ok && counter <= guard.LastCounter {
+		return errors.New("Confirmation code was already used")
+	}
+
+	if !ok {
+		guard.FailedAttempts += 1
+		if maxFailedAttempts > 0 && guard.FailedAttempts >= maxFailedAttempts {
+			guard.FailedAttempts = 0
+			guard.LockedUntil = t.Add(lockout).Unix()
+		}
+		return errors.New("Confirmation code is invalid")
+	}
+
+	guard.LastCounter = counter
+	guard.FailedAttempts = 0
+	guard.LockedUntil = 0
+	return nil
+}
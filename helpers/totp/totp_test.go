@@ -0,0 +1,59 @@
+package totp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGenerateCode(t *testing.T) {
+
+	//RFC 6238 test vectors truncated to 6 digits
+	secret := []byte("12345678901234567890")
+	assert.Equal(t, "287082", GenerateCode(secret, time.Unix(59, 0)))
+	assert.Equal(t, "081804", GenerateCode(secret, time.Unix(1111111109, 0)))
+	assert.Equal(t, "050471", GenerateCode(secret, time.Unix(1111111111, 0)))
+	assert.Equal(t, "005924", GenerateCode(secret, time.Unix(1234567890, 0)))
+	assert.Equal(t, "279037", GenerateCode(secret, time.Unix(2000000000, 0)))
+}
+
+func TestValidate(t *testing.T) {
+
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	code := GenerateCode(secret, now)
+
+	assert.True(t, Validate(secret, code, now))
+	assert.True(t, Validate(secret, code, now.Add(PERIOD*time.Second)))
+	assert.False(t, Validate(secret, code, now.Add(3*PERIOD*time.Second)))
+	assert.False(t, Validate(secret, "", now))
+
+	decoded, err := DecodeSecret(EncodeSecret(secret))
+	assert.NoError(t, err)
+	assert.Equal(t, secret, decoded)
+}
+
+func TestGuard(t *testing.T) {
+
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	guard := &Guard{}
+
+	assert.NoError(t, guard.Validate(secret, GenerateCode(secret, now), now, 3, time.Minute))
+	assert.Error(t, guard.Validate(secret, GenerateCode(secret, now), now, 3, time.Minute), "replayed code")
+	assert.Error(t, guard.Validate(secret, GenerateCode(secret, now.Add(-PERIOD*time.Second)), now, 3, time.Minute), "older code")
+
+	now = now.Add(PERIOD * time.Second)
+	for i := 0; i < 3; i++ {
+		assert.Error(t, guard.Validate(secret, "abcdef", now, 3, time.Minute))
+	}
+	assert.Error(t, guard.Validate(secret, GenerateCode(secret, now), now, 3, time.Minute), "locked")
+
+	now = now.Add(time.Minute + time.Second)
+	assert.NoError(t, guard.Validate(secret, GenerateCode(secret, now), now, 3, time.Minute))
+	assert.Equal(t, 0, guard.FailedAttempts)
+}
@@ -24,6 +24,7 @@ type MempoolAccountTxs struct {
 
 type MempoolTxs struct {
 	count                     int32
+	bytes                     int64 //use atomic
 	txsMap                    *generics.Map[string, *mempoolTx]
 	accountsMapTxs            *generics.Map[string, *MempoolAccountTxs]
 	UpdateMempoolTransactions *multicast.MulticastChannel[*blockchain_types.MempoolTransactionUpdate]
@@ -33,6 +34,7 @@ func (self *MempoolTxs) insertTx(tx *mempoolTx) bool {
 	_, loaded := self.txsMap.LoadOrStore(tx.Tx.Bloom.HashStr, tx)
 	if !loaded {
 		atomic.AddInt32(&self.count, 1)
+		atomic.AddInt64(&self.bytes, int64(len(tx.Tx.Bloom.Serialized)))
 	}
 	return !loaded
 }
@@ -71,9 +73,10 @@ func (self *MempoolTxs) inserted(tx *mempoolTx) {
 }
 
 func (self *MempoolTxs) deleteTx(hashStr string) bool {
-	_, deleted := self.txsMap.LoadAndDelete(hashStr)
+	tx, deleted := self.txsMap.LoadAndDelete(hashStr)
 	if deleted {
 		atomic.AddInt32(&self.count, -1)
+		atomic.AddInt64(&self.bytes, -int64(len(tx.Tx.Bloom.Serialized)))
 	}
 	return deleted
 }
@@ -107,6 +110,14 @@ func (self *MempoolTxs) deleted(tx *mempoolTx, broadcastNotifications, includedI
 	}
 }
 
+func (self *MempoolTxs) GetCount() int32 {
+	return atomic.LoadInt32(&self.count)
+}
+
+func (self *MempoolTxs) GetBytes() int64 {
+	return atomic.LoadInt64(&self.bytes)
+}
+
 func (self *MempoolTxs) GetTxsFromMap() (out map[string]*mempoolTx) {
 
 	out = make(map[string]*mempoolTx)
@@ -182,6 +193,7 @@ func (self *MempoolTxs) GetAccountTxs(publicKey []byte) []*mempoolTx {
 func createMempoolTxs() (txs *MempoolTxs) {
 
 	txs = &MempoolTxs{
+		0,
 		0,
 		&generics.Map[string, *mempoolTx]{},
 		&generics.Map[string, *MempoolAccountTxs]{},
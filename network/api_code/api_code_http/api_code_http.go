@@ -7,14 +7,16 @@ import (
 	"net/url"
 	"pandora-pay/helpers/urldecoder"
 	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/network_config/network_config_auth"
 )
 
-func HandleAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values url.Values) (interface{}, error) {
+func HandleAuthenticated[T any, B any](scope network_config_auth.AuthScope, callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values url.Values) (interface{}, error) {
 	return func(values url.Values) (interface{}, error) {
 
-		authenticated := api_code_types.CheckAuthenticated(values)
+		authenticated := api_code_types.CheckAuthenticated(values, scope)
 		values.Del("user")
 		values.Del("pass")
+		values.Del("token")
 
 		args := new(T)
 		if err := urldecoder.Decoder.Decode(args, values); err != nil {
@@ -38,7 +40,7 @@ func Handle[T any, B any](callback func(r *http.Request, args *T, reply *B) erro
 	}
 }
 
-func HandlePOSTAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values io.ReadCloser) (interface{}, error) {
+func HandlePOSTAuthenticated[T any, B any](scope network_config_auth.AuthScope, callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values io.ReadCloser) (interface{}, error) {
 	return func(values io.ReadCloser) (interface{}, error) {
 
 		authenticated := new(api_code_types.APIAuthenticated[T])
@@ -47,7 +49,7 @@ func HandlePOSTAuthenticated[T any, B any](callback func(r *http.Request, args *
 		}
 
 		reply := new(B)
-		return reply, callback(nil, authenticated.Data, reply, authenticated.CheckAuthenticated())
+		return reply, callback(nil, authenticated.Data, reply, authenticated.CheckAuthenticated(scope))
 	}
 }
 
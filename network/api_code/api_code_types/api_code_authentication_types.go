@@ -5,27 +5,19 @@ import (
 	"pandora-pay/network/network_config/network_config_auth"
 )
 
-func CheckAuthenticated(args url.Values) bool {
-
-	user := network_config_auth.CONFIG_AUTH_USERS_MAP[args.Get("user")]
-	if user == nil {
-		return false
-	}
-
-	return user.Password == args.Get("pass")
+func CheckAuthenticated(args url.Values, scope network_config_auth.AuthScope) bool {
+	_, scopes := network_config_auth.Authenticate(args.Get("user"), args.Get("pass"), args.Get("token"))
+	return network_config_auth.HasScope(scopes, scope)
 }
 
 type APIAuthenticated[T any] struct {
-	User string `json:"user" msgpack:"user"`
-	Pass string `json:"pass" msgpack:"pass"`
-	Data *T     `json:"req" msgpack:"req"`
+	User  string `json:"user" msgpack:"user"`
+	Pass  string `json:"pass" msgpack:"pass"`
+	Token string `json:"token" msgpack:"token"`
+	Data  *T     `json:"req" msgpack:"req"`
 }
 
-func (authenticated *APIAuthenticated[T]) CheckAuthenticated() bool {
-	user := network_config_auth.CONFIG_AUTH_USERS_MAP[authenticated.User]
-	if user == nil {
-		return false
-	}
-
-	return user.Password == authenticated.Pass
+func (authenticated *APIAuthenticated[T]) CheckAuthenticated(scope network_config_auth.AuthScope) bool {
+	_, scopes := network_config_auth.Authenticate(authenticated.User, authenticated.Pass, authenticated.Token)
+	return network_config_auth.HasScope(scopes, scope)
 }
@@ -13,6 +13,8 @@ const (
 	SUBSCRIPTION_ASSET
 	SUBSCRIPTION_REGISTRATION
 	SUBSCRIPTION_TRANSACTION
+	SUBSCRIPTION_WALLET_INVOICE
+	SUBSCRIPTION_PENDING_STAKE
 )
 
 type APISubscriptionNotification struct {
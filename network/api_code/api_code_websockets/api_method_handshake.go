@@ -4,8 +4,9 @@ import (
 	"pandora-pay/config"
 	"pandora-pay/network/network_config"
 	"pandora-pay/network/websocks/connection"
+	"time"
 )
 
 func Handshake(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
-	return &connection.ConnectionHandshake{config.NAME, config.VERSION_STRING, config.NETWORK_SELECTED, config.NODE_CONSENSUS, network_config.NETWORK_WEBSOCKET_ADDRESS_URL_STRING}, nil
+	return &connection.ConnectionHandshake{config.NAME, config.VERSION_STRING, config.NETWORK_SELECTED, config.NODE_CONSENSUS, network_config.NETWORK_WEBSOCKET_ADDRESS_URL_STRING, time.Now().UnixMilli()}, nil
 }
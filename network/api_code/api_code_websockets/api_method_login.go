@@ -9,6 +9,7 @@ import (
 type APILogin struct {
 	Username string `json:"user" msgpack:"user"`
 	Password string `json:"pass" msgpack:"pass"`
+	Token    string `json:"token" msgpack:"token"`
 }
 
 type APILoginReply struct {
@@ -22,11 +23,13 @@ func Login(conn *connection.AdvancedConnection, values []byte) (interface{}, err
 	}
 	reply := &APILoginReply{}
 
-	user := network_config_auth.CONFIG_AUTH_USERS_MAP[args.Username]
-	if user == nil || user.Password != args.Password {
+	name, scopes := network_config_auth.Authenticate(args.Username, args.Password, args.Token)
+	if len(scopes) == 0 {
 		return reply, nil
 	}
 
+	conn.AuthName.Store(name)
+	conn.AuthScopes.Store(scopes)
 	conn.Authenticated.Set()
 	reply.Status = true
 
@@ -17,6 +17,8 @@ func Logout(conn *connection.AdvancedConnection, values []byte) (interface{}, er
 	}
 
 	conn.Authenticated.UnSet()
+	conn.AuthScopes.Store(nil)
+	conn.AuthName.Store("")
 	reply.Status = true
 
 	return reply, nil
@@ -5,10 +5,12 @@ import (
 	"pandora-pay/blockchain"
 	"pandora-pay/blockchain/blockchain_sync"
 	"pandora-pay/config"
+	"pandora-pay/config/config_cosigner"
 	"pandora-pay/config/config_nodes"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/mempool"
+	"pandora-pay/network/api_implementation/api_common/api_cosigner"
 	"pandora-pay/network/api_implementation/api_common/api_delegator_node"
 	"pandora-pay/network/api_implementation/api_common/api_faucet"
 	"pandora-pay/wallet"
@@ -29,10 +31,12 @@ type APICommon struct {
 	localChainSync            *generics.Value[*blockchain_sync.BlockchainSyncData]
 	Faucet                    *api_faucet.Faucet
 	DelegatorNode             *api_delegator_node.DelegatorNode
+	Cosigner                  *api_cosigner.Cosigner
 	ApiStore                  *APIStore
 	mempoolProcessedThisBlock *generics.Value[*generics.Map[string, *mempoolNewTxReply]]
 	temporaryList             *generics.Value[*APINetworkNodesReply]
 	temporaryListCreation     *generics.Value[time.Time]
+	stakingEstimate           *generics.Value[*apiStakingEstimateNetwork]
 }
 
 //make sure it is safe to read
@@ -73,6 +77,11 @@ func NewAPICommon(mempool *mempool.Mempool, chain *blockchain.Blockchain, wallet
 		delegatorNode = api_delegator_node.NewDelegatorNode(chain, wallet)
 	}
 
+	var cosigner *api_cosigner.Cosigner
+	if config_cosigner.COSIGNER_ENABLED {
+		cosigner = api_cosigner.NewCosigner(wallet)
+	}
+
 	api = &APICommon{
 		mempool,
 		chain,
@@ -81,10 +90,12 @@ func NewAPICommon(mempool *mempool.Mempool, chain *blockchain.Blockchain, wallet
 		&generics.Value[*blockchain_sync.BlockchainSyncData]{},
 		faucet,
 		delegatorNode,
+		cosigner,
 		apiStore,
 		&generics.Value[*generics.Map[string, *mempoolNewTxReply]]{},
 		&generics.Value[*APINetworkNodesReply]{},
 		&generics.Value[time.Time]{},
+		&generics.Value[*apiStakingEstimateNetwork]{},
 	}
 
 	api.temporaryListCreation.Store(time.Now())
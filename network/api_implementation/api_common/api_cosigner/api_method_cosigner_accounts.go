@@ -0,0 +1,77 @@
+package api_cosigner
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/totp"
+	"pandora-pay/wallet"
+)
+
+type ApiCosignerCreateAccountRequest struct {
+	Name   string                       `json:"name" msgpack:"name"`
+	Policy *wallet.WalletCosignerPolicy `json:"policy" msgpack:"policy"`
+}
+
+type ApiCosignerCreateAccountReply struct {
+	SpendPublicKey helpers.Base64 `json:"spendPublicKey" msgpack:"spendPublicKey"`
+	TOTPSecret     string         `json:"totpSecret,omitempty" msgpack:"totpSecret,omitempty"` //base32, displayed only once
+}
+
+type ApiCosignerUpdateAccountRequest struct {
+	SpendPublicKey helpers.Base64               `json:"spendPublicKey" msgpack:"spendPublicKey"`
+	Policy         *wallet.WalletCosignerPolicy `json:"policy" msgpack:"policy"`
+}
+
+type ApiCosignerUpdateAccountReply struct {
+	TOTPSecret string `json:"totpSecret,omitempty" msgpack:"totpSecret,omitempty"` //base32, only when the confirmation codes were enabled
+}
+
+type ApiCosignerAccountsReply struct {
+	Accounts []*wallet.WalletCosignerAccount `json:"accounts" msgpack:"accounts"`
+}
+
+func (api *Cosigner) CreateAccount(r *http.Request, args *ApiCosignerCreateAccountRequest, reply *ApiCosignerCreateAccountReply, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	account, totpSecret, err := api.wallet.CreateCosignerAccount(args.Name, args.Policy)
+	if err != nil {
+		return err
+	}
+
+	reply.SpendPublicKey = account.SpendPublicKey
+	if totpSecret != nil {
+		reply.TOTPSecret = totp.EncodeSecret(totpSecret)
+	}
+	return nil
+}
+
+func (api *Cosigner) UpdateAccount(r *http.Request, args *ApiCosignerUpdateAccountRequest, reply *ApiCosignerUpdateAccountReply, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	totpSecret, err := api.wallet.UpdateCosignerAccount(args.SpendPublicKey, args.Policy)
+	if err != nil {
+		return err
+	}
+
+	if totpSecret != nil {
+		reply.TOTPSecret = totp.EncodeSecret(totpSecret)
+	}
+	return nil
+}
+
+func (api *Cosigner) GetAccounts(r *http.Request, args *struct{}, reply *ApiCosignerAccountsReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Accounts, err = api.wallet.GetCosignerAccounts()
+	return
+}
@@ -0,0 +1,34 @@
+package api_cosigner
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/wallet"
+)
+
+type ApiCosignerAuditLogRequest struct {
+	Start int `json:"start,omitempty" msgpack:"start,omitempty"`
+	Count int `json:"count,omitempty" msgpack:"count,omitempty"`
+}
+
+type ApiCosignerAuditLogReply struct {
+	Entries []*wallet.WalletCosignerAuditEntry `json:"entries" msgpack:"entries"`
+	Total   int                                `json:"total" msgpack:"total"`
+}
+
+func (api *Cosigner) GetAuditLog(r *http.Request, args *ApiCosignerAuditLogRequest, reply *ApiCosignerAuditLogReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if args.Count <= 0 || args.Count > 100 {
+		args.Count = 100
+	}
+	if args.Start < 0 {
+		args.Start = 0
+	}
+
+	reply.Entries, reply.Total, err = api.wallet.GetCosignerAuditLog(args.Start, args.Count)
+	return
+}
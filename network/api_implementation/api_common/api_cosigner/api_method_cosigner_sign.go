@@ -0,0 +1,40 @@
+package api_cosigner
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/wallet"
+)
+
+type ApiCosignerSignRequest struct {
+	Tx             helpers.Base64 `json:"tx" msgpack:"tx"` //serialized with an empty spend signature
+	PayloadIndex   int            `json:"payloadIndex" msgpack:"payloadIndex"`
+	R              helpers.Base64 `json:"r" msgpack:"r"`
+	SenderIndex    int            `json:"senderIndex" msgpack:"senderIndex"`
+	RecipientIndex int            `json:"recipientIndex" msgpack:"recipientIndex"`
+	Amount         uint64         `json:"amount" msgpack:"amount"`
+	Code           string         `json:"code,omitempty" msgpack:"code,omitempty"`
+}
+
+type ApiCosignerSignReply struct {
+	Signature helpers.Base64 `json:"signature" msgpack:"signature"`
+}
+
+func (api *Cosigner) Sign(r *http.Request, args *ApiCosignerSignRequest, reply *ApiCosignerSignReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Signature, err = api.wallet.CosignerSign(&wallet.WalletCosignerSignRequest{
+		args.Tx,
+		args.PayloadIndex,
+		args.R,
+		args.SenderIndex,
+		args.RecipientIndex,
+		args.Amount,
+		args.Code,
+	})
+	return
+}
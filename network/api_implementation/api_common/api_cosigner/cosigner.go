@@ -0,0 +1,15 @@
+package api_cosigner
+
+import (
+	"pandora-pay/wallet"
+)
+
+type Cosigner struct {
+	wallet *wallet.Wallet
+}
+
+func NewCosigner(wallet *wallet.Wallet) *Cosigner {
+	return &Cosigner{
+		wallet,
+	}
+}
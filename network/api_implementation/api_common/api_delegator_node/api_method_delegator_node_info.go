@@ -3,6 +3,7 @@ package api_delegator_node
 import (
 	"net/http"
 	"pandora-pay/config/config_nodes"
+	"pandora-pay/network/network_config"
 	"sync/atomic"
 )
 
@@ -10,11 +11,17 @@ type ApiDelegatorNodeInfoReply struct {
 	MaximumAllowed int    `json:"maximumAllowed" msgpack:"maximumAllowed"`
 	DelegatesCount int    `json:"delegatesCount" msgpack:"delegatesCount"`
 	Blocks         uint64 `json:"blocks" msgpack:"blocks"`
+	FeeRate        uint64 `json:"feeRate" msgpack:"feeRate"` //commission taken from the staking rewards, in FeeDivisor parts
+	FeeDivisor     uint64 `json:"feeDivisor" msgpack:"feeDivisor"`
+	Origin         string `json:"origin" msgpack:"origin"` //URL of the node signed in the revokes
 }
 
 func (api *DelegatorNode) GetDelegatorNodeInfo(r *http.Request, args *struct{}, reply *ApiDelegatorNodeInfoReply) error {
 	reply.MaximumAllowed = config_nodes.DELEGATES_MAXIMUM
 	reply.DelegatesCount = api.wallet.GetDelegatesCount()
 	reply.Blocks = atomic.LoadUint64(&api.chainHeight)
+	reply.FeeRate = config_nodes.DELEGATOR_FEE
+	reply.FeeDivisor = config_nodes.DELEGATOR_FEE_DIVISOR
+	reply.Origin = network_config.NETWORK_ADDRESS_URL_STRING
 	return nil
 }
@@ -0,0 +1,21 @@
+package api_delegator_node
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/wallet"
+)
+
+type ApiDelegatorNodeListReply struct {
+	Delegations []*wallet.WalletDelegation `json:"delegations" msgpack:"delegations"`
+}
+
+func (api *DelegatorNode) GetDelegatorList(r *http.Request, args *struct{}, reply *ApiDelegatorNodeListReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Delegations, err = api.wallet.GetDelegations()
+	return
+}
@@ -3,6 +3,7 @@ package api_delegator_node
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"net/http"
 	"pandora-pay/addresses"
 	"pandora-pay/blockchain/data_storage"
@@ -22,6 +23,7 @@ import (
 type ApiDelegatorNodeNotifyRequest struct {
 	SharedStakedPrivateKey helpers.Base64 `json:"sharedStakedPrivateKey" msgpack:"sharedStakedPrivateKey"`
 	SharedStakedBalance    uint64         `json:"sharedStakedBalance" msgpack:"sharedStakedBalance"`
+	FeeRate                uint64         `json:"feeRate" msgpack:"feeRate"` //the commission advertised by delegator-node/info
 }
 
 type ApiDelegatorNodeNotifyReply struct {
@@ -34,6 +36,10 @@ func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNod
 		return errors.New("Invalid User or Password")
 	}
 
+	if args.FeeRate != config_nodes.DELEGATOR_FEE {
+		return fmt.Errorf("Fee rate is not matching. The delegator node commission is %d", config_nodes.DELEGATOR_FEE)
+	}
+
 	sharedStakedPrivateKey, err := addresses.NewPrivateKey(args.SharedStakedPrivateKey)
 	if err != nil {
 		return
@@ -92,7 +98,7 @@ func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNod
 		return errors.New("Your stake is not accepted because you will need at least the minimum staking amount")
 	}
 
-	if err = api.wallet.AddSharedStakedAddress(&wallet_address.WalletAddress{
+	if err = api.wallet.AddDelegation(&wallet_address.WalletAddress{
 		wallet_address.VERSION_NORMAL,
 		"Delegated Stake",
 		0,
@@ -106,14 +112,16 @@ func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNod
 		true,
 		false,
 		nil,
+		false,
 		true,
 		&shared_staked.WalletAddressSharedStaked{
 			sharedStakedPrivateKey,
 			sharedStakedPublicKey,
 		},
+		nil,
 		"",
 		"",
-	}, true); err != nil {
+	}, args.SharedStakedBalance, args.FeeRate, chainHeight); err != nil {
 		return
 	}
 
@@ -0,0 +1,82 @@
+package api_delegator_node
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/config"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/network/network_config"
+	"time"
+)
+
+const DELEGATOR_REVOKE_TIMESTAMP_TOLERANCE = uint64(10 * 60) //seconds
+
+type ApiDelegatorNodeRevokeRequest struct {
+	PublicKey helpers.Base64 `json:"publicKey" msgpack:"publicKey"`
+	Timestamp uint64         `json:"timestamp" msgpack:"timestamp"`
+	Signature helpers.Base64 `json:"signature" msgpack:"signature"` //signature of GetRevokeMessage by the delegated account
+}
+
+type ApiDelegatorNodeRevokeReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+// GetRevokeMessage returns the hash signed by the account to revoke its delegated stake. The origin is the URL of the delegator node returned by delegator-node/info
+func GetRevokeMessage(origin string, publicKey []byte, timestamp uint64) []byte {
+	w := advanced_buffers.NewBufferWriter()
+	w.Write([]byte("DELEGATOR_REVOKE"))
+	w.WriteUvarint(config.NETWORK_SELECTED)
+	w.WriteString(origin)
+	w.Write(publicKey)
+	w.WriteUvarint(timestamp)
+	return cryptography.SHA3(w.Bytes())
+}
+
+func (api *DelegatorNode) DelegatorRevoke(r *http.Request, args *ApiDelegatorNodeRevokeRequest, reply *ApiDelegatorNodeRevokeReply, authenticated bool) (err error) {
+
+	if config_nodes.DELEGATOR_REQUIRE_AUTH && !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if len(args.PublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid publicKey")
+	}
+
+	now := uint64(time.Now().Unix())
+	if args.Timestamp+DELEGATOR_REVOKE_TIMESTAMP_TOLERANCE < now || args.Timestamp > now+DELEGATOR_REVOKE_TIMESTAMP_TOLERANCE {
+		return errors.New("Timestamp is too far from the node time")
+	}
+
+	if len(args.Signature) != cryptography.SignatureSize || !crypto.VerifySignature(GetRevokeMessage(network_config.NETWORK_ADDRESS_URL_STRING, args.PublicKey, args.Timestamp), args.Signature, args.PublicKey) {
+		return errors.New("Signature is invalid")
+	}
+
+	api.revokesLock.Lock()
+	defer api.revokesLock.Unlock()
+
+	//the timestamps outside the tolerance are rejected anyway
+	for key, timestamp := range api.revokes {
+		if timestamp+DELEGATOR_REVOKE_TIMESTAMP_TOLERANCE < now {
+			delete(api.revokes, key)
+		}
+	}
+
+	//a signed revoke can be used only once, otherwise it could revoke the stake delegated again
+	if args.Timestamp <= api.revokes[string(args.PublicKey)] {
+		return errors.New("Revoke was already used. Sign it again with a newer timestamp")
+	}
+	api.revokes[string(args.PublicKey)] = args.Timestamp
+
+	if reply.Result, err = api.wallet.RevokeDelegation(args.PublicKey); err != nil {
+		return
+	}
+	if !reply.Result {
+		return errors.New("Delegation doesn't exist")
+	}
+
+	return nil
+}
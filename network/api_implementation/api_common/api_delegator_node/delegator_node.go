@@ -1,14 +1,44 @@
 package api_delegator_node
 
 import (
+	"context"
 	"pandora-pay/blockchain"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/recovery"
 	"pandora-pay/wallet"
+	"sync"
+	"sync/atomic"
 )
 
 type DelegatorNode struct {
 	chainHeight uint64 //use atomic
 	wallet      *wallet.Wallet
 	chain       *blockchain.Blockchain
+	revokes     map[string]uint64 //last timestamp of the accepted revokes by public key
+	revokesLock sync.Mutex
+}
+
+// processChainUpdates revokes the delegated stakes which no longer meet the required stake
+func (api *DelegatorNode) processChainUpdates() {
+
+	updateNewChainCn := api.chain.UpdateNewChain.AddListener()
+	defer api.chain.UpdateNewChain.RemoveChannel(updateNewChainCn)
+
+	for {
+		chainHeight, ok := <-updateNewChainCn
+		if !ok {
+			return
+		}
+
+		atomic.StoreUint64(&api.chainHeight, chainHeight)
+
+		if chainHeight%config_nodes.DELEGATES_CHECK_BLOCKS == 0 {
+			if err := api.wallet.ExpireDelegations(context.Background()); err != nil {
+				gui.GUI.Error("Error checking the delegated stakes", err)
+			}
+		}
+	}
 }
 
 func NewDelegatorNode(chain *blockchain.Blockchain, wallet *wallet.Wallet) (delegator *DelegatorNode) {
@@ -17,7 +47,11 @@ func NewDelegatorNode(chain *blockchain.Blockchain, wallet *wallet.Wallet) (dele
 		0,
 		wallet,
 		chain,
+		make(map[string]uint64),
+		sync.Mutex{},
 	}
 
+	recovery.SafeGo(delegator.processChainUpdates)
+
 	return
 }
@@ -42,7 +42,7 @@ func (api *Faucet) GetFaucetCoins(r *http.Request, args *APIFaucetCoinsRequest,
 			config_coins.NATIVE_ASSET_FULL,
 			network_config.FAUCET_TESTNET_COINS_UNITS,
 			0,
-			&txs_builder.ZetherRingConfiguration{&txs_builder.ZetherSenderRingType{}, &txs_builder.ZetherRecipientRingType{}},
+			&txs_builder.ZetherRingConfiguration{&txs_builder.ZetherSenderRingType{}, &txs_builder.ZetherRecipientRingType{}, nil},
 			0,
 			&wizard.WizardTransactionData{[]byte("Testnet Faucet Tx"), true},
 			&wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, true}, false, 0, 0},
@@ -53,7 +53,7 @@ func (api *Faucet) GetFaucetCoins(r *http.Request, args *APIFaucetCoinsRequest,
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	tx, err := txs_builder.TxsBuilder.CreateZetherTx(txData, nil, true, true, true, false, ctx, func(status string) {})
+	tx, err := txs_builder.TxsBuilder.CreateZetherTx(api.wallet, txData, nil, true, true, true, false, ctx, func(status string) {})
 	if err != nil {
 		return err
 	}
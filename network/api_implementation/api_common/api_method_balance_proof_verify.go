@@ -0,0 +1,33 @@
+package api_common
+
+import (
+	"net/http"
+	"pandora-pay/cryptography/crypto/balance_proof"
+	"pandora-pay/txs_builder"
+)
+
+type APIBalanceProofVerifyRequest struct {
+	Proof string `json:"proof" msgpack:"proof"`
+}
+
+type APIBalanceProofVerifyReply struct {
+	Valid bool   `json:"valid" msgpack:"valid"`
+	Error string `json:"error,omitempty" msgpack:"error,omitempty"`
+	*txs_builder.BalanceProofOutput
+}
+
+func (api *APICommon) GetBalanceProofVerify(r *http.Request, args *APIBalanceProofVerifyRequest, reply *APIBalanceProofVerifyReply) error {
+
+	proof, err := balance_proof.DecodeBalanceProof(args.Proof)
+	if err != nil {
+		return err
+	}
+
+	if reply.BalanceProofOutput, err = txs_builder.VerifyBalanceProof(proof); err != nil {
+		reply.Error = err.Error()
+		return nil
+	}
+
+	reply.Valid = true
+	return nil
+}
@@ -0,0 +1,35 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/blockchain/forging/forging_stats"
+	"pandora-pay/config"
+)
+
+type APIForgingStatsRequest struct {
+	Start uint64 `json:"start,omitempty" msgpack:"start,omitempty"`
+}
+
+type APIForgingStatsReply struct {
+	Addresses   []*forging_stats.ForgingAddressStats `json:"addresses" msgpack:"addresses"`
+	Pending     []*forging_stats.ForgingLedgerEntry  `json:"pending" msgpack:"pending"`
+	Ledger      []*forging_stats.ForgingLedgerEntry  `json:"ledger" msgpack:"ledger"`
+	LedgerCount uint64                               `json:"ledgerCount" msgpack:"ledgerCount"`
+}
+
+func (api *APICommon) GetForgingStats(r *http.Request, args *APIForgingStatsRequest, reply *APIForgingStatsReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if forging_stats.ForgingStats == nil {
+		return errors.New("Forging is not initialized")
+	}
+
+	reply.Addresses = forging_stats.ForgingStats.GetAddresses()
+	reply.Pending = forging_stats.ForgingStats.GetPending()
+	reply.Ledger, reply.LedgerCount, err = forging_stats.ForgingStats.GetLedger(args.Start, config.API_ACCOUNT_MAX_TXS)
+	return
+}
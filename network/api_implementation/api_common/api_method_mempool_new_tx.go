@@ -8,11 +8,13 @@ import (
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/txs_builder"
 	"pandora-pay/txs_validator"
 )
 
 type APIMempoolNewTxRequest struct {
-	Tx helpers.Base64 `json:"tx" msgpack:"tx"`
+	Tx      helpers.Base64 `json:"tx" msgpack:"tx"`
+	Offline bool           `json:"offline,omitempty" msgpack:"offline,omitempty"` //the tx was signed offline and its context is verified
 }
 
 type APIMempoolNewTxReply struct {
@@ -58,6 +60,13 @@ func (api *APICommon) mempoolNewTx(args *APIMempoolNewTxRequest, reply *APIMempo
 		return
 	}
 
+	//zether transactions signed offline could have been created on a stale context
+	if args.Offline {
+		if _, err = txs_builder.CheckZetherTxChainContext(tx); err != nil {
+			return
+		}
+	}
+
 	if err = api.mempool.AddTxToMempool(tx, api.chain.GetChainData().Height, false, true, false, exceptSocketUUID, context.Background()); err != nil {
 		return
 	}
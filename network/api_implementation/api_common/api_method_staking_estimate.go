@@ -0,0 +1,164 @@
+package api_common
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/big"
+	"net/http"
+	"pandora-pay/blockchain"
+	"pandora-pay/config"
+	"pandora-pay/config/config_reward"
+	"pandora-pay/config/config_stake"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type APIStakingEstimateRequest struct {
+	Amount uint64 `json:"amount" msgpack:"amount"`
+	Staked bool   `json:"staked,omitempty" msgpack:"staked,omitempty"` //the amount is already part of the active stake
+}
+
+type APIStakingEstimateReply struct {
+	Height             uint64  `json:"height" msgpack:"height"`
+	BlocksAnalyzed     uint64  `json:"blocksAnalyzed" msgpack:"blocksAnalyzed"`
+	AverageBlockTime   float64 `json:"averageBlockTime" msgpack:"averageBlockTime"`
+	NetworkStake       uint64  `json:"networkStake" msgpack:"networkStake"`
+	RequiredStake      uint64  `json:"requiredStake" msgpack:"requiredStake"`
+	PendingStakeWindow uint64  `json:"pendingStakeWindow" msgpack:"pendingStakeWindow"`
+	ActiveAt           uint64  `json:"activeAt" msgpack:"activeAt"`
+	Eligible           bool    `json:"eligible" msgpack:"eligible"`
+	BlockReward        uint64  `json:"blockReward" msgpack:"blockReward"`
+	Share              float64 `json:"share" msgpack:"share"`
+	BlocksPerDay       float64 `json:"blocksPerDay" msgpack:"blocksPerDay"`
+	RewardPerDay       uint64  `json:"rewardPerDay" msgpack:"rewardPerDay"`
+	DaysPerBlock       float64 `json:"daysPerBlock" msgpack:"daysPerBlock"`
+}
+
+// apiStakingEstimateNetwork is the part of the estimate which depends only on the chain. It is computed once per block
+type apiStakingEstimateNetwork struct {
+	height       uint64
+	hash         []byte
+	window       uint64
+	deltaTime    uint64
+	networkStake uint64
+}
+
+// getStakingEstimateNetwork returns the network stake of the last blocks, reusing the estimate of the same block
+func (api *APICommon) getStakingEstimateNetwork(chainData *blockchain.BlockchainData) (*apiStakingEstimateNetwork, error) {
+
+	if cached := api.stakingEstimate.Load(); cached != nil && cached.height == chainData.Height && bytes.Equal(cached.hash, chainData.Hash) {
+		return cached, nil
+	}
+
+	window := config.API_STAKING_ESTIMATE_BLOCKS
+	if window > chainData.Height-1 {
+		window = chainData.Height - 1
+	}
+
+	var maxStakingAmount uint64
+	var deltaDifficulty *big.Int
+	var deltaTime uint64
+
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		//totalDifficulty of height h stores the timestamp of the block h-1
+		firstDifficulty, firstTimestamp, err := chainData.LoadTotalDifficultyExtra(reader, chainData.Height-window)
+		if err != nil {
+			return err
+		}
+
+		deltaDifficulty = new(big.Int).Sub(chainData.BigTotalDifficulty, firstDifficulty)
+		deltaTime = chainData.Timestamp - firstTimestamp
+
+		//the winning stakes are a lower bound of the active stake
+		for height := chainData.Height - window; height < chainData.Height; height++ {
+
+			hash, err := api.chain.LoadBlockHash(reader, height)
+			if err != nil {
+				return err
+			}
+
+			blk, err := api.ApiStore.loadBlock(reader, hash)
+			if err != nil {
+				return err
+			}
+
+			if blk.StakingAmount > maxStakingAmount {
+				maxStakingAmount = blk.StakingAmount
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if deltaTime == 0 {
+		deltaTime = 1
+	}
+
+	estimate := &apiStakingEstimateNetwork{
+		height:    chainData.Height,
+		hash:      chainData.Hash,
+		window:    window,
+		deltaTime: deltaTime,
+	}
+
+	networkStake := new(big.Int).Div(deltaDifficulty, new(big.Int).SetUint64(deltaTime))
+	if networkStake.IsUint64() {
+		estimate.networkStake = networkStake.Uint64()
+	} else {
+		estimate.networkStake = math.MaxUint64
+	}
+	if estimate.networkStake < maxStakingAmount {
+		estimate.networkStake = maxStakingAmount
+	}
+
+	api.stakingEstimate.Store(estimate)
+	return estimate, nil
+}
+
+// GetStakingEstimate estimates the active stake of the network and the blocks an amount would forge.
+// An address forges when SHA3(kernel) / stake <= target, so every second the network forges with the
+// probability networkStake / difficulty. The network stake is the difficulty forged per second.
+func (api *APICommon) GetStakingEstimate(r *http.Request, args *APIStakingEstimateRequest, reply *APIStakingEstimateReply) error {
+
+	chainData := api.chain.GetChainData()
+	if chainData.Height < 2 {
+		return errors.New("Not enough blocks to estimate the stake")
+	}
+
+	estimate, err := api.getStakingEstimateNetwork(chainData)
+	if err != nil {
+		return err
+	}
+
+	reply.Height = chainData.Height
+	reply.NetworkStake = estimate.networkStake
+	reply.BlocksAnalyzed = estimate.window
+	reply.AverageBlockTime = float64(estimate.deltaTime) / float64(estimate.window)
+	reply.RequiredStake = config_stake.GetRequiredStake(chainData.Height)
+	reply.PendingStakeWindow = config_stake.GetPendingStakeWindow(chainData.Height)
+	reply.ActiveAt = chainData.Height + reply.PendingStakeWindow
+	reply.Eligible = args.Amount >= reply.RequiredStake
+	reply.BlockReward = config_reward.GetRewardAt(reply.ActiveAt)
+
+	if !reply.Eligible {
+		return nil
+	}
+
+	activeStake := float64(reply.NetworkStake)
+	if !args.Staked {
+		activeStake += float64(args.Amount)
+	}
+
+	reply.Share = math.Min(float64(args.Amount)/activeStake, 1)
+	reply.BlocksPerDay = reply.Share * 24 * 60 * 60 / float64(config.BLOCK_TIME)
+	reply.RewardPerDay = uint64(reply.BlocksPerDay * float64(reply.BlockReward))
+	if reply.BlocksPerDay > 0 {
+		reply.DaysPerBlock = 1 / reply.BlocksPerDay
+	}
+
+	return nil
+}
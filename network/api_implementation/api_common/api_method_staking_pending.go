@@ -0,0 +1,59 @@
+package api_common
+
+import (
+	"encoding/binary"
+	"net/http"
+	"pandora-pay/blockchain/data_storage/pending_stakes_list"
+	"pandora-pay/blockchain/data_storage/pending_stakes_list/pending_stakes"
+	"pandora-pay/config/config_stake"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type APIStakingPendingRequest struct {
+	api_types.APIAccountBaseRequest
+}
+
+type APIStakingPendingReply struct {
+	ChainHeight uint64                          `json:"chainHeight" msgpack:"chainHeight"`
+	List        []*pending_stakes.PendingStakes `json:"list" msgpack:"list"`
+}
+
+type APIStakingPendingByHeightRequest struct {
+	Height uint64 `json:"height" msgpack:"height"`
+}
+
+func (api *APICommon) GetStakingPending(r *http.Request, args *APIStakingPendingRequest, reply *APIStakingPendingReply) error {
+
+	publicKey, err := args.GetPublicKey(true)
+	if err != nil {
+		return err
+	}
+
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		reply.ChainHeight, _ = binary.Uvarint(reader.Get("chainHeight"))
+
+		pendingStakesList := pending_stakes_list.NewPendingStakesList(reader)
+		reply.List, err = pendingStakesList.GetPublicKeyPendingStakes(publicKey, reply.ChainHeight, reply.ChainHeight+config_stake.GetPendingStakeWindow(reply.ChainHeight))
+		return
+	})
+}
+
+func (api *APICommon) GetStakingPendingByHeight(r *http.Request, args *APIStakingPendingByHeightRequest, reply *pending_stakes.PendingStakes) error {
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		pendingStakes, err := pending_stakes_list.NewPendingStakesList(reader).GetPendingStakes(args.Height)
+		if err != nil {
+			return err
+		}
+
+		reply.Height = args.Height
+		reply.Pending = []*pending_stakes.PendingStake{}
+		if pendingStakes != nil {
+			reply.Pending = pendingStakes.Pending
+		}
+		return nil
+	})
+}
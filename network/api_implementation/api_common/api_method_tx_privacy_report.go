@@ -0,0 +1,27 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/txs_builder"
+)
+
+type APITxPrivacyReportRequest struct {
+	Hash helpers.Base64 `json:"hash" msgpack:"hash"`
+}
+
+type APITxPrivacyReportReply struct {
+	*txs_builder.TxPrivacyReport
+}
+
+func (api *APICommon) GetTxPrivacyReport(r *http.Request, args *APITxPrivacyReportRequest, reply *APITxPrivacyReportReply) (err error) {
+
+	if len(args.Hash) != cryptography.HashSize {
+		return errors.New("Invalid hash")
+	}
+
+	reply.TxPrivacyReport, err = txs_builder.TxsBuilder.GetTxPrivacyReport(args.Hash)
+	return
+}
@@ -0,0 +1,39 @@
+package api_common
+
+import (
+	"net/http"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payment_proof"
+)
+
+type APITxVerifyPaymentProofRequest struct {
+	Proof string `json:"proof" msgpack:"proof"`
+}
+
+type APITxVerifyPaymentProofReply struct {
+	Valid   bool   `json:"valid" msgpack:"valid"`
+	Error   string `json:"error,omitempty" msgpack:"error,omitempty"`
+	Mempool bool   `json:"mempool,omitempty" msgpack:"mempool,omitempty"`
+	*transaction_zether_payment_proof.PaymentProofOutput
+}
+
+func (api *APICommon) GetTxVerifyPaymentProof(r *http.Request, args *APITxVerifyPaymentProofRequest, reply *APITxVerifyPaymentProofReply) error {
+
+	proof, err := transaction_zether_payment_proof.DecodePaymentProof(args.Proof)
+	if err != nil {
+		return err
+	}
+
+	txReply := &APITxReply{}
+	if err = api.GetTx(r, &APITxRequest{Hash: proof.TxHash}, txReply); err != nil {
+		return err
+	}
+	reply.Mempool = txReply.Mempool
+
+	if reply.PaymentProofOutput, err = proof.Verify(txReply.Tx); err != nil {
+		reply.Error = err.Error()
+		return nil
+	}
+
+	reply.Valid = true
+	return nil
+}
@@ -0,0 +1,40 @@
+package api_common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers"
+	"pandora-pay/txs_builder"
+)
+
+type APIWalletBalanceProofsRequest struct {
+	APIWalletSelectRequest
+	Rows      []*txs_builder.TxBuilderBalanceProofRow `json:"rows" msgpack:"rows"`
+	Asset     helpers.Base64                          `json:"asset,omitempty" msgpack:"asset,omitempty"`
+	Challenge helpers.Base64                          `json:"challenge" msgpack:"challenge"`
+}
+
+type APIWalletBalanceProofsReply struct {
+	Results []*txs_builder.TxBuilderBalanceProofResult `json:"results" msgpack:"results"`
+}
+
+func (api *APICommon) WalletBalanceProofs(r *http.Request, args *APIWalletBalanceProofsRequest, reply *APIWalletBalanceProofsReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if len(args.Asset) == 0 {
+		args.Asset = config_coins.NATIVE_ASSET_FULL
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	reply.Results, err = txs_builder.TxsBuilder.CreateBalanceProofs(w, args.Rows, args.Asset, args.Challenge, context.Background(), func(string) {})
+	return
+}
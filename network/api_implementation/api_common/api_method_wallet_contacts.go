@@ -0,0 +1,132 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/wallet"
+)
+
+type APIWalletContactRequest struct {
+	APIWalletSelectRequest
+	Name         string         `json:"name" msgpack:"name"`
+	Address      string         `json:"address" msgpack:"address"`
+	DefaultAsset helpers.Base64 `json:"defaultAsset,omitempty" msgpack:"defaultAsset,omitempty"`
+	Notes        string         `json:"notes,omitempty" msgpack:"notes,omitempty"`
+}
+
+type APIWalletGetContactsReply struct {
+	Contacts []*wallet.WalletContact `json:"contacts" msgpack:"contacts"`
+}
+
+type APIWalletCreateContactRequest struct {
+	APIWalletContactRequest
+}
+
+type APIWalletCreateContactReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+type APIWalletEditContactRequest struct {
+	OldName string `json:"oldName" msgpack:"oldName"`
+	APIWalletContactRequest
+}
+
+type APIWalletEditContactReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+type APIWalletDeleteContactRequest struct {
+	APIWalletSelectRequest
+	Name string `json:"name" msgpack:"name"`
+}
+
+type APIWalletDeleteContactReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (request *APIWalletContactRequest) getContact() *wallet.WalletContact {
+	return &wallet.WalletContact{
+		Name:         request.Name,
+		Address:      request.Address,
+		DefaultAsset: request.DefaultAsset,
+		Notes:        request.Notes,
+	}
+}
+
+func (api *APICommon) GetWalletContacts(r *http.Request, args *APIWalletSelectRequest, reply *APIWalletGetContactsReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	reply.Contacts, err = w.GetContacts()
+	return
+}
+
+func (api *APICommon) WalletCreateContact(r *http.Request, args *APIWalletCreateContactRequest, reply *APIWalletCreateContactReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	if err = w.AddContact(args.getContact()); err != nil {
+		return
+	}
+
+	reply.Result = true
+	return
+}
+
+func (api *APICommon) WalletEditContact(r *http.Request, args *APIWalletEditContactRequest, reply *APIWalletEditContactReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	oldName := args.OldName
+	if oldName == "" {
+		oldName = args.Name
+	}
+
+	if err = w.EditContact(oldName, args.getContact()); err != nil {
+		return
+	}
+
+	reply.Result = true
+	return
+}
+
+func (api *APICommon) WalletDeleteContact(r *http.Request, args *APIWalletDeleteContactRequest, reply *APIWalletDeleteContactReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	if err = w.RemoveContact(args.Name); err != nil {
+		return
+	}
+
+	reply.Result = true
+	return
+}
@@ -8,6 +8,7 @@ import (
 )
 
 type APIWalletCreateAddressRequest struct {
+	APIWalletSelectRequest
 	Name          string `json:"name" msgpack:"name"`
 	Staked        bool   `json:"staked" msgpack:"staked"`
 	SpendRequired bool   `json:"spendRequired" msgpack:"spendRequired"`
@@ -22,7 +23,12 @@ func (api *APICommon) GetWalletCreateAddress(r *http.Request, args *APIWalletCre
 		return errors.New("Invalid User or Password")
 	}
 
-	addr, err := api.wallet.AddNewAddress(true, args.Name, args.Staked, args.SpendRequired, true)
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
+	addr, err := w.AddNewAddress(true, args.Name, args.Staked, args.SpendRequired, true)
 	if err != nil {
 		return err
 	}
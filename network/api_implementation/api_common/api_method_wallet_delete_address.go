@@ -7,6 +7,7 @@ import (
 )
 
 type APIWalletDeleteAddressRequest struct {
+	APIWalletSelectRequest
 	api_types.APIAccountBaseRequest
 }
 
@@ -19,11 +20,16 @@ func (api *APICommon) GetWalletDeleteAddress(r *http.Request, args *APIWalletDel
 		return errors.New("Invalid User or Password")
 	}
 
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
 	publicKey, err := args.GetPublicKey(true)
 	if err != nil {
 		return err
 	}
 
-	reply.Status, err = api.wallet.RemoveAddressByPublicKey(publicKey, true)
+	reply.Status, err = w.RemoveAddressByPublicKey(publicKey, true)
 	return err
 }
@@ -0,0 +1,66 @@
+package api_common
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+	"pandora-pay/wallet"
+)
+
+type APIWalletExportHistoryRequest struct {
+	APIWalletSelectRequest
+	api_types.APIAccountBaseRequest
+	From   uint64 `json:"from,omitempty" msgpack:"from,omitempty"`
+	To     uint64 `json:"to,omitempty" msgpack:"to,omitempty"` //0 means the last block
+	Format string `json:"format,omitempty" msgpack:"format,omitempty"`
+}
+
+type APIWalletExportHistoryReply struct {
+	Rows []*wallet.WalletHistoryExportRow `json:"rows,omitempty" msgpack:"rows,omitempty"`
+	CSV  string                           `json:"csv,omitempty" msgpack:"csv,omitempty"`
+}
+
+func (api *APICommon) GetWalletExportHistory(r *http.Request, args *APIWalletExportHistoryRequest, reply *APIWalletExportHistoryReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	if args.Format != "" && args.Format != "json" && args.Format != "csv" {
+		return errors.New("Invalid format")
+	}
+
+	publicKey, err := args.GetPublicKey(false)
+	if err != nil {
+		return
+	}
+
+	publicKeys := [][]byte{}
+	if publicKey != nil {
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	to := args.To
+	if to == 0 {
+		to = math.MaxUint64
+	}
+
+	rows, err := w.ExportHistory(publicKeys, args.From, to)
+	if err != nil {
+		return
+	}
+
+	if args.Format == "csv" {
+		reply.CSV, err = wallet.ExportHistoryCSV(rows)
+		return
+	}
+
+	reply.Rows = rows
+	return
+}
@@ -12,6 +12,7 @@ import (
 )
 
 type APIWalletGenerateAddressRequest struct {
+	APIWalletSelectRequest
 	api_types.APIAccountBaseRequest
 	PaymentID     helpers.Base64 `json:"paymentID" msgpack:"paymentID"`
 	PaymentAmount uint64         `json:"paymentAmount" msgpack:"paymentAmount"`
@@ -28,12 +29,17 @@ func (api *APICommon) GetWalletGenerateAddress(r *http.Request, args *APIWalletG
 		return errors.New("Invalid User or Password")
 	}
 
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
 	publicKey, err := args.GetPublicKey(true)
 	if err != nil {
 		return err
 	}
 
-	walletAddr := api.wallet.GetWalletAddressByPublicKey(publicKey, true)
+	walletAddr := w.GetWalletAddressByPublicKey(publicKey, true)
 	if walletAddr == nil {
 		return errors.New("address doesn't exist in your waallet")
 	}
@@ -14,20 +14,25 @@ type APIWalletGetAccountsReply struct {
 	Addresses []*wallet_address.WalletAddress `json:"addresses" msgpack:"addresses"`
 }
 
-func (api *APICommon) GetWalletAddresses(r *http.Request, args *struct{}, reply *APIWalletGetAccountsReply, authenticated bool) (err error) {
+func (api *APICommon) GetWalletAddresses(r *http.Request, args *APIWalletSelectRequest, reply *APIWalletGetAccountsReply, authenticated bool) (err error) {
 
 	if !authenticated {
 		return errors.New("Invalid User or Password")
 	}
 
-	api.wallet.Lock.RLock()
-	defer api.wallet.Lock.RUnlock()
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	w.Lock.RLock()
+	defer w.Lock.RUnlock()
 
-	reply.Version = api.wallet.Version
-	reply.Encrypted = api.wallet.Encryption.Encrypted
+	reply.Version = w.Version
+	reply.Encrypted = w.Encryption.Encrypted
 
-	reply.Addresses = make([]*wallet_address.WalletAddress, len(api.wallet.Addresses))
-	for i, addr := range api.wallet.Addresses {
+	reply.Addresses = make([]*wallet_address.WalletAddress, len(w.Addresses))
+	for i, addr := range w.Addresses {
 		if reply.Addresses[i], err = generics.Clone[*wallet_address.WalletAddress](addr, new(wallet_address.WalletAddress)); err != nil {
 			return
 		}
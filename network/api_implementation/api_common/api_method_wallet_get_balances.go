@@ -15,6 +15,7 @@ import (
 )
 
 type APIWalletGetBalanceRequest struct {
+	APIWalletSelectRequest
 	List []*api_types.APIAccountBaseRequest `json:"list" msgpack:"list"`
 }
 
@@ -40,6 +41,11 @@ func (api *APICommon) GetWalletBalances(r *http.Request, args *APIWalletGetBalan
 		return errors.New("Invalid User or Password")
 	}
 
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
 	publicKeys := make([][]byte, len(args.List))
 	for i, it := range args.List {
 		if publicKeys[i], err = it.GetPublicKey(true); err != nil {
@@ -49,7 +55,7 @@ func (api *APICommon) GetWalletBalances(r *http.Request, args *APIWalletGetBalan
 
 	walletAddresses := make([]*wallet_address.WalletAddress, len(publicKeys))
 	for i, publicKey := range publicKeys {
-		if walletAddresses[i] = api.wallet.GetWalletAddressByPublicKey(publicKey, true); walletAddresses[i] == nil {
+		if walletAddresses[i] = w.GetWalletAddressByPublicKey(publicKey, true); walletAddresses[i] == nil {
 			return errors.New(fmt.Sprintf("input %d doesn't exist in your wallet", i))
 		}
 	}
@@ -113,7 +119,7 @@ func (api *APICommon) GetWalletBalances(r *http.Request, args *APIWalletGetBalan
 	for i, publicKey := range publicKeys {
 		for _, data := range reply.Results[i].Balances {
 
-			if data.Amount, err = api.wallet.DecryptBalanceByPublicKey(publicKey, data.Balance, data.Asset, false, 0, true, true, nil, func(status string) {}); err != nil {
+			if data.Amount, err = w.DecryptBalanceByPublicKey(publicKey, data.Balance, data.Asset, false, 0, true, true, nil, func(status string) {}); err != nil {
 				return
 			}
 		}
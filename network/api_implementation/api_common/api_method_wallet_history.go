@@ -0,0 +1,53 @@
+package api_common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"pandora-pay/config"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+	"pandora-pay/wallet"
+)
+
+type APIWalletHistoryRequest struct {
+	APIWalletSelectRequest
+	api_types.APIAccountBaseRequest
+	Start uint64 `json:"start,omitempty" msgpack:"start,omitempty"`
+	Scan  bool   `json:"scan,omitempty" msgpack:"scan,omitempty"`
+}
+
+type APIWalletHistoryReply struct {
+	Count uint64                    `json:"count" msgpack:"count"`
+	Txs   []*wallet.WalletHistoryTx `json:"txs" msgpack:"txs"`
+}
+
+func (api *APICommon) GetWalletHistory(r *http.Request, args *APIWalletHistoryRequest, reply *APIWalletHistoryReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	publicKey, err := args.GetPublicKey(true)
+	if err != nil {
+		return
+	}
+
+	if args.Scan {
+		if err = w.ScanHistory(publicKey, context.Background()); err != nil {
+			return
+		}
+	}
+
+	var count int
+	if reply.Txs, count, err = w.GetHistory(publicKey, int(args.Start), int(config.API_ACCOUNT_MAX_TXS)); err != nil {
+		return
+	}
+	reply.Count = uint64(count)
+
+	return
+}
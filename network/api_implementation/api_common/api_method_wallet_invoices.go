@@ -0,0 +1,96 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/config"
+	"pandora-pay/helpers"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+	"pandora-pay/wallet"
+)
+
+type APIWalletCreateInvoiceRequest struct {
+	APIWalletSelectRequest
+	api_types.APIAccountBaseRequest
+	Asset         helpers.Base64 `json:"asset,omitempty" msgpack:"asset,omitempty"`
+	Amount        uint64         `json:"amount" msgpack:"amount"`
+	Confirmations uint64         `json:"confirmations,omitempty" msgpack:"confirmations,omitempty"`
+}
+
+type APIWalletCreateInvoiceReply struct {
+	Invoice *wallet.WalletInvoice `json:"invoice" msgpack:"invoice"`
+}
+
+type APIWalletGetInvoiceRequest struct {
+	APIWalletSelectRequest
+	PaymentID helpers.Base64 `json:"paymentID" msgpack:"paymentID"`
+}
+
+type APIWalletGetInvoiceReply struct {
+	Invoice *wallet.WalletInvoice `json:"invoice" msgpack:"invoice"`
+}
+
+type APIWalletGetInvoicesRequest struct {
+	APIWalletSelectRequest
+	Start uint64 `json:"start,omitempty" msgpack:"start,omitempty"`
+}
+
+type APIWalletGetInvoicesReply struct {
+	Count    uint64                  `json:"count" msgpack:"count"`
+	Invoices []*wallet.WalletInvoice `json:"invoices" msgpack:"invoices"`
+}
+
+func (api *APICommon) WalletCreateInvoice(r *http.Request, args *APIWalletCreateInvoiceRequest, reply *APIWalletCreateInvoiceReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	publicKey, err := args.GetPublicKey(true)
+	if err != nil {
+		return
+	}
+
+	reply.Invoice, err = w.CreateInvoice(publicKey, args.Asset, args.Amount, args.Confirmations)
+	return
+}
+
+func (api *APICommon) GetWalletInvoice(r *http.Request, args *APIWalletGetInvoiceRequest, reply *APIWalletGetInvoiceReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	reply.Invoice, err = w.GetInvoice(args.PaymentID)
+	return
+}
+
+func (api *APICommon) GetWalletInvoices(r *http.Request, args *APIWalletGetInvoicesRequest, reply *APIWalletGetInvoicesReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	var count int
+	if reply.Invoices, count, err = w.GetInvoices(int(args.Start), int(config.API_ACCOUNT_MAX_TXS)); err != nil {
+		return
+	}
+	reply.Count = uint64(count)
+
+	return
+}
@@ -0,0 +1,63 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+type APIWalletUnlockRequest struct {
+	APIWalletSelectRequest
+	Password string `json:"password" msgpack:"password"`
+	Seconds  uint64 `json:"seconds" msgpack:"seconds"`
+}
+
+type APIWalletUnlockReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+type APIWalletLockReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (api *APICommon) WalletUnlock(r *http.Request, args *APIWalletUnlockRequest, reply *APIWalletUnlockReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil && args.Wallet != "" { //a locked named wallet is not loaded
+		if err = api.wallet.LoadNamedWallet(args.Wallet, args.Password); err != nil {
+			return err
+		}
+		w, err = api.wallet.GetWallet(args.Wallet)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = w.Unlock(args.Password, time.Duration(args.Seconds)*time.Second); err != nil {
+		return err
+	}
+
+	reply.Result = true
+	return nil
+}
+
+func (api *APICommon) WalletLock(r *http.Request, args *APIWalletSelectRequest, reply *APIWalletLockReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
+	if err = w.LockWallet(); err != nil {
+		return err
+	}
+
+	reply.Result = true
+	return nil
+}
@@ -0,0 +1,54 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+)
+
+type APIWalletPaymentProofRequest struct {
+	APIWalletSelectRequest
+	api_types.APIAccountBaseRequest
+	Hash         helpers.Base64 `json:"hash" msgpack:"hash"`
+	PayloadIndex int            `json:"payloadIndex" msgpack:"payloadIndex"`
+}
+
+type APIWalletPaymentProofReply struct {
+	Proof string `json:"proof" msgpack:"proof"`
+}
+
+func (api *APICommon) GetWalletPaymentProof(r *http.Request, args *APIWalletPaymentProofRequest, reply *APIWalletPaymentProofReply, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return err
+	}
+
+	if len(args.Hash) != cryptography.HashSize {
+		return errors.New("Invalid hash")
+	}
+
+	publicKey, err := args.GetPublicKey(false)
+	if err != nil {
+		return err
+	}
+
+	txReply := &APITxReply{}
+	if err = api.GetTx(r, &APITxRequest{Hash: args.Hash}, txReply); err != nil {
+		return err
+	}
+
+	proof, err := w.CreatePaymentProof(txReply.Tx, args.PayloadIndex, publicKey)
+	if err != nil {
+		return err
+	}
+
+	reply.Proof = proof.Encode()
+	return nil
+}
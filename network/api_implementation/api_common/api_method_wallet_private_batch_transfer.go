@@ -0,0 +1,63 @@
+package api_common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/txs_builder"
+)
+
+type APIWalletPrivateBatchTransferRequest struct {
+	APIWalletSelectRequest
+	Sender    string                                   `json:"sender" msgpack:"sender"`
+	Rows      []*txs_builder.TxBuilderBatchTransferRow `json:"rows,omitempty" msgpack:"rows,omitempty"`
+	CSV       string                                   `json:"csv,omitempty" msgpack:"csv,omitempty"`
+	RingSize  int                                      `json:"ringSize,omitempty" msgpack:"ringSize,omitempty"`
+	Propagate bool                                     `json:"propagate" msgpack:"propagate"`
+}
+
+type APIWalletPrivateBatchTransferReply struct {
+	Results []*txs_builder.TxBuilderBatchTransferResult `json:"results" msgpack:"results"`
+	Txs     []helpers.Base64                            `json:"txs" msgpack:"txs"`
+	Error   string                                      `json:"error,omitempty" msgpack:"error,omitempty"`
+}
+
+func (api *APICommon) WalletPrivateBatchTransfer(r *http.Request, args *APIWalletPrivateBatchTransferRequest, reply *APIWalletPrivateBatchTransferReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+	if err = w.CheckSpendUnlocked(); err != nil {
+		return
+	}
+
+	rows := args.Rows
+	if len(args.CSV) > 0 {
+		if rows, err = txs_builder.ParseBatchTransferCSV(args.CSV); err != nil {
+			return
+		}
+	}
+
+	results, txs, err := txs_builder.TxsBuilder.CreateZetherBatchTx(w, args.Sender, rows, args.RingSize, args.Propagate, true, true, context.Background(), func(string) {})
+	if results == nil {
+		return
+	}
+
+	//the rows paid before the error are reported
+	reply.Results = results
+	reply.Txs = make([]helpers.Base64, len(txs))
+	for i, tx := range txs {
+		reply.Txs[i] = tx.Bloom.Serialized
+	}
+	if err != nil {
+		reply.Error = err.Error()
+	}
+
+	return nil
+}
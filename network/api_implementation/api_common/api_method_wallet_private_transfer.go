@@ -9,6 +9,7 @@ import (
 )
 
 type APIWalletPrivateTransferRequest struct {
+	APIWalletSelectRequest
 	Data      *txs_builder.TxBuilderCreateZetherTxData `json:"data" msgpack:"data"`
 	Propagate bool                                     `json:"propagate" msgpack:"propagate"`
 }
@@ -24,7 +25,19 @@ func (api *APICommon) WalletPrivateTransfer(r *http.Request, args *APIWalletPriv
 		return errors.New("Invalid User or Password")
 	}
 
-	if reply.Tx, err = txs_builder.TxsBuilder.CreateZetherTx(args.Data, nil, args.Propagate, true, true, false, context.Background(), func(string) {}); err != nil {
+	if args.Data == nil {
+		return errors.New("Data is missing")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+	if err = w.CheckSpendUnlocked(); err != nil {
+		return
+	}
+
+	if reply.Tx, err = txs_builder.TxsBuilder.CreateZetherTx(w, args.Data, nil, args.Propagate, true, true, false, context.Background(), func(string) {}); err != nil {
 		return
 	}
 
@@ -0,0 +1,82 @@
+package api_common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/txs_builder"
+)
+
+type APIWalletPrivateTransferPrepareRequest struct {
+	APIWalletSelectRequest
+	Data *txs_builder.TxBuilderCreateZetherTxData `json:"data" msgpack:"data"`
+}
+
+type APIWalletPrivateTransferPrepareReply struct {
+	Context     helpers.Base64 `json:"context" msgpack:"context"`
+	ChainHeight uint64         `json:"chainHeight" msgpack:"chainHeight"`
+}
+
+type APIWalletPrivateTransferSignRequest struct {
+	APIWalletSelectRequest
+	Context helpers.Base64 `json:"context" msgpack:"context"`
+}
+
+type APIWalletPrivateTransferSignReply struct {
+	Tx   helpers.Base64 `json:"tx" msgpack:"tx"`
+	Hash helpers.Base64 `json:"hash" msgpack:"hash"`
+}
+
+func (api *APICommon) WalletPrivateTransferPrepare(r *http.Request, args *APIWalletPrivateTransferPrepareRequest, reply *APIWalletPrivateTransferPrepareReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+
+	txContext, err := txs_builder.TxsBuilder.PrepareZetherTxContext(w, args.Data, context.Background(), func(string) {})
+	if err != nil {
+		return
+	}
+
+	if reply.Context, err = txContext.Serialize(); err != nil {
+		return
+	}
+	reply.ChainHeight = txContext.ChainHeight
+
+	return
+}
+
+func (api *APICommon) WalletPrivateTransferSign(r *http.Request, args *APIWalletPrivateTransferSignRequest, reply *APIWalletPrivateTransferSignReply, authenticated bool) (err error) {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	txContext, err := txs_builder.DeserializeZetherTxContext(args.Context)
+	if err != nil {
+		return
+	}
+
+	w, err := api.wallet.GetWallet(args.Wallet)
+	if err != nil {
+		return
+	}
+	if err = w.CheckSpendUnlocked(); err != nil {
+		return
+	}
+
+	tx, err := txs_builder.TxsBuilder.SignZetherTxContext(w, txContext, context.Background(), func(string) {})
+	if err != nil {
+		return
+	}
+
+	reply.Tx = tx.Bloom.Serialized
+	reply.Hash = tx.Bloom.Hash
+	return
+}
@@ -0,0 +1,90 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/wallet"
+)
+
+// APIWalletSelectRequest selects one of the loaded wallets by name. An empty name selects the active wallet
+type APIWalletSelectRequest struct {
+	Wallet string `json:"wallet,omitempty" msgpack:"wallet,omitempty"`
+}
+
+type APIWalletWalletsReply struct {
+	Wallets []*wallet.WalletInfo `json:"wallets" msgpack:"wallets"`
+}
+
+type APIWalletNameRequest struct {
+	Name string `json:"name" msgpack:"name"`
+}
+
+type APIWalletLoadWalletRequest struct {
+	Name     string `json:"name" msgpack:"name"`
+	Password string `json:"password,omitempty" msgpack:"password,omitempty"`
+}
+
+type APIWalletWalletReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (api *APICommon) GetWalletWallets(r *http.Request, args *struct{}, reply *APIWalletWalletsReply, authenticated bool) (err error) {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Wallets, err = api.wallet.GetWallets()
+	return
+}
+
+func (api *APICommon) WalletCreateWallet(r *http.Request, args *APIWalletNameRequest, reply *APIWalletWalletReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if err := api.wallet.CreateNamedWallet(args.Name); err != nil {
+		return err
+	}
+
+	reply.Result = true
+	return nil
+}
+
+func (api *APICommon) WalletLoadWallet(r *http.Request, args *APIWalletLoadWalletRequest, reply *APIWalletWalletReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if err := api.wallet.LoadNamedWallet(args.Name, args.Password); err != nil {
+		return err
+	}
+
+	reply.Result = true
+	return nil
+}
+
+func (api *APICommon) WalletUnloadWallet(r *http.Request, args *APIWalletNameRequest, reply *APIWalletWalletReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if err := api.wallet.UnloadNamedWallet(args.Name); err != nil {
+		return err
+	}
+
+	reply.Result = true
+	return nil
+}
+
+func (api *APICommon) WalletSwitchWallet(r *http.Request, args *APIWalletNameRequest, reply *APIWalletWalletReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if err := api.wallet.SwitchWallet(args.Name); err != nil {
+		return err
+	}
+
+	reply.Result = true
+	return nil
+}
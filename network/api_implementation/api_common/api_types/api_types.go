@@ -37,6 +37,10 @@ type APISubscriptionNotificationAssetExtra struct {
 	Index uint64 `json:"index" msgpack:"index"`
 }
 
+type APISubscriptionNotificationPendingStakeExtra struct {
+	Height uint64 `json:"height" msgpack:"height"`
+}
+
 type APISubscriptionNotificationAccountTxExtra struct {
 	Blockchain *APISubscriptionNotificationAccountTxExtraBlockchain `json:"blockchain,omitempty" msgpack:"blockchain,omitempty"`
 	Mempool    *APISubscriptionNotificationAccountTxExtraMempool    `json:"mempool,omitempty" msgpack:"mempool,omitempty"`
@@ -1,19 +1,24 @@
 package api_websockets
 
 import (
+	"errors"
 	"pandora-pay/blockchain"
 	"pandora-pay/blockchain/blockchain_sync"
+	"pandora-pay/blockchain/data_storage/pending_stakes_list/pending_stakes"
 	"pandora-pay/blockchain/info"
 	"pandora-pay/config"
 	"pandora-pay/mempool"
 	"pandora-pay/network/api_code/api_code_websockets"
 	"pandora-pay/network/api_implementation/api_common"
+	"pandora-pay/network/api_implementation/api_common/api_cosigner"
 	"pandora-pay/network/api_implementation/api_common/api_delegator_node"
 	"pandora-pay/network/api_implementation/api_common/api_faucet"
 	"pandora-pay/network/api_implementation/api_websockets/consensus"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/settings"
+	"strings"
 )
 
 type APIWebsockets struct {
@@ -28,6 +33,15 @@ type APIWebsockets struct {
 
 var ConfigureAPIRoutes func(api *APIWebsockets)
 
+func localOnly(callback func(conn *connection.AdvancedConnection, values []byte) (interface{}, error)) func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+	return func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+		if !network_config.IsLocalAddress(conn.RemoteAddr) {
+			return nil, errors.New("Wallet API is available only on loopback")
+		}
+		return callback(conn, values)
+	}
+}
+
 func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chain *blockchain.Blockchain, settings *settings.Settings, mempool *mempool.Mempool) *APIWebsockets {
 
 	api := &APIWebsockets{
@@ -41,41 +55,69 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 	}
 
 	api.GetMap = map[string]func(conn *connection.AdvancedConnection, values []byte) (interface{}, error){
-		"ping":                    api_code_websockets.Handle[struct{}, api_common.APIPingReply](api.apiCommon.GetPing),
-		"":                        api_code_websockets.Handle[struct{}, api_common.APIInfoReply](api.apiCommon.GetInfo),
-		"chain":                   api_code_websockets.Handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
-		"blockchain":              api_code_websockets.Handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
-		"blockchain/staking-info": api_code_websockets.Handle[api_common.APIStakingInfoRequest, api_common.APIStakingInfoReply](api.apiCommon.GetStakingInfo),
-		"blockchain/genesis-info": api_code_websockets.Handle[api_common.APIGenesisInfoRequest, api_common.APIGenesisInfoReply](api.apiCommon.GetGenesisInfo),
-		"blockchain/supply":       api_code_websockets.Handle[struct{}, api_common.APISupply](api.apiCommon.GetSupply),
-		"blockchain/supply-only":  api_code_websockets.Handle[struct{}, uint64](api.apiCommon.GetSupplyOnly),
-		"sync":                    api_code_websockets.Handle[struct{}, blockchain_sync.BlockchainSyncData](api.apiCommon.GetBlockchainSync),
-		"block-hash":              api_code_websockets.Handle[api_common.APIBlockHashRequest, api_common.APIBlockHashReply](api.apiCommon.GetBlockHash),
-		"block":                   api_code_websockets.Handle[api_common.APIBlockRequest, api_common.APIBlockReply](api.apiCommon.GetBlock),
-		"block/exists":            api_code_websockets.Handle[api_common.APIBlockExistsRequest, api_common.APIBlockExistsReply](api.apiCommon.GetBlockExists),
-		"block-complete":          api_code_websockets.Handle[api_common.APIBlockCompleteRequest, api_common.APIBlockCompleteReply](api.apiCommon.GetBlockComplete),
-		"tx-hash":                 api_code_websockets.Handle[api_common.APITxHashRequest, api_common.APITxHashReply](api.apiCommon.GetTxHash),
-		"tx":                      api_code_websockets.Handle[api_common.APITxRequest, api_common.APITxReply](api.apiCommon.GetTx),
-		"tx/exists":               api_code_websockets.Handle[api_common.APITxExistsRequest, api_common.APITxExistsReply](api.apiCommon.GetTxExists),
-		"tx-raw":                  api_code_websockets.Handle[api_common.APITxRawRequest, api_common.APITxRawReply](api.apiCommon.GetTxRaw),
-		"account":                 api_code_websockets.Handle[api_common.APIAccountRequest, api_common.APIAccountReply](api.apiCommon.GetAccount),
-		"accounts/count":          api_code_websockets.Handle[api_common.APIAccountsCountRequest, api_common.APIAccountsCountReply](api.apiCommon.GetAccountsCount),
-		"accounts/keys-by-index":  api_code_websockets.Handle[api_common.APIAccountsKeysByIndexRequest, api_common.APIAccountsKeysByIndexReply](api.apiCommon.GetAccountsKeysByIndex),
-		"accounts/by-keys":        api_code_websockets.Handle[api_common.APIAccountsByKeysRequest, api_common.APIAccountsByKeysReply](api.apiCommon.GetAccountsByKeys),
-		"asset":                   api_code_websockets.Handle[api_common.APIAssetRequest, api_common.APIAssetReply](api.apiCommon.GetAsset),
-		"asset/exists":            api_code_websockets.Handle[api_common.APIAssetRequest, api_common.APIAssetReply](api.apiCommon.GetAsset),
-		"asset/fee-liquidity":     api_code_websockets.Handle[api_common.APIAssetFeeLiquidityFeeRequest, api_common.APIAssetFeeLiquidityFeeReply](api.apiCommon.GetAssetFeeLiquidity),
-		"mempool":                 api_code_websockets.Handle[api_common.APIMempoolRequest, api_common.APIMempoolReply](api.apiCommon.GetMempool),
-		"mempool/tx-exists":       api_code_websockets.Handle[api_common.APIMempoolExistsRequest, api_common.APIMempoolExistsReply](api.apiCommon.GetMempoolExists),
-		"mempool/new-tx":          api_code_websockets.Handle[api_common.APIMempoolNewTxRequest, api_common.APIMempoolNewTxReply](api.apiCommon.MempoolNewTx),
-		"network/nodes":           api_code_websockets.Handle[struct{}, api_common.APINetworkNodesReply](api.apiCommon.GetNetworkNodes),
-		"wallet/get-addresses":    api_code_websockets.HandleAuthenticated[struct{}, api_common.APIWalletGetAccountsReply](api.apiCommon.GetWalletAddresses),
-		"wallet/generate-address": api_code_websockets.HandleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](api.apiCommon.GetWalletGenerateAddress),
-		"wallet/create-address":   api_code_websockets.HandleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](api.apiCommon.GetWalletCreateAddress),
-		"wallet/delete-address":   api_code_websockets.HandleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](api.apiCommon.GetWalletDeleteAddress),
-		"wallet/get-balances":     api_code_websockets.HandleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](api.apiCommon.GetWalletBalances),
-		"wallet/decrypt-tx":       api_code_websockets.HandleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](api.apiCommon.GetWalletDecryptTx),
-		"wallet/private-transfer": api_code_websockets.HandleAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](api.apiCommon.WalletPrivateTransfer),
+		"ping":                            api_code_websockets.Handle[struct{}, api_common.APIPingReply](api.apiCommon.GetPing),
+		"":                                api_code_websockets.Handle[struct{}, api_common.APIInfoReply](api.apiCommon.GetInfo),
+		"chain":                           api_code_websockets.Handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
+		"blockchain":                      api_code_websockets.Handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
+		"blockchain/staking-info":         api_code_websockets.Handle[api_common.APIStakingInfoRequest, api_common.APIStakingInfoReply](api.apiCommon.GetStakingInfo),
+		"staking/estimate":                api_code_websockets.Handle[api_common.APIStakingEstimateRequest, api_common.APIStakingEstimateReply](api.apiCommon.GetStakingEstimate),
+		"staking/pending":                 api_code_websockets.Handle[api_common.APIStakingPendingRequest, api_common.APIStakingPendingReply](api.apiCommon.GetStakingPending),
+		"staking/pending-by-height":       api_code_websockets.Handle[api_common.APIStakingPendingByHeightRequest, pending_stakes.PendingStakes](api.apiCommon.GetStakingPendingByHeight),
+		"blockchain/genesis-info":         api_code_websockets.Handle[api_common.APIGenesisInfoRequest, api_common.APIGenesisInfoReply](api.apiCommon.GetGenesisInfo),
+		"blockchain/supply":               api_code_websockets.Handle[struct{}, api_common.APISupply](api.apiCommon.GetSupply),
+		"blockchain/supply-only":          api_code_websockets.Handle[struct{}, uint64](api.apiCommon.GetSupplyOnly),
+		"sync":                            api_code_websockets.Handle[struct{}, blockchain_sync.BlockchainSyncData](api.apiCommon.GetBlockchainSync),
+		"block-hash":                      api_code_websockets.Handle[api_common.APIBlockHashRequest, api_common.APIBlockHashReply](api.apiCommon.GetBlockHash),
+		"block":                           api_code_websockets.Handle[api_common.APIBlockRequest, api_common.APIBlockReply](api.apiCommon.GetBlock),
+		"block/exists":                    api_code_websockets.Handle[api_common.APIBlockExistsRequest, api_common.APIBlockExistsReply](api.apiCommon.GetBlockExists),
+		"block-complete":                  api_code_websockets.Handle[api_common.APIBlockCompleteRequest, api_common.APIBlockCompleteReply](api.apiCommon.GetBlockComplete),
+		"tx-hash":                         api_code_websockets.Handle[api_common.APITxHashRequest, api_common.APITxHashReply](api.apiCommon.GetTxHash),
+		"tx":                              api_code_websockets.Handle[api_common.APITxRequest, api_common.APITxReply](api.apiCommon.GetTx),
+		"tx/exists":                       api_code_websockets.Handle[api_common.APITxExistsRequest, api_common.APITxExistsReply](api.apiCommon.GetTxExists),
+		"tx-raw":                          api_code_websockets.Handle[api_common.APITxRawRequest, api_common.APITxRawReply](api.apiCommon.GetTxRaw),
+		"tx/privacy-report":               api_code_websockets.Handle[api_common.APITxPrivacyReportRequest, api_common.APITxPrivacyReportReply](api.apiCommon.GetTxPrivacyReport),
+		"tx/verify-payment-proof":         api_code_websockets.Handle[api_common.APITxVerifyPaymentProofRequest, api_common.APITxVerifyPaymentProofReply](api.apiCommon.GetTxVerifyPaymentProof),
+		"balance-proof/verify":            api_code_websockets.Handle[api_common.APIBalanceProofVerifyRequest, api_common.APIBalanceProofVerifyReply](api.apiCommon.GetBalanceProofVerify),
+		"account":                         api_code_websockets.Handle[api_common.APIAccountRequest, api_common.APIAccountReply](api.apiCommon.GetAccount),
+		"accounts/count":                  api_code_websockets.Handle[api_common.APIAccountsCountRequest, api_common.APIAccountsCountReply](api.apiCommon.GetAccountsCount),
+		"accounts/keys-by-index":          api_code_websockets.Handle[api_common.APIAccountsKeysByIndexRequest, api_common.APIAccountsKeysByIndexReply](api.apiCommon.GetAccountsKeysByIndex),
+		"accounts/by-keys":                api_code_websockets.Handle[api_common.APIAccountsByKeysRequest, api_common.APIAccountsByKeysReply](api.apiCommon.GetAccountsByKeys),
+		"asset":                           api_code_websockets.Handle[api_common.APIAssetRequest, api_common.APIAssetReply](api.apiCommon.GetAsset),
+		"asset/exists":                    api_code_websockets.Handle[api_common.APIAssetRequest, api_common.APIAssetReply](api.apiCommon.GetAsset),
+		"asset/fee-liquidity":             api_code_websockets.Handle[api_common.APIAssetFeeLiquidityFeeRequest, api_common.APIAssetFeeLiquidityFeeReply](api.apiCommon.GetAssetFeeLiquidity),
+		"mempool":                         api_code_websockets.Handle[api_common.APIMempoolRequest, api_common.APIMempoolReply](api.apiCommon.GetMempool),
+		"mempool/tx-exists":               api_code_websockets.Handle[api_common.APIMempoolExistsRequest, api_common.APIMempoolExistsReply](api.apiCommon.GetMempoolExists),
+		"mempool/new-tx":                  api_code_websockets.Handle[api_common.APIMempoolNewTxRequest, api_common.APIMempoolNewTxReply](api.apiCommon.MempoolNewTx),
+		"network/nodes":                   api_code_websockets.Handle[struct{}, api_common.APINetworkNodesReply](api.apiCommon.GetNetworkNodes),
+		"wallet/get-addresses":            api_code_websockets.HandleAuthenticated[api_common.APIWalletSelectRequest, api_common.APIWalletGetAccountsReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletAddresses),
+		"wallet/generate-address":         api_code_websockets.HandleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletGenerateAddress),
+		"wallet/create-address":           api_code_websockets.HandleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletCreateAddress),
+		"wallet/delete-address":           api_code_websockets.HandleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletDeleteAddress),
+		"wallet/get-balances":             api_code_websockets.HandleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletBalances),
+		"wallet/decrypt-tx":               api_code_websockets.HandleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletDecryptTx),
+		"wallet/payment-proof":            api_code_websockets.HandleAuthenticated[api_common.APIWalletPaymentProofRequest, api_common.APIWalletPaymentProofReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletPaymentProof),
+		"wallet/history":                  api_code_websockets.HandleAuthenticated[api_common.APIWalletHistoryRequest, api_common.APIWalletHistoryReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletHistory),
+		"wallet/export-history":           api_code_websockets.HandleAuthenticated[api_common.APIWalletExportHistoryRequest, api_common.APIWalletExportHistoryReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletExportHistory),
+		"wallet/create-invoice":           api_code_websockets.HandleAuthenticated[api_common.APIWalletCreateInvoiceRequest, api_common.APIWalletCreateInvoiceReply](network_config_auth.SCOPE_WALLET, api.apiCommon.WalletCreateInvoice),
+		"wallet/get-invoice":              api_code_websockets.HandleAuthenticated[api_common.APIWalletGetInvoiceRequest, api_common.APIWalletGetInvoiceReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletInvoice),
+		"wallet/get-invoices":             api_code_websockets.HandleAuthenticated[api_common.APIWalletGetInvoicesRequest, api_common.APIWalletGetInvoicesReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletInvoices),
+		"wallet/get-contacts":             api_code_websockets.HandleAuthenticated[api_common.APIWalletSelectRequest, api_common.APIWalletGetContactsReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletContacts),
+		"wallet/create-contact":           api_code_websockets.HandleAuthenticated[api_common.APIWalletCreateContactRequest, api_common.APIWalletCreateContactReply](network_config_auth.SCOPE_WALLET, api.apiCommon.WalletCreateContact),
+		"wallet/edit-contact":             api_code_websockets.HandleAuthenticated[api_common.APIWalletEditContactRequest, api_common.APIWalletEditContactReply](network_config_auth.SCOPE_WALLET, api.apiCommon.WalletEditContact),
+		"wallet/delete-contact":           api_code_websockets.HandleAuthenticated[api_common.APIWalletDeleteContactRequest, api_common.APIWalletDeleteContactReply](network_config_auth.SCOPE_WALLET, api.apiCommon.WalletDeleteContact),
+		"wallet/wallets":                  api_code_websockets.HandleAuthenticated[struct{}, api_common.APIWalletWalletsReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetWalletWallets),
+		"wallet/create-wallet":            api_code_websockets.HandleAuthenticated[api_common.APIWalletNameRequest, api_common.APIWalletWalletReply](network_config_auth.SCOPE_WALLET, api.apiCommon.WalletCreateWallet),
+		"wallet/load-wallet":              api_code_websockets.HandleAuthenticated[api_common.APIWalletLoadWalletRequest, api_common.APIWalletWalletReply](network_config_auth.SCOPE_WALLET, api.apiCommon.WalletLoadWallet),
+		"wallet/unload-wallet":            api_code_websockets.HandleAuthenticated[api_common.APIWalletNameRequest, api_common.APIWalletWalletReply](network_config_auth.SCOPE_WALLET, api.apiCommon.WalletUnloadWallet),
+		"wallet/switch-wallet":            api_code_websockets.HandleAuthenticated[api_common.APIWalletNameRequest, api_common.APIWalletWalletReply](network_config_auth.SCOPE_WALLET, api.apiCommon.WalletSwitchWallet),
+		"wallet/unlock":                   api_code_websockets.HandleAuthenticated[api_common.APIWalletUnlockRequest, api_common.APIWalletUnlockReply](network_config_auth.SCOPE_WALLET, api.apiCommon.WalletUnlock),
+		"wallet/lock":                     api_code_websockets.HandleAuthenticated[api_common.APIWalletSelectRequest, api_common.APIWalletLockReply](network_config_auth.SCOPE_WALLET, api.apiCommon.WalletLock),
+		"wallet/forging-stats":            api_code_websockets.HandleAuthenticated[api_common.APIForgingStatsRequest, api_common.APIForgingStatsReply](network_config_auth.SCOPE_WALLET, api.apiCommon.GetForgingStats),
+		"wallet/private-transfer":         api_code_websockets.HandleAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](network_config_auth.SCOPE_WALLET_SPEND, api.apiCommon.WalletPrivateTransfer),
+		"wallet/private-batch-transfer":   api_code_websockets.HandleAuthenticated[api_common.APIWalletPrivateBatchTransferRequest, api_common.APIWalletPrivateBatchTransferReply](network_config_auth.SCOPE_WALLET_SPEND, api.apiCommon.WalletPrivateBatchTransfer),
+		"wallet/balance-proofs":           api_code_websockets.HandleAuthenticated[api_common.APIWalletBalanceProofsRequest, api_common.APIWalletBalanceProofsReply](network_config_auth.SCOPE_WALLET, api.apiCommon.WalletBalanceProofs),
+		"wallet/private-transfer-prepare": api_code_websockets.HandleAuthenticated[api_common.APIWalletPrivateTransferPrepareRequest, api_common.APIWalletPrivateTransferPrepareReply](network_config_auth.SCOPE_WALLET, api.apiCommon.WalletPrivateTransferPrepare),
+		"wallet/private-transfer-sign":    api_code_websockets.HandleAuthenticated[api_common.APIWalletPrivateTransferSignRequest, api_common.APIWalletPrivateTransferSignReply](network_config_auth.SCOPE_WALLET_SPEND, api.apiCommon.WalletPrivateTransferSign),
 		//below are ONLY websockets API
 		"block-miss-txs":    api_code_websockets.Handle[consensus.APIBlockCompleteMissingTxsRequest, consensus.APIBlockCompleteMissingTxsReply](api.Consensus.GetBlockCompleteMissingTxs),
 		"handshake":         api_code_websockets.Handshake,
@@ -111,12 +153,30 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 
 	if api.apiCommon.DelegatorNode != nil {
 		api.GetMap["delegator-node/info"] = api_code_websockets.Handle[struct{}, api_delegator_node.ApiDelegatorNodeInfoReply](api.apiCommon.DelegatorNode.GetDelegatorNodeInfo)
-		api.GetMap["delegator-node/notify"] = api_code_websockets.HandleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](api.apiCommon.DelegatorNode.DelegatorNotify)
+		api.GetMap["delegator-node/notify"] = api_code_websockets.HandleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](network_config_auth.SCOPE_DELEGATOR, api.apiCommon.DelegatorNode.DelegatorNotify)
+		api.GetMap["delegator-node/revoke"] = api_code_websockets.HandleAuthenticated[api_delegator_node.ApiDelegatorNodeRevokeRequest, api_delegator_node.ApiDelegatorNodeRevokeReply](network_config_auth.SCOPE_DELEGATOR, api.apiCommon.DelegatorNode.DelegatorRevoke)
+		api.GetMap["delegator-node/list"] = api_code_websockets.HandleAuthenticated[struct{}, api_delegator_node.ApiDelegatorNodeListReply](network_config_auth.SCOPE_ADMIN, api.apiCommon.DelegatorNode.GetDelegatorList)
+	}
+
+	if api.apiCommon.Cosigner != nil {
+		api.GetMap["cosigner/accounts"] = api_code_websockets.HandleAuthenticated[struct{}, api_cosigner.ApiCosignerAccountsReply](network_config_auth.SCOPE_ADMIN, api.apiCommon.Cosigner.GetAccounts)
+		api.GetMap["cosigner/audit-log"] = api_code_websockets.HandleAuthenticated[api_cosigner.ApiCosignerAuditLogRequest, api_cosigner.ApiCosignerAuditLogReply](network_config_auth.SCOPE_ADMIN, api.apiCommon.Cosigner.GetAuditLog)
+		api.GetMap["cosigner/create-account"] = api_code_websockets.HandleAuthenticated[api_cosigner.ApiCosignerCreateAccountRequest, api_cosigner.ApiCosignerCreateAccountReply](network_config_auth.SCOPE_ADMIN, api.apiCommon.Cosigner.CreateAccount)
+		api.GetMap["cosigner/update-account"] = api_code_websockets.HandleAuthenticated[api_cosigner.ApiCosignerUpdateAccountRequest, api_cosigner.ApiCosignerUpdateAccountReply](network_config_auth.SCOPE_ADMIN, api.apiCommon.Cosigner.UpdateAccount)
+		api.GetMap["cosigner/sign"] = api_code_websockets.HandleAuthenticated[api_cosigner.ApiCosignerSignRequest, api_cosigner.ApiCosignerSignReply](network_config_auth.SCOPE_COSIGNER, api.apiCommon.Cosigner.Sign)
 	}
 
 	if ConfigureAPIRoutes != nil {
 		ConfigureAPIRoutes(api)
 	}
 
+	if !network_config.API_WALLET_PUBLIC {
+		for key, callback := range api.GetMap {
+			if strings.HasPrefix(key, "wallet/") {
+				api.GetMap[key] = localOnly(callback)
+			}
+		}
+	}
+
 	return api
 }
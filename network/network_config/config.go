@@ -1,6 +1,7 @@
 package network_config
 
 import (
+	"net"
 	"pandora-pay/config"
 	"pandora-pay/config/arguments"
 	"pandora-pay/network/network_config/network_config_auth"
@@ -18,6 +19,7 @@ var (
 	NETWORK_ENABLE_SUBSCRIPTIONS               = false
 	NETWORK_CONNECTIONS_READY_THRESHOLD        = int64(1)
 	STATIC_FILES                               = map[string]string{}
+	API_WALLET_PUBLIC                          = false
 )
 
 const (
@@ -67,6 +69,14 @@ func InitConfig() (err error) {
 		STATIC_FILES["/static/challenge/"] = "../../../static/challenge"
 	}
 
+	if arguments.Arguments["--api-wallet-public"] == "true" {
+		API_WALLET_PUBLIC = true
+	}
+
+	if err = initRateLimitConfig(); err != nil {
+		return
+	}
+
 	if err = network_config_auth.InitConfig(); err != nil {
 		return
 	}
@@ -75,3 +85,13 @@ func InitConfig() (err error) {
 
 	return
 }
+
+// IsLocalAddress returns true for loopback remote addresses. Requests forwarded by a local reverse proxy are local too
+func IsLocalAddress(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
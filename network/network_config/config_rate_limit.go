@@ -0,0 +1,100 @@
+package network_config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"pandora-pay/config/arguments"
+	"strconv"
+)
+
+type rateLimitConfig struct {
+	Rate    float64            `json:"rate"`
+	Burst   float64            `json:"burst"`
+	Weights map[string]float64 `json:"weights"`
+}
+
+var (
+	RATE_LIMIT_RATE  = float64(100) //tokens refilled per second. 0 disables the rate limiter
+	RATE_LIMIT_BURST = float64(500)
+	//weight of the routes. Missing routes have the weight 1
+	RATE_LIMIT_WEIGHTS = map[string]float64{
+		"block-complete":       2,
+		"accounts/by-keys":     5,
+		"mempool/new-tx":       5,
+		"faucet/coins":         50,
+		"faucet/info":          2,
+		"tx/privacy-report":    50, //loads the rings of all the payloads
+		"balance-proof/verify": 20, //reverts the account using the changes of the blocks after the proof
+	}
+	//websockets routes used by the nodes to sync the chain and the mempool. They are not rate limited for the handshaken peer nodes, otherwise the peers syncing would be penalized
+	RATE_LIMIT_PEER_EXEMPT_ROUTES = map[string]bool{
+		"get-chain":         true,
+		"chain-update":      true,
+		"block-hash":        true,
+		"block":             true,
+		"block-miss-txs":    true,
+		"tx-raw":            true,
+		"mempool/new-tx-id": true,
+		"network/nodes":     true,
+	}
+)
+
+const (
+	WEBSOCKETS_RATE_LIMIT_SCORE_PENALTY = int32(-5)
+)
+
+func initRateLimitConfig() (err error) {
+
+	if str := arguments.Arguments["--rate-limit-config"]; str != nil {
+
+		var data []byte
+		if data, err = os.ReadFile(str.(string)); err != nil {
+			return
+		}
+
+		cfg := &rateLimitConfig{RATE_LIMIT_RATE, RATE_LIMIT_BURST, nil}
+		if err = json.Unmarshal(data, cfg); err != nil {
+			return
+		}
+
+		RATE_LIMIT_RATE = cfg.Rate
+		RATE_LIMIT_BURST = cfg.Burst
+		for route, weight := range cfg.Weights {
+			RATE_LIMIT_WEIGHTS[route] = weight
+		}
+	}
+
+	if str := arguments.Arguments["--rate-limit-rate"]; str != nil {
+		if RATE_LIMIT_RATE, err = strconv.ParseFloat(str.(string), 64); err != nil {
+			return
+		}
+	}
+
+	if str := arguments.Arguments["--rate-limit-burst"]; str != nil {
+		if RATE_LIMIT_BURST, err = strconv.ParseFloat(str.(string), 64); err != nil {
+			return
+		}
+	}
+
+	if str := arguments.Arguments["--rate-limit-weights"]; str != nil {
+		weights := map[string]float64{}
+		if err = json.Unmarshal([]byte(str.(string)), &weights); err != nil {
+			return
+		}
+		for route, weight := range weights {
+			RATE_LIMIT_WEIGHTS[route] = weight
+		}
+	}
+
+	if RATE_LIMIT_RATE < 0 || RATE_LIMIT_BURST < 0 {
+		return errors.New("Rate limit values can not be negative")
+	}
+	for _, weight := range RATE_LIMIT_WEIGHTS {
+		if weight < 0 {
+			return errors.New("Rate limit weights can not be negative")
+		}
+	}
+
+	return
+}
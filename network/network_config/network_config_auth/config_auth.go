@@ -3,11 +3,15 @@ package network_config_auth
 import (
 	"encoding/json"
 	"pandora-pay/config/arguments"
+	"pandora-pay/helpers"
 )
 
+// ConfigAuth users are deprecated, use API tokens instead. The users have admin scope
 type ConfigAuth struct {
 	Username string `json:"user" msgpack:"user"`
 	Password string `json:"pass"  msgpack:"pass"`
+	salt     []byte
+	hash     []byte
 }
 
 var (
@@ -25,6 +29,10 @@ func InitConfig() (err error) {
 
 	CONFIG_AUTH_USERS_MAP = map[string]*ConfigAuth{}
 	for _, auth := range CONFIG_AUTH_USERS_LIST {
+		//the password is not kept in plain text
+		auth.salt = helpers.RandomBytes(32)
+		auth.hash = hashSecret(auth.salt, []byte(auth.Password))
+		auth.Password = ""
 		CONFIG_AUTH_USERS_MAP[auth.Username] = auth
 	}
 
@@ -0,0 +1,71 @@
+package network_config_auth
+
+import (
+	"errors"
+	"strings"
+)
+
+type AuthScope string
+
+const (
+	SCOPE_READ         AuthScope = "read"
+	SCOPE_WALLET       AuthScope = "wallet"
+	SCOPE_WALLET_SPEND AuthScope = "wallet-spend"
+	SCOPE_DELEGATOR    AuthScope = "delegator"
+	SCOPE_COSIGNER     AuthScope = "cosigner"
+	SCOPE_ADMIN        AuthScope = "admin"
+)
+
+// scopes granted implicitly by a scope
+var scopeIncludes = map[AuthScope][]AuthScope{
+	SCOPE_READ:         {},
+	SCOPE_WALLET:       {SCOPE_READ},
+	SCOPE_WALLET_SPEND: {SCOPE_WALLET, SCOPE_READ},
+	SCOPE_DELEGATOR:    {SCOPE_READ},
+	SCOPE_COSIGNER:     {SCOPE_READ},
+	SCOPE_ADMIN:        {SCOPE_READ, SCOPE_WALLET, SCOPE_WALLET_SPEND, SCOPE_DELEGATOR, SCOPE_COSIGNER},
+}
+
+func (scope AuthScope) Validate() error {
+	if _, ok := scopeIncludes[scope]; !ok {
+		return errors.New("Invalid scope " + string(scope))
+	}
+	return nil
+}
+
+func (scope AuthScope) Includes(required AuthScope) bool {
+	if scope == required {
+		return true
+	}
+	for _, included := range scopeIncludes[scope] {
+		if included == required {
+			return true
+		}
+	}
+	return false
+}
+
+func HasScope(scopes []AuthScope, required AuthScope) bool {
+	for _, scope := range scopes {
+		if scope.Includes(required) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseScopes parses a list of scopes separated by "|"
+func ParseScopes(str string) ([]AuthScope, error) {
+
+	list := strings.Split(str, "|")
+	scopes := make([]AuthScope, 0, len(list))
+	for _, s := range list {
+		scope := AuthScope(strings.TrimSpace(s))
+		if err := scope.Validate(); err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, scope)
+	}
+
+	return scopes, nil
+}
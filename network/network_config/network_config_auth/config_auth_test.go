@@ -0,0 +1,157 @@
+package network_config_auth
+
+import (
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/config/arguments"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"strings"
+	"testing"
+)
+
+func initAuthTestStore(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("settings")
+	assert.NoError(t, err)
+
+	store.StoreSettings = &store.Store{"settings", true, db}
+
+	authTokensLock.Lock()
+	authTokens = map[string]*AuthToken{}
+	authTokensLock.Unlock()
+}
+
+func TestAuthTokens(t *testing.T) {
+
+	initAuthTestStore(t)
+
+	token, err := CreateAuthToken("merchant", []AuthScope{SCOPE_WALLET})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, "merchant:"))
+
+	identity, scopes := Authenticate("", "", token)
+	assert.Equal(t, "token:merchant", identity)
+	assert.Equal(t, []AuthScope{SCOPE_WALLET}, scopes)
+
+	//the secret is stored only as a salted hash
+	stored := GetAuthTokens()
+	assert.Len(t, stored, 1)
+	secret, err := base64.RawURLEncoding.DecodeString(token[len("merchant:"):])
+	assert.NoError(t, err)
+	assert.Len(t, stored[0].Salt, 32)
+	assert.Equal(t, hashSecret(stored[0].Salt, secret), stored[0].Hash)
+	assert.NotEqual(t, hashSecret(nil, secret), stored[0].Hash)
+
+	//the tokens are loaded from the store
+	authTokens = map[string]*AuthToken{}
+	identity, _ = Authenticate("", "", token)
+	assert.Equal(t, "", identity)
+	assert.NoError(t, loadAuthTokens())
+	identity, _ = Authenticate("", "", token)
+	assert.Equal(t, "token:merchant", identity)
+
+	for _, invalid := range []string{
+		"",
+		"merchant",
+		"merchant:",
+		"other:" + token[len("merchant:"):],
+		"merchant:" + base64.RawURLEncoding.EncodeToString(helpers.RandomBytes(32)),
+		token + "A",
+	} {
+		identity, scopes = Authenticate("", "", invalid)
+		assert.Equal(t, "", identity, invalid)
+		assert.Nil(t, scopes, invalid)
+	}
+
+	_, err = CreateAuthToken("merchant", []AuthScope{SCOPE_READ})
+	assert.Error(t, err, "duplicate name")
+	_, err = CreateAuthToken("mer:chant", []AuthScope{SCOPE_READ})
+	assert.Error(t, err, "invalid name")
+	_, err = CreateAuthToken("empty", nil)
+	assert.Error(t, err, "no scopes")
+	_, err = CreateAuthToken("root", []AuthScope{"root"})
+	assert.Error(t, err, "invalid scope")
+
+	assert.NoError(t, RevokeAuthToken("merchant"))
+	identity, scopes = Authenticate("", "", token)
+	assert.Equal(t, "", identity)
+	assert.Nil(t, scopes)
+	assert.Error(t, RevokeAuthToken("merchant"))
+
+	//the revocation is persisted
+	assert.NoError(t, loadAuthTokens())
+	assert.Len(t, GetAuthTokens(), 0)
+}
+
+func TestAuthTokenWrongSalt(t *testing.T) {
+
+	initAuthTestStore(t)
+
+	token, err := CreateAuthToken("exchange", []AuthScope{SCOPE_WALLET_SPEND})
+	assert.NoError(t, err)
+
+	authTokens["exchange"].Salt = helpers.RandomBytes(32)
+
+	identity, scopes := Authenticate("", "", token)
+	assert.Equal(t, "", identity)
+	assert.Nil(t, scopes)
+}
+
+func TestAuthScopes(t *testing.T) {
+
+	all := []AuthScope{SCOPE_READ, SCOPE_WALLET, SCOPE_WALLET_SPEND, SCOPE_DELEGATOR, SCOPE_COSIGNER, SCOPE_ADMIN}
+
+	for _, scope := range all {
+		assert.True(t, HasScope([]AuthScope{SCOPE_ADMIN}, scope), scope)
+		assert.True(t, HasScope([]AuthScope{scope}, scope), scope)
+		assert.True(t, HasScope([]AuthScope{scope}, SCOPE_READ), scope)
+	}
+
+	for _, test := range []struct {
+		scopes   []AuthScope
+		required AuthScope
+	}{
+		{nil, SCOPE_READ},
+		{[]AuthScope{SCOPE_READ}, SCOPE_WALLET},
+		{[]AuthScope{SCOPE_WALLET}, SCOPE_WALLET_SPEND},
+		{[]AuthScope{SCOPE_WALLET_SPEND}, SCOPE_DELEGATOR},
+		{[]AuthScope{SCOPE_DELEGATOR, SCOPE_COSIGNER}, SCOPE_WALLET},
+		{[]AuthScope{SCOPE_WALLET_SPEND}, SCOPE_ADMIN},
+	} {
+		assert.False(t, HasScope(test.scopes, test.required), test.required)
+	}
+
+	assert.True(t, HasScope([]AuthScope{SCOPE_WALLET_SPEND}, SCOPE_WALLET))
+
+	scopes, err := ParseScopes("read| wallet-spend")
+	assert.NoError(t, err)
+	assert.Equal(t, []AuthScope{SCOPE_READ, SCOPE_WALLET_SPEND}, scopes)
+
+	_, err = ParseScopes("read|root")
+	assert.Error(t, err)
+}
+
+func TestAuthUsers(t *testing.T) {
+
+	arguments.Arguments = map[string]any{"--auth-users": `[{"user": "admin", "pass": "secret"}]`}
+	defer func() {
+		arguments.Arguments = nil
+	}()
+
+	assert.NoError(t, InitConfig())
+
+	//the password is not kept in plain text
+	assert.Equal(t, "", CONFIG_AUTH_USERS_MAP["admin"].Password)
+
+	identity, scopes := Authenticate("admin", "secret", "")
+	assert.Equal(t, "user:admin", identity)
+	assert.True(t, HasScope(scopes, SCOPE_WALLET_SPEND))
+
+	for _, credentials := range [][2]string{{"admin", "wrong"}, {"admin", ""}, {"other", "secret"}, {"", ""}} {
+		identity, scopes = Authenticate(credentials[0], credentials[1], "")
+		assert.Equal(t, "", identity, credentials)
+		assert.Nil(t, scopes, credentials)
+	}
+}
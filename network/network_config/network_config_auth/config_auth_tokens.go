@@ -0,0 +1,194 @@
+package network_config_auth
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthToken stores only the salted hash of the secret. The secret is shown once when the token is created
+type AuthToken struct {
+	Name    string      `json:"name" msgpack:"name"`
+	Scopes  []AuthScope `json:"scopes" msgpack:"scopes"`
+	Salt    []byte      `json:"-" msgpack:"salt"`
+	Hash    []byte      `json:"-" msgpack:"hash"`
+	Created int64       `json:"created" msgpack:"created"`
+}
+
+var (
+	authTokens     = map[string]*AuthToken{}
+	authTokensLock = &sync.RWMutex{}
+)
+
+func hashSecret(salt, secret []byte) []byte {
+	data := make([]byte, len(salt)+len(secret))
+	copy(data, salt)
+	copy(data[len(salt):], secret)
+	return cryptography.SHA3(data)
+}
+
+func (token *AuthToken) verifySecret(secret []byte) bool {
+	return subtle.ConstantTimeCompare(hashSecret(token.Salt, secret), token.Hash) == 1
+}
+
+func saveAuthTokens() error {
+
+	list := make([]*AuthToken, 0, len(authTokens))
+	for _, token := range authTokens {
+		list = append(list, token)
+	}
+
+	data, err := msgpack.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Put("authTokens", data)
+		return nil
+	})
+}
+
+func loadAuthTokens() error {
+	return store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		data := reader.Get("authTokens")
+		if data == nil {
+			return
+		}
+
+		list := []*AuthToken{}
+		if err = msgpack.Unmarshal(data, &list); err != nil {
+			return
+		}
+
+		authTokensLock.Lock()
+		defer authTokensLock.Unlock()
+
+		authTokens = make(map[string]*AuthToken)
+		for _, token := range list {
+			authTokens[token.Name] = token
+		}
+		return
+	})
+}
+
+// CreateAuthToken returns the token that must be provided by the clients. Format "name:secret"
+func CreateAuthToken(name string, scopes []AuthScope) (string, error) {
+
+	if len(name) == 0 || len(name) > 32 || strings.Contains(name, ":") {
+		return "", errors.New("Token name is invalid")
+	}
+	if len(scopes) == 0 {
+		return "", errors.New("At least one scope is required")
+	}
+	for _, scope := range scopes {
+		if err := scope.Validate(); err != nil {
+			return "", err
+		}
+	}
+
+	authTokensLock.Lock()
+	defer authTokensLock.Unlock()
+
+	if authTokens[name] != nil {
+		return "", errors.New("Token name already exists")
+	}
+
+	secret := helpers.RandomBytes(32)
+	salt := helpers.RandomBytes(32)
+
+	authTokens[name] = &AuthToken{name, scopes, salt, hashSecret(salt, secret), time.Now().Unix()}
+	if err := saveAuthTokens(); err != nil {
+		delete(authTokens, name)
+		return "", err
+	}
+
+	return name + ":" + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func RevokeAuthToken(name string) error {
+
+	authTokensLock.Lock()
+	defer authTokensLock.Unlock()
+
+	token := authTokens[name]
+	if token == nil {
+		return errors.New("Token was not found")
+	}
+
+	delete(authTokens, name)
+	if err := saveAuthTokens(); err != nil {
+		authTokens[name] = token
+		return err
+	}
+
+	return nil
+}
+
+func GetAuthTokens() []*AuthToken {
+
+	authTokensLock.RLock()
+	defer authTokensLock.RUnlock()
+
+	list := make([]*AuthToken, 0, len(authTokens))
+	for _, token := range authTokens {
+		list = append(list, token)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+func authenticateToken(str string) (string, []AuthScope) {
+
+	index := strings.LastIndex(str, ":")
+	if index == -1 {
+		return "", nil
+	}
+
+	secret, err := base64.RawURLEncoding.DecodeString(str[index+1:])
+	if err != nil {
+		return "", nil
+	}
+
+	authTokensLock.RLock()
+	token := authTokens[str[:index]]
+	authTokensLock.RUnlock()
+
+	if token == nil || !token.verifySecret(secret) {
+		return "", nil
+	}
+
+	return "token:" + token.Name, token.Scopes
+}
+
+// Authenticate returns the identity and the scopes granted by a token or by the credentials of a user
+func Authenticate(username, password, token string) (string, []AuthScope) {
+
+	if token != "" {
+		return authenticateToken(token)
+	}
+
+	if username == "" {
+		return "", nil
+	}
+
+	user := CONFIG_AUTH_USERS_MAP[username]
+	if user == nil || subtle.ConstantTimeCompare(hashSecret(user.salt, []byte(password)), user.hash) != 1 {
+		return "", nil
+	}
+
+	return "user:" + username, []AuthScope{SCOPE_ADMIN}
+}
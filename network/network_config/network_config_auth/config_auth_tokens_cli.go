@@ -0,0 +1,92 @@
+package network_config_auth
+
+import (
+	"context"
+	"errors"
+	"pandora-pay/config/arguments"
+	"pandora-pay/gui"
+	"strings"
+	"time"
+)
+
+func cliAuthTokenCreate(cmd string, ctx context.Context) (err error) {
+
+	name := gui.GUI.OutputReadString("Token name")
+
+	scopes, err := ParseScopes(gui.GUI.OutputReadString("Scopes separated by | (read, wallet, wallet-spend, delegator, admin)"))
+	if err != nil {
+		return
+	}
+
+	token, err := CreateAuthToken(name, scopes)
+	if err != nil {
+		return
+	}
+
+	gui.GUI.OutputWrite("Token", token)
+	gui.GUI.OutputWrite("The token is displayed only once")
+
+	return
+}
+
+func cliAuthTokenRevoke(cmd string, ctx context.Context) (err error) {
+
+	if err = RevokeAuthToken(gui.GUI.OutputReadString("Token name")); err != nil {
+		return
+	}
+
+	gui.GUI.OutputWrite("Token revoked")
+	return
+}
+
+func cliAuthTokensList(cmd string, ctx context.Context) (err error) {
+
+	for _, token := range GetAuthTokens() {
+		scopes := make([]string, len(token.Scopes))
+		for i, scope := range token.Scopes {
+			scopes[i] = string(scope)
+		}
+		gui.GUI.OutputWrite(token.Name, strings.Join(scopes, "|"), time.Unix(token.Created, 0).UTC().Format(time.RFC3339))
+	}
+
+	return
+}
+
+func InitAuthTokens() (err error) {
+
+	if err = loadAuthTokens(); err != nil {
+		return
+	}
+
+	if str := arguments.Arguments["--auth-token-revoke"]; str != nil {
+		if err = RevokeAuthToken(str.(string)); err != nil {
+			return
+		}
+		gui.GUI.Info("API Token revoked", str.(string))
+	}
+
+	if str := arguments.Arguments["--auth-token-create"]; str != nil {
+
+		args := strings.Split(str.(string), ",")
+		if len(args) != 2 {
+			return errors.New("--auth-token-create must be name,scopes")
+		}
+
+		var scopes []AuthScope
+		if scopes, err = ParseScopes(args[1]); err != nil {
+			return
+		}
+
+		var token string
+		if token, err = CreateAuthToken(args[0], scopes); err != nil {
+			return
+		}
+		gui.GUI.Info("API Token created. It is displayed only once", token)
+	}
+
+	gui.GUI.CommandDefineCallback("API Token Create", cliAuthTokenCreate, true)
+	gui.GUI.CommandDefineCallback("API Token Revoke", cliAuthTokenRevoke, true)
+	gui.GUI.CommandDefineCallback("API Tokens List", cliAuthTokensList, true)
+
+	return
+}
@@ -0,0 +1,118 @@
+package rate_limiter
+
+import (
+	"net"
+	"pandora-pay/helpers/metrics"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/network/network_config"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// RateLimiterType is a token bucket per client. The client is the remote IP or the authenticated user
+type RateLimiterType struct {
+	buckets map[string]*bucket
+	lock    *sync.Mutex
+	now     func() time.Time
+}
+
+var RateLimiter *RateLimiterType
+
+var metricsRejected = metrics.NewCounterVec("rate_limit_rejections_total", "API requests rejected by the rate limiter by route", "route")
+
+func (this *RateLimiterType) refill(b *bucket, now time.Time) {
+	b.tokens += now.Sub(b.updated).Seconds() * network_config.RATE_LIMIT_RATE
+	if b.tokens > network_config.RATE_LIMIT_BURST {
+		b.tokens = network_config.RATE_LIMIT_BURST
+	}
+	b.updated = now
+}
+
+func (this *RateLimiterType) take(key string, weight float64) bool {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	now := this.now()
+
+	b := this.buckets[key]
+	if b == nil {
+		b = &bucket{network_config.RATE_LIMIT_BURST, now}
+		this.buckets[key] = b
+	} else {
+		this.refill(b, now)
+	}
+
+	if b.tokens < weight {
+		return false
+	}
+
+	b.tokens -= weight
+	return true
+}
+
+// Allow spends the weight of the route from the bucket of the client
+func (this *RateLimiterType) Allow(route, client string) bool {
+
+	if network_config.RATE_LIMIT_RATE == 0 {
+		return true
+	}
+
+	weight, ok := network_config.RATE_LIMIT_WEIGHTS[route]
+	if !ok {
+		weight = 1
+	}
+
+	if this.take(client, weight) {
+		return true
+	}
+
+	metricsRejected.WithLabel(route).Inc()
+	return false
+}
+
+// prune removes the buckets that got full, as they are identical to new ones
+func (this *RateLimiterType) prune() {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	now := this.now()
+	for key, b := range this.buckets {
+		if this.refill(b, now); b.tokens >= network_config.RATE_LIMIT_BURST {
+			delete(this.buckets, key)
+		}
+	}
+}
+
+func (this *RateLimiterType) pruneProcess() {
+	for {
+		time.Sleep(time.Minute)
+		this.prune()
+	}
+}
+
+// GetClientKey identifies the client by the authenticated user or by the remote IP
+func GetClientKey(remoteAddr, authName string) string {
+	if authName != "" {
+		return "auth:" + authName
+	}
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+func init() {
+	RateLimiter = &RateLimiterType{
+		make(map[string]*bucket),
+		&sync.Mutex{},
+		time.Now,
+	}
+	recovery.SafeGo(RateLimiter.pruneProcess)
+}
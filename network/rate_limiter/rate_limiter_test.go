@@ -0,0 +1,149 @@
+package rate_limiter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/network/network_config"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (clock *testClock) advance(duration time.Duration) {
+	clock.now = clock.now.Add(duration)
+}
+
+func createTestRateLimiter(t *testing.T, rate, burst float64, weights map[string]float64) (*RateLimiterType, *testClock) {
+
+	oldRate, oldBurst, oldWeights := network_config.RATE_LIMIT_RATE, network_config.RATE_LIMIT_BURST, network_config.RATE_LIMIT_WEIGHTS
+	network_config.RATE_LIMIT_RATE, network_config.RATE_LIMIT_BURST, network_config.RATE_LIMIT_WEIGHTS = rate, burst, weights
+	t.Cleanup(func() {
+		network_config.RATE_LIMIT_RATE, network_config.RATE_LIMIT_BURST, network_config.RATE_LIMIT_WEIGHTS = oldRate, oldBurst, oldWeights
+	})
+
+	clock := &testClock{time.Unix(1000000, 0)}
+
+	return &RateLimiterType{
+		make(map[string]*bucket),
+		&sync.Mutex{},
+		func() time.Time { return clock.now },
+	}, clock
+}
+
+func TestRateLimiterBurstAndRefill(t *testing.T) {
+
+	limiter, clock := createTestRateLimiter(t, 2, 10, map[string]float64{})
+
+	for i := 0; i < 10; i++ {
+		assert.True(t, limiter.Allow("block", "1.2.3.4"), i)
+	}
+	assert.False(t, limiter.Allow("block", "1.2.3.4"))
+
+	//2 tokens per second
+	clock.advance(500 * time.Millisecond)
+	assert.True(t, limiter.Allow("block", "1.2.3.4"))
+	assert.False(t, limiter.Allow("block", "1.2.3.4"))
+
+	clock.advance(time.Second)
+	assert.True(t, limiter.Allow("block", "1.2.3.4"))
+	assert.True(t, limiter.Allow("block", "1.2.3.4"))
+	assert.False(t, limiter.Allow("block", "1.2.3.4"))
+
+	//the bucket never holds more than the burst
+	clock.advance(time.Hour)
+	for i := 0; i < 10; i++ {
+		assert.True(t, limiter.Allow("block", "1.2.3.4"), i)
+	}
+	assert.False(t, limiter.Allow("block", "1.2.3.4"))
+}
+
+func TestRateLimiterWeights(t *testing.T) {
+
+	limiter, clock := createTestRateLimiter(t, 1, 10, map[string]float64{"block-complete": 4, "faucet/coins": 11})
+
+	assert.True(t, limiter.Allow("block-complete", "1.2.3.4"))
+	assert.True(t, limiter.Allow("block-complete", "1.2.3.4"))
+	assert.False(t, limiter.Allow("block-complete", "1.2.3.4"))
+
+	//the routes without weight spend 1
+	assert.True(t, limiter.Allow("block", "1.2.3.4"))
+	assert.True(t, limiter.Allow("block", "1.2.3.4"))
+	assert.False(t, limiter.Allow("block", "1.2.3.4"))
+
+	//a rejected request doesn't spend tokens
+	clock.advance(4 * time.Second)
+	assert.True(t, limiter.Allow("block-complete", "1.2.3.4"))
+	assert.False(t, limiter.Allow("block", "1.2.3.4"))
+
+	//a route heavier than the burst is never allowed
+	clock.advance(time.Hour)
+	assert.False(t, limiter.Allow("faucet/coins", "1.2.3.4"))
+}
+
+func TestRateLimiterClients(t *testing.T) {
+
+	limiter, _ := createTestRateLimiter(t, 1, 3, map[string]float64{})
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, limiter.Allow("block", GetClientKey("1.2.3.4:1000", "")))
+	}
+
+	//the ports of the same IP share the bucket
+	assert.False(t, limiter.Allow("block", GetClientKey("1.2.3.4:2000", "")))
+
+	//other IPs and the authenticated users have their own buckets
+	assert.True(t, limiter.Allow("block", GetClientKey("5.6.7.8:1000", "")))
+	assert.True(t, limiter.Allow("block", GetClientKey("1.2.3.4:1000", "merchant")))
+
+	assert.Equal(t, "1.2.3.4", GetClientKey("1.2.3.4:1000", ""))
+	assert.Equal(t, "::1", GetClientKey("[::1]:1000", ""))
+	assert.Equal(t, "auth:merchant", GetClientKey("1.2.3.4:1000", "merchant"))
+	assert.Equal(t, "1.2.3.4", GetClientKey("1.2.3.4", ""))
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+
+	limiter, _ := createTestRateLimiter(t, 0, 1, map[string]float64{})
+
+	for i := 0; i < 100; i++ {
+		assert.True(t, limiter.Allow("block", "1.2.3.4"))
+	}
+	assert.Len(t, limiter.buckets, 0)
+}
+
+func TestRateLimiterMetrics(t *testing.T) {
+
+	limiter, _ := createTestRateLimiter(t, 1, 1, map[string]float64{})
+
+	rejected := metricsRejected.WithLabel("tx-raw").Get()
+
+	assert.True(t, limiter.Allow("tx-raw", "1.2.3.4"))
+	assert.False(t, limiter.Allow("tx-raw", "1.2.3.4"))
+	assert.False(t, limiter.Allow("tx-raw", "1.2.3.4"))
+
+	assert.Equal(t, rejected+2, metricsRejected.WithLabel("tx-raw").Get())
+}
+
+func TestRateLimiterPrune(t *testing.T) {
+
+	limiter, clock := createTestRateLimiter(t, 1, 10, map[string]float64{})
+
+	assert.True(t, limiter.Allow("block", "1.2.3.4"))
+	for i := 0; i < 5; i++ {
+		assert.True(t, limiter.Allow("block", "5.6.7.8"))
+	}
+
+	clock.advance(2 * time.Second)
+	limiter.prune()
+
+	//only the full buckets are removed
+	assert.Len(t, limiter.buckets, 1)
+	assert.NotNil(t, limiter.buckets["5.6.7.8"])
+
+	clock.advance(3 * time.Second)
+	limiter.prune()
+	assert.Len(t, limiter.buckets, 0)
+}
@@ -28,7 +28,7 @@ func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, me
 	}
 
 	apiWebsockets := api_websockets.NewWebsocketsAPI(apiStore, apiCommon, chain, settings, mempool)
-	websocks.NewWebsockets(chain, mempool, settings, apiWebsockets.GetMap)
+	websocks.NewWebsockets(chain, mempool, wallet, settings, apiWebsockets.GetMap)
 
 	HttpServer = &httpServerType{
 		apiWebsockets,
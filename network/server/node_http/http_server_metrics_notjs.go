@@ -0,0 +1,59 @@
+//go:build !wasm
+// +build !wasm
+
+package node_http
+
+import (
+	"net/http"
+	"pandora-pay/blockchain"
+	"pandora-pay/helpers/metrics"
+	"pandora-pay/mempool"
+	"pandora-pay/network/connected_nodes"
+	"sync/atomic"
+)
+
+func (this *httpServerType) metrics(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := metrics.Write(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func initMetrics(chain *blockchain.Blockchain, mempool *mempool.Mempool) {
+
+	metrics.NewGaugeFunc("chain_height", "Blockchain height", func() float64 {
+		return float64(chain.GetChainData().Height)
+	})
+
+	metrics.NewGaugeFunc("chain_total_difficulty", "Blockchain total difficulty", func() float64 {
+		value, _ := chain.GetChainData().BigTotalDifficulty.Float64()
+		return value
+	})
+
+	metrics.NewGaugeFunc("chain_sync", "Blockchain is synchronized with the network", func() float64 {
+		if chain.Sync.GetSyncData().Sync {
+			return 1
+		}
+		return 0
+	})
+
+	metrics.NewGaugeFunc("chain_sync_time", "Unix time when the blockchain got synchronized", func() float64 {
+		return float64(chain.Sync.GetSyncTime())
+	})
+
+	metrics.NewGaugeFunc("mempool_transactions", "Transactions in mempool", func() float64 {
+		return float64(mempool.Txs.GetCount())
+	})
+
+	metrics.NewGaugeFunc("mempool_bytes", "Size of the transactions in mempool", func() float64 {
+		return float64(mempool.Txs.GetBytes())
+	})
+
+	metrics.NewGaugeVecFunc("peers", "Connected peers by direction", "direction", func() map[string]float64 {
+		return map[string]float64{
+			"inbound":  float64(atomic.LoadInt64(&connected_nodes.ConnectedNodes.ServerSockets)),
+			"outbound": float64(atomic.LoadInt64(&connected_nodes.ConnectedNodes.Clients)),
+		}
+	})
+
+}
@@ -16,10 +16,13 @@ import (
 	"pandora-pay/network/api_implementation/api_http"
 	"pandora-pay/network/api_implementation/api_websockets"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
+	"pandora-pay/network/rate_limiter"
 	"pandora-pay/network/server/node_http_rpc"
 	"pandora-pay/network/websocks"
 	"pandora-pay/settings"
 	"pandora-pay/wallet"
+	"strings"
 )
 
 type httpServerType struct {
@@ -32,6 +35,17 @@ type httpServerType struct {
 
 var HttpServer *httpServerType
 
+// rateLimitAllowed identifies the authenticated users using the credentials provided in the url
+func (this *httpServerType) rateLimitAllowed(req *http.Request) bool {
+	query := req.URL.Query()
+	name, _ := network_config_auth.Authenticate(query.Get("user"), query.Get("pass"), query.Get("token"))
+	return rate_limiter.RateLimiter.Allow(strings.TrimPrefix(req.URL.Path, "/"), rate_limiter.GetClientKey(req.RemoteAddr, name))
+}
+
+func (this *httpServerType) walletAllowed(req *http.Request) bool {
+	return network_config.API_WALLET_PUBLIC || !strings.HasPrefix(req.URL.Path, "/wallet/") || network_config.IsLocalAddress(req.RemoteAddr)
+}
+
 func (this *httpServerType) get(w http.ResponseWriter, req *http.Request) {
 
 	defer func() {
@@ -40,6 +54,16 @@ func (this *httpServerType) get(w http.ResponseWriter, req *http.Request) {
 		}
 	}()
 
+	if !this.walletAllowed(req) {
+		http.Error(w, "Wallet API is available only on loopback", http.StatusForbidden)
+		return
+	}
+
+	if !this.rateLimitAllowed(req) {
+		http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+		return
+	}
+
 	var err error
 	var output interface{}
 
@@ -86,6 +110,16 @@ func (this *httpServerType) post(w http.ResponseWriter, req *http.Request) {
 		}
 	}()
 
+	if !this.walletAllowed(req) {
+		http.Error(w, "Wallet API is available only on loopback", http.StatusForbidden)
+		return
+	}
+
+	if !this.rateLimitAllowed(req) {
+		http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+		return
+	}
+
 	var err error
 	var output interface{}
 
@@ -123,6 +157,7 @@ func (this *httpServerType) GetHttpHandler() *http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/ws", websocks.Websockets.HandleUpgradeConnection)
+	mux.HandleFunc("/metrics", this.metrics)
 
 	for key, filepath := range network_config.STATIC_FILES {
 		fs := http.FileServer(http.Dir(filepath))
@@ -154,7 +189,7 @@ func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, me
 	apiWebsockets := api_websockets.NewWebsocketsAPI(apiStore, apiCommon, chain, settings, mempool)
 	api := api_http.NewAPI(apiStore, apiCommon, chain)
 
-	websocks.NewWebsockets(chain, mempool, settings, apiWebsockets.GetMap)
+	websocks.NewWebsockets(chain, mempool, wallet, settings, apiWebsockets.GetMap)
 
 	HttpServer = &httpServerType{
 		api,
@@ -168,5 +203,7 @@ func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, me
 		return err
 	}
 
+	initMetrics(chain, mempool)
+
 	return nil
 }
@@ -11,6 +11,8 @@ import (
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/network/known_nodes/known_node"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
+	"pandora-pay/network/rate_limiter"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/network/websocks/websock"
 	"sync"
@@ -30,10 +32,13 @@ var uuidGenerator uint32 //use atomic
 
 type AdvancedConnection struct {
 	Authenticated            *abool.AtomicBool
+	AuthScopes               *generics.Value[[]network_config_auth.AuthScope]
+	AuthName                 *generics.Value[string]
 	UUID                     advanced_connection_types.UUID
 	Conn                     *websock.Conn
 	Handshake                *ConnectionHandshake
 	Version                  *semver.Version
+	ClockOffset              int64 //milliseconds, the remote clock minus the local clock estimated during handshake
 	KnownNode                *known_node.KnownNodeScored
 	RemoteAddr               string
 	answerCounter            uint32
@@ -49,6 +54,7 @@ type AdvancedConnection struct {
 	ConnectionType           bool
 	onClosedConnection       func(c *AdvancedConnection)
 	onIncreaseKnownNodeScore func(knownNode *known_node.KnownNodeScored, delta int32, isServer bool) bool
+	onDecreaseKnownNodeScore func(knownNode *known_node.KnownNodeScored, delta int32, isServer bool)
 }
 
 func (c *AdvancedConnection) GetTimeout() time.Duration {
@@ -64,7 +70,7 @@ func (c *AdvancedConnection) Close() error {
 	return nil
 }
 
-func (c *AdvancedConnection) connSendMessage(message any, ctxDuration time.Duration) error {
+func (c *AdvancedConnection) connSendMessage(message *advanced_connection_types.AdvancedConnectionMessage, ctxDuration time.Duration) error {
 
 	data, err := msgpack.Marshal(message)
 	if err != nil {
@@ -79,7 +85,12 @@ func (c *AdvancedConnection) connSendMessage(message any, ctxDuration time.Durat
 	defer c.writeLock.Unlock()
 
 	c.Conn.SetWriteDeadline(time.Now().Add(generics.Max(ctxDuration, network_config.WEBSOCKETS_TIMEOUT)))
-	return c.Conn.WriteMessage(websock.BinaryMessage, data)
+	if err = c.Conn.WriteMessage(websock.BinaryMessage, data); err != nil {
+		return err
+	}
+
+	c.metricsSent(message, len(data))
+	return nil
 }
 
 func (c *AdvancedConnection) sendNow(replyBackId uint32, name []byte, data []byte, reply bool, ctxDuration time.Duration) error {
@@ -190,6 +201,10 @@ func (c *AdvancedConnection) get(message *advanced_connection_types.AdvancedConn
 
 	route := string(message.Name)
 	if callback := c.getMap[route]; callback != nil {
+		if !(network_config.RATE_LIMIT_PEER_EXEMPT_ROUTES[route] && c.IsPeerNode()) && !rate_limiter.RateLimiter.Allow(route, rate_limiter.GetClientKey(c.RemoteAddr, c.AuthName.Load())) {
+			c.penalizeRateLimit()
+			return nil, errors.New("Rate limit exceeded")
+		}
 		output, err = callback(c, message.Data)
 	} else {
 		err = errors.New("Unknown request")
@@ -212,6 +227,19 @@ func (c *AdvancedConnection) get(message *advanced_connection_types.AdvancedConn
 
 }
 
+// IsPeerNode returns true for the initialized connections of the nodes which advertised their URL in the handshake
+func (c *AdvancedConnection) IsPeerNode() bool {
+	c.InitializedStatusMutex.Lock()
+	defer c.InitializedStatusMutex.Unlock()
+	return c.InitializedStatus == INITIALIZED_STATUS_INITIALIZED && c.Handshake != nil && c.Handshake.URL != ""
+}
+
+func (c *AdvancedConnection) penalizeRateLimit() {
+	if c.KnownNode != nil {
+		c.onDecreaseKnownNodeScore(c.KnownNode, network_config.WEBSOCKETS_RATE_LIMIT_SCORE_PENALTY, c.ConnectionType)
+	}
+}
+
 func (c *AdvancedConnection) processRead(message *advanced_connection_types.AdvancedConnectionMessage) {
 
 	if !message.ReplyStatus {
@@ -270,6 +298,7 @@ func (c *AdvancedConnection) ReadPump() {
 		recovery.SafeGo(func() {
 			message := &advanced_connection_types.AdvancedConnectionMessage{}
 			if err = msgpack.Unmarshal(read, message); err == nil && message != nil {
+				c.metricsReceived(message, len(read))
 				c.processRead(message)
 			}
 		})
@@ -329,7 +358,7 @@ func (c *AdvancedConnection) IncreaseKnownNodeScore() {
 
 }
 
-func NewAdvancedConnection(conn *websock.Conn, remoteAddr string, knownNode *known_node.KnownNodeScored, getMap map[string]func(conn *AdvancedConnection, values []byte) (any, error), connectionType bool, newSubscriptionCn, removeSubscriptionCn chan<- *SubscriptionNotification, onClosedConnection func(*AdvancedConnection), onIncreaseKnownNodeScore func(*known_node.KnownNodeScored, int32, bool) bool) (*AdvancedConnection, error) {
+func NewAdvancedConnection(conn *websock.Conn, remoteAddr string, knownNode *known_node.KnownNodeScored, getMap map[string]func(conn *AdvancedConnection, values []byte) (any, error), connectionType bool, newSubscriptionCn, removeSubscriptionCn chan<- *SubscriptionNotification, onClosedConnection func(*AdvancedConnection), onIncreaseKnownNodeScore func(*known_node.KnownNodeScored, int32, bool) bool, onDecreaseKnownNodeScore func(*known_node.KnownNodeScored, int32, bool)) (*AdvancedConnection, error) {
 
 	//making sure u is not collided with UUID_ALL and UUID_SKIP_ALL
 	uuid := advanced_connection_types.UUID(atomic.AddUint32(&uuidGenerator, 1))
@@ -339,10 +368,13 @@ func NewAdvancedConnection(conn *websock.Conn, remoteAddr string, knownNode *kno
 
 	advancedConnection := &AdvancedConnection{
 		abool.New(),
+		&generics.Value[[]network_config_auth.AuthScope]{},
+		&generics.Value[string]{},
 		uuid,
 		conn,
 		nil,
 		nil,
+		0,
 		knownNode,
 		remoteAddr,
 		0,
@@ -358,6 +390,7 @@ func NewAdvancedConnection(conn *websock.Conn, remoteAddr string, knownNode *kno
 		connectionType,
 		onClosedConnection,
 		onIncreaseKnownNodeScore,
+		onDecreaseKnownNodeScore,
 	}
 	advancedConnection.Subscriptions = NewSubscriptions(advancedConnection, newSubscriptionCn, removeSubscriptionCn)
 	return advancedConnection, nil
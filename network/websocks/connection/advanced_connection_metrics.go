@@ -0,0 +1,40 @@
+package connection
+
+import (
+	"pandora-pay/helpers/metrics"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+)
+
+var (
+	metricsMessagesReceived = metrics.NewCounterVec("websocket_messages_received_total", "Websocket messages received by route", "route")
+	metricsBytesReceived    = metrics.NewCounterVec("websocket_bytes_received_total", "Websocket bytes received by route", "route")
+	metricsMessagesSent     = metrics.NewCounterVec("websocket_messages_sent_total", "Websocket messages sent by route", "route")
+	metricsBytesSent        = metrics.NewCounterVec("websocket_bytes_sent_total", "Websocket bytes sent by route", "route")
+)
+
+// getMetricsRoute avoids creating labels for the unknown routes received from the peers
+func (c *AdvancedConnection) getMetricsRoute(message *advanced_connection_types.AdvancedConnectionMessage) string {
+	if message.ReplyStatus {
+		return "reply"
+	}
+	route := string(message.Name)
+	if c.getMap[route] == nil {
+		return "unknown"
+	}
+	return route
+}
+
+func (c *AdvancedConnection) metricsReceived(message *advanced_connection_types.AdvancedConnectionMessage, size int) {
+	route := c.getMetricsRoute(message)
+	metricsMessagesReceived.WithLabel(route).Inc()
+	metricsBytesReceived.WithLabel(route).Add(uint64(size))
+}
+
+func (c *AdvancedConnection) metricsSent(message *advanced_connection_types.AdvancedConnectionMessage, size int) {
+	route := "reply"
+	if !message.ReplyStatus {
+		route = string(message.Name)
+	}
+	metricsMessagesSent.WithLabel(route).Inc()
+	metricsBytesSent.WithLabel(route).Add(uint64(size))
+}
@@ -12,6 +12,7 @@ type ConnectionHandshake struct {
 	Network   uint64                   `json:"network" msgpack:"network"`
 	Consensus config.NodeConsensusType `json:"consensus" msgpack:"consensus"`
 	URL       string                   `json:"url" msgpack:"url"`
+	Timestamp int64                    `json:"timestamp" msgpack:"timestamp"` //unix milliseconds, used to detect the clock drift
 }
 
 func (handshake *ConnectionHandshake) ValidateHandshake() (*semver.Version, error) {
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"errors"
 	"golang.org/x/exp/slices"
+	"pandora-pay/addresses"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
 	"pandora-pay/network/api_code/api_code_types"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
 	"sync"
 )
 
@@ -23,12 +25,14 @@ type Subscriptions struct {
 func checkSubscriptionLength(key []byte, subscriptionType api_code_types.SubscriptionType) error {
 	var length int
 	switch subscriptionType {
-	case api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT, api_code_types.SUBSCRIPTION_ACCOUNT, api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, api_code_types.SUBSCRIPTION_REGISTRATION:
+	case api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT, api_code_types.SUBSCRIPTION_ACCOUNT, api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, api_code_types.SUBSCRIPTION_REGISTRATION, api_code_types.SUBSCRIPTION_PENDING_STAKE:
 		length = cryptography.PublicKeySize
 	case api_code_types.SUBSCRIPTION_ASSET:
 		length = config_coins.ASSET_LENGTH
 	case api_code_types.SUBSCRIPTION_TRANSACTION:
 		length = cryptography.HashSize
+	case api_code_types.SUBSCRIPTION_WALLET_INVOICE:
+		length = addresses.PAYMENT_ID_LENGTH
 	}
 	if len(key) != length {
 		return errors.New("Key is invalid")
@@ -42,6 +46,16 @@ func (s *Subscriptions) AddSubscription(subscriptionType api_code_types.Subscrip
 		return errors.New("These subscriptions are automatically. They can't be subsribed manually")
 	}
 
+	if subscriptionType == api_code_types.SUBSCRIPTION_WALLET_INVOICE {
+		//the invoices are part of the wallet API
+		if !network_config.API_WALLET_PUBLIC && !network_config.IsLocalAddress(s.conn.RemoteAddr) {
+			return errors.New("Wallet API is available only on loopback")
+		}
+		if !(s.conn.Authenticated.IsSet() && network_config_auth.HasScope(s.conn.AuthScopes.Load(), network_config_auth.SCOPE_WALLET)) {
+			return errors.New("Invalid User or Password")
+		}
+	}
+
 	if err := checkSubscriptionLength(key, subscriptionType); err != nil {
 		return err
 	}
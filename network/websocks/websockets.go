@@ -23,6 +23,7 @@ import (
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/network/websocks/websock"
 	"pandora-pay/settings"
+	"pandora-pay/wallet"
 	"strconv"
 	"sync/atomic"
 	"time"
@@ -174,9 +175,13 @@ func (this *websocketsType) increaseScoreKnownNode(knownNode *known_node.KnownNo
 	return known_nodes.KnownNodes.IncreaseKnownNodeScore(knownNode, delta, isServer)
 }
 
+func (this *websocketsType) decreaseScoreKnownNode(knownNode *known_node.KnownNodeScored, delta int32, isServer bool) {
+	known_nodes.KnownNodes.DecreaseKnownNodeScore(knownNode, delta, isServer)
+}
+
 func (this *websocketsType) NewConnection(c *websock.Conn, remoteAddr string, knownNode *known_node.KnownNodeScored, connectionType bool) (*connection.AdvancedConnection, error) {
 
-	conn, err := connection.NewAdvancedConnection(c, remoteAddr, knownNode, this.apiGetMap, connectionType, this.subscriptions.newSubscriptionCn, this.subscriptions.removeSubscriptionCn, this.closedConnection, this.increaseScoreKnownNode)
+	conn, err := connection.NewAdvancedConnection(c, remoteAddr, knownNode, this.apiGetMap, connectionType, this.subscriptions.newSubscriptionCn, this.subscriptions.removeSubscriptionCn, this.closedConnection, this.increaseScoreKnownNode, this.decreaseScoreKnownNode)
 	if err != nil {
 		return nil, err
 	}
@@ -208,7 +213,9 @@ func (this *websocketsType) InitializeConnection(conn *connection.AdvancedConnec
 		}
 	}()
 
+	sentTimestamp := time.Now().UnixMilli()
 	out := conn.SendAwaitAnswer([]byte("handshake"), nil, nil, 0)
+	receivedTimestamp := time.Now().UnixMilli()
 
 	if out.Err != nil {
 		return errors.New("Error sending handshake")
@@ -233,6 +240,9 @@ func (this *websocketsType) InitializeConnection(conn *connection.AdvancedConnec
 
 	conn.Handshake = handshakeReceived
 	conn.Version = version
+	if handshakeReceived.Timestamp != 0 {
+		conn.ClockOffset = handshakeReceived.Timestamp - (sentTimestamp+receivedTimestamp)/2
+	}
 
 	if conn.IsClosed.IsSet() {
 		return
@@ -257,7 +267,7 @@ func (this *websocketsType) InitializeConnection(conn *connection.AdvancedConnec
 	return nil
 }
 
-func NewWebsockets(chain *blockchain.Blockchain, mempool *mempool.Mempool, settings *settings.Settings, apiGetMap map[string]func(conn *connection.AdvancedConnection, values []byte) (any, error)) *websocketsType {
+func NewWebsockets(chain *blockchain.Blockchain, mempool *mempool.Mempool, wallet *wallet.Wallet, settings *settings.Settings, apiGetMap map[string]func(conn *connection.AdvancedConnection, values []byte) (any, error)) *websocketsType {
 
 	Websockets = &websocketsType{
 		apiGetMap,
@@ -270,7 +280,7 @@ func NewWebsockets(chain *blockchain.Blockchain, mempool *mempool.Mempool, setti
 	}
 
 	Websockets.ReadyCn.Store(make(chan struct{}))
-	Websockets.subscriptions = newWebsocketSubscriptions(chain, mempool)
+	Websockets.subscriptions = newWebsocketSubscriptions(chain, mempool, wallet)
 
 	recovery.SafeGo(func() {
 		for {
@@ -279,5 +289,7 @@ func NewWebsockets(chain *blockchain.Blockchain, mempool *mempool.Mempool, setti
 		}
 	})
 
+	recovery.SafeGo(Websockets.processClockDrift)
+
 	return Websockets
 }
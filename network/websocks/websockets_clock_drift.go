@@ -0,0 +1,52 @@
+package websocks
+
+import (
+	"fmt"
+	"pandora-pay/config"
+	"pandora-pay/gui"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// GetClockDrift returns the median of the clock offsets (milliseconds) measured during the handshakes and the number of peers used
+func (this *websocketsType) GetClockDrift() (int64, int) {
+
+	offsets := make([]int64, 0)
+	for _, conn := range this.GetAllSockets() {
+		if conn.Handshake != nil && conn.Handshake.Timestamp != 0 {
+			offsets = append(offsets, conn.ClockOffset)
+		}
+	}
+
+	if len(offsets) == 0 {
+		return 0, 0
+	}
+
+	sort.Slice(offsets, func(i, j int) bool {
+		return offsets[i] < offsets[j]
+	})
+
+	return offsets[len(offsets)/2], len(offsets)
+}
+
+func (this *websocketsType) processClockDrift() {
+
+	warned := false
+	for {
+
+		drift, count := this.GetClockDrift()
+		if count > 0 {
+
+			gui.GUI.InfoUpdate("Clock Drift", strconv.FormatInt(drift, 10)+"ms")
+
+			tooLarge := drift > config.NETWORK_CLOCK_DRIFT_WARNING_MS || drift < -config.NETWORK_CLOCK_DRIFT_WARNING_MS
+			if tooLarge && !warned {
+				gui.GUI.Warning(fmt.Sprintf("Local clock drifts %d ms from the median of %d peers. Synchronize your clock, otherwise your blocks may be rejected", -drift, count))
+			}
+			warned = tooLarge
+		}
+
+		time.Sleep(10 * time.Second)
+	}
+}
@@ -11,11 +11,13 @@ import (
 	"pandora-pay/network/network_config"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/wallet"
 )
 
 type WebsocketSubscriptions struct {
 	chain                             *blockchain.Blockchain
 	mempool                           *mempool.Mempool
+	wallet                            *wallet.Wallet
 	websocketClosedCn                 chan *connection.AdvancedConnection
 	newSubscriptionCn                 chan *connection.SubscriptionNotification
 	removeSubscriptionCn              chan *connection.SubscriptionNotification
@@ -23,18 +25,22 @@ type WebsocketSubscriptions struct {
 	accountsTransactionsSubscriptions map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 	assetsSubscriptions               map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 	transactionsSubscriptions         map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	invoicesSubscriptions             map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	pendingStakesSubscriptions        map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 }
 
-func newWebsocketSubscriptions(chain *blockchain.Blockchain, mempool *mempool.Mempool) (subs *WebsocketSubscriptions) {
+func newWebsocketSubscriptions(chain *blockchain.Blockchain, mempool *mempool.Mempool, wallet *wallet.Wallet) (subs *WebsocketSubscriptions) {
 
 	subs = &WebsocketSubscriptions{
-		chain, mempool, make(chan *connection.AdvancedConnection),
+		chain, mempool, wallet, make(chan *connection.AdvancedConnection),
 		make(chan *connection.SubscriptionNotification),
 		make(chan *connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 	}
 
 	if network_config.NETWORK_ENABLE_SUBSCRIPTIONS {
@@ -103,6 +109,10 @@ func (this *WebsocketSubscriptions) getSubsMap(subscriptionType api_code_types.S
 		subsMap = this.assetsSubscriptions
 	case api_code_types.SUBSCRIPTION_TRANSACTION:
 		subsMap = this.transactionsSubscriptions
+	case api_code_types.SUBSCRIPTION_WALLET_INVOICE:
+		subsMap = this.invoicesSubscriptions
+	case api_code_types.SUBSCRIPTION_PENDING_STAKE:
+		subsMap = this.pendingStakesSubscriptions
 	}
 	return
 }
@@ -136,6 +146,12 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 	updateMempoolTransactionsCn := this.mempool.Txs.UpdateMempoolTransactions.AddListener()
 	defer this.mempool.Txs.UpdateMempoolTransactions.RemoveChannel(updateMempoolTransactionsCn)
 
+	var updateInvoicesCn chan *wallet.WalletInvoice
+	if this.wallet != nil {
+		updateInvoicesCn = this.wallet.UpdateInvoices.AddListener()
+		defer this.wallet.UpdateInvoices.RemoveChannel(updateInvoicesCn)
+	}
+
 	var subsMap map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 
 	for {
@@ -230,6 +246,16 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 				}
 			}
 
+			for _, pendingStakes := range dataStorage.MaturedPendingStakes {
+				for _, pending := range pendingStakes.Pending {
+					if list := this.pendingStakesSubscriptions[string(pending.PublicKey)]; list != nil {
+						this.send(api_code_types.SUBSCRIPTION_PENDING_STAKE, []byte("sub/notify"), pending.PublicKey, list, pending, nil, &api_types.APISubscriptionNotificationPendingStakeExtra{
+							pendingStakes.Height,
+						})
+					}
+				}
+			}
+
 		case txsUpdates, ok := <-updateTransactionsCn:
 			if !ok {
 				return
@@ -274,6 +300,19 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 				})
 			}
 
+		case invoice, ok := <-updateInvoicesCn:
+			if !ok {
+				return
+			}
+
+			if list := this.invoicesSubscriptions[string(invoice.PaymentID)]; list != nil {
+				bytes, err := msgpack.Marshal(invoice)
+				if err != nil {
+					panic(err)
+				}
+				this.send(api_code_types.SUBSCRIPTION_WALLET_INVOICE, []byte("sub/notify"), invoice.PaymentID, list, nil, bytes, nil)
+			}
+
 		case conn, ok := <-this.websocketClosedCn:
 			if !ok {
 				return
@@ -283,6 +322,8 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 			this.removeConnection(conn, api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS)
 			this.removeConnection(conn, api_code_types.SUBSCRIPTION_ASSET)
 			this.removeConnection(conn, api_code_types.SUBSCRIPTION_TRANSACTION)
+			this.removeConnection(conn, api_code_types.SUBSCRIPTION_WALLET_INVOICE)
+			this.removeConnection(conn, api_code_types.SUBSCRIPTION_PENDING_STAKE)
 
 		}
 
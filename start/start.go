@@ -22,6 +22,7 @@ import (
 	"pandora-pay/mempool"
 	"pandora-pay/network"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
 	"pandora-pay/settings"
 	"pandora-pay/store"
 	"pandora-pay/testnet"
@@ -114,7 +115,7 @@ func StartMainNow() (err error) {
 		}()
 	}
 
-	app.Wallet.InitializeWallet(app.Chain.UpdateNewChainUpdate)
+	app.Wallet.InitializeWallet(app.Chain.UpdateNewChainUpdate, app.Chain.UpdateSocketsSubscriptionsTransactions)
 	if err = app.Wallet.StartWallet(); err != nil {
 		return
 	}
@@ -124,6 +125,10 @@ func StartMainNow() (err error) {
 	}
 	globals.MainEvents.BroadcastEvent("main", "settings initialized")
 
+	if err = network_config_auth.InitAuthTokens(); err != nil {
+		return
+	}
+
 	if err = txs_builder.TxsBuilderInit(app.Wallet, app.Mempool); err != nil {
 		return
 	}
@@ -98,3 +98,30 @@ func (hashMap *HashMap[T]) ReadTransitionalChangesFromStore(prefix string) error
 
 	return nil
 }
+
+// GetTransitionalChange returns the element as it was before the changes stored with the prefix. found is false when the key was not changed
+func (hashMap *HashMap[T]) GetTransitionalChange(prefix, key string) (out T, found bool, err error) {
+
+	data := hashMap.Tx.Get(hashMap.name + ":transitions:" + prefix)
+	if data == nil {
+		return
+	}
+
+	changes := &transactionChanges{}
+	if err = msgpack.Unmarshal(data, changes); err != nil {
+		return
+	}
+
+	for _, change := range changes.List {
+		if string(change.Key) == key {
+			if change.Transition != nil {
+				if out, err = hashMap.deserialize(change.Key, change.Transition, 0); err != nil {
+					return
+				}
+			}
+			return out, true, nil
+		}
+	}
+
+	return
+}
@@ -12,10 +12,21 @@ type Store struct {
 
 var StoreBlockchain, StoreWallet, StoreSettings, StoreMempool, StoreBalancesDecrypted *Store
 
+var walletStoreType string
+
 func (store *Store) close() error {
 	return store.DB.Close()
 }
 
+func (store *Store) Close() error {
+	return store.close()
+}
+
+// CreateWalletStore opens the store of a named wallet. It uses the same type as the default wallet
+func CreateWalletStore(name string) (*Store, error) {
+	return createStoreNow("/wallet_"+name, walletStoreType)
+}
+
 func createStore(name string, db store_db_interface.StoreDBInterface) (*Store, error) {
 
 	store := &Store{
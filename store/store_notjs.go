@@ -51,7 +51,8 @@ func create_db() (err error) {
 	if StoreBlockchain, err = createStoreNow(prefix+"/blockchain", getStoreType(arguments.Arguments["--store-chain-type"].(string), allowedStores)); err != nil {
 		return
 	}
-	if StoreWallet, err = createStoreNow(prefix+"/wallet", getStoreType(arguments.Arguments["--store-wallet-type"].(string), allowedStores)); err != nil {
+	walletStoreType = getStoreType(arguments.Arguments["--store-wallet-type"].(string), allowedStores)
+	if StoreWallet, err = createStoreNow(prefix+"/wallet", walletStoreType); err != nil {
 		return
 	}
 	if StoreSettings, err = createStoreNow(prefix+"/settings", getStoreType(arguments.Arguments["--store-wallet-type"].(string), allowedStores)); err != nil {
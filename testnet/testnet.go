@@ -41,7 +41,7 @@ var Testnet *TestnetType
 
 func (testnet *TestnetType) testnetGetZetherRingConfiguration(payload *txs_builder.TxBuilderCreateZetherTxPayload) *txs_builder.TxBuilderCreateZetherTxPayload {
 	payload.RingSize = -1
-	payload.RingConfiguration = &txs_builder.ZetherRingConfiguration{&txs_builder.ZetherSenderRingType{false, false, []string{}, 0}, &txs_builder.ZetherRecipientRingType{false, false, nil, -1}}
+	payload.RingConfiguration = &txs_builder.ZetherRingConfiguration{&txs_builder.ZetherSenderRingType{false, false, []string{}, 0}, &txs_builder.ZetherRecipientRingType{false, false, nil, -1}, nil}
 	if config.LIGHT_COMPUTATIONS {
 		payload.RingSize = int(math.Pow(2, float64(rand.Intn(2)+3)))
 	}
@@ -78,7 +78,7 @@ func (testnet *TestnetType) testnetCreateTransfersNewWallets(blockHeight uint64,
 		}))
 	}
 
-	if tx, err = txs_builder.TxsBuilder.CreateZetherTx(txData, nil, true, true, true, false, ctx, func(string) {}); err != nil {
+	if tx, err = txs_builder.TxsBuilder.CreateZetherTx(testnet.wallet, txData, nil, true, true, true, false, ctx, func(string) {}); err != nil {
 		return nil, err
 	}
 
@@ -108,7 +108,7 @@ func (testnet *TestnetType) testnetCreateClaimTx(senderAddr *wallet_address.Wall
 		})},
 	}
 
-	if tx, err = txs_builder.TxsBuilder.CreateZetherTx(txData, nil, true, true, true, false, ctx, func(string) {}); err != nil {
+	if tx, err = txs_builder.TxsBuilder.CreateZetherTx(testnet.wallet, txData, nil, true, true, true, false, ctx, func(string) {}); err != nil {
 		return nil, err
 	}
 
@@ -151,7 +151,7 @@ func (testnet *TestnetType) testnetCreateTransfers(senderAddr *wallet_address.Wa
 			})},
 	}
 
-	if tx, err = txs_builder.TxsBuilder.CreateZetherTx(txData, nil, true, true, true, false, ctx, func(string) {}); err != nil {
+	if tx, err = txs_builder.TxsBuilder.CreateZetherTx(testnet.wallet, txData, nil, true, true, true, false, ctx, func(string) {}); err != nil {
 		return nil, err
 	}
 
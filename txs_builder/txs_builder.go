@@ -56,12 +56,15 @@ func (builder *TxsBuilderType) getWalletAddresses(senders []string) ([]*wallet_a
 	var err error
 
 	for i, senderAddress := range senders {
-		if sendersWalletAddress[i], err = builder.wallet.GetWalletAddressByEncodedAddress(senderAddress, true); err != nil {
+		if sendersWalletAddress[i], err = builder.wallet.GetActiveWallet().GetWalletAddressByEncodedAddress(senderAddress, true); err != nil {
 			return nil, err
 		}
 		if sendersWalletAddress[i].PrivateKey == nil {
 			return nil, fmt.Errorf("Can't be used for transactions as the private key is missing for sender %s", senderAddress)
 		}
+		if sendersWalletAddress[i].WatchOnly {
+			return nil, fmt.Errorf("Address %s is watch-only and can't spend", senderAddress)
+		}
 	}
 
 	return sendersWalletAddress, nil
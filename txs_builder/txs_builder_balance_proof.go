@@ -0,0 +1,225 @@
+package txs_builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/cryptography/crypto/balance_proof"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/wallet"
+	"pandora-pay/wallet/wallet_address"
+	"strconv"
+)
+
+// BALANCE_PROOF_MAX_AGE limits the number of blocks reverted to find the balance of the account at the height of the proof
+const BALANCE_PROOF_MAX_AGE = uint64(10000)
+
+type TxBuilderBalanceProofRow struct {
+	Address    string `json:"address" msgpack:"address"`
+	MinBalance uint64 `json:"minBalance" msgpack:"minBalance"`
+}
+
+type TxBuilderBalanceProofResult struct {
+	Address string `json:"address" msgpack:"address"`
+	Proof   string `json:"proof,omitempty" msgpack:"proof,omitempty"`
+	Error   string `json:"error,omitempty" msgpack:"error,omitempty"`
+}
+
+type BalanceProofOutput struct {
+	Address    string         `json:"address" msgpack:"address"`
+	Asset      helpers.Base64 `json:"asset" msgpack:"asset"`
+	Height     uint64         `json:"height" msgpack:"height"`
+	BlockHash  helpers.Base64 `json:"blockHash" msgpack:"blockHash"`
+	MinBalance uint64         `json:"minBalance" msgpack:"minBalance"`
+	Challenge  helpers.Base64 `json:"challenge" msgpack:"challenge"`
+}
+
+func getBalanceProofAccount(reader store_db_interface.StoreDBTransactionInterface, publicKey, asset []byte) (*account.Account, error) {
+
+	accs, err := data_storage.NewDataStorage(reader).AccsCollection.GetMap(asset)
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := accs.Get(string(publicKey))
+	if err != nil {
+		return nil, err
+	}
+	if acc == nil {
+		return nil, errors.New("Account has no balance for the asset")
+	}
+
+	return acc, nil
+}
+
+// getBalanceProofAccountAt returns the account as it was after the block at the height.
+// The current account is reverted using the transitional changes stored for the next blocks
+func getBalanceProofAccountAt(reader store_db_interface.StoreDBTransactionInterface, publicKey, asset []byte, height, chainHeight uint64) (*account.Account, error) {
+
+	if height >= chainHeight {
+		return nil, errors.New("Height is invalid")
+	}
+	if chainHeight-height > BALANCE_PROOF_MAX_AGE {
+		return nil, errors.New("Proof is too old")
+	}
+
+	accs, err := data_storage.NewDataStorage(reader).AccsCollection.GetMap(asset)
+	if err != nil {
+		return nil, err
+	}
+
+	for blockHeight := height + 1; blockHeight < chainHeight; blockHeight++ {
+		acc, found, err := accs.GetTransitionalChange(strconv.FormatUint(blockHeight, 10), string(publicKey))
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			if acc == nil {
+				return nil, errors.New("Account has no balance for the asset")
+			}
+			return acc, nil
+		}
+	}
+
+	return getBalanceProofAccount(reader, publicKey, asset)
+}
+
+// CreateBalanceProofs attests the balances of the wallet addresses at the last block. Every row is proven separately and the errors are reported per row
+func (builder *TxsBuilderType) CreateBalanceProofs(w *wallet.Wallet, rows []*TxBuilderBalanceProofRow, asset, challenge []byte, ctx context.Context, statusCallback func(string)) ([]*TxBuilderBalanceProofResult, error) {
+
+	if len(rows) == 0 {
+		return nil, errors.New("No addresses to prove")
+	}
+	if len(challenge) == 0 {
+		return nil, errors.New("Challenge is missing")
+	}
+	if len(challenge) > balance_proof.BALANCE_PROOF_CHALLENGE_MAX_LENGTH {
+		return nil, errors.New("Challenge is too long")
+	}
+
+	results := make([]*TxBuilderBalanceProofResult, len(rows))
+	addrs := make([]*wallet_address.WalletAddress, len(rows))
+	balances := make([][]byte, len(rows))
+
+	for i, row := range rows {
+		results[i] = &TxBuilderBalanceProofResult{Address: row.Address}
+		addr, err := w.GetWalletAddressByEncodedAddress(row.Address, true)
+		if err == nil && addr.PrivateKey == nil {
+			err = errors.New("Private key is missing")
+		}
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		addrs[i] = addr
+	}
+
+	var height uint64
+	var blockHash []byte
+
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		chainHeight, _ := binary.Uvarint(reader.Get("chainHeight"))
+		if chainHeight == 0 {
+			return errors.New("Blockchain is empty")
+		}
+		height = chainHeight - 1
+		blockHash = reader.Get("chainHash")
+
+		for i, addr := range addrs {
+			if addr == nil {
+				continue
+			}
+			var acc *account.Account
+			if acc, err = getBalanceProofAccount(reader, addr.PublicKey, asset); err != nil {
+				results[i].Error = err.Error()
+				addrs[i] = nil
+				err = nil
+				continue
+			}
+			balances[i] = acc.Balance.Amount.Serialize()
+		}
+
+		return
+	}); err != nil {
+		return nil, err
+	}
+
+	for i, addr := range addrs {
+		if addr == nil {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		statusCallback(fmt.Sprintf("Proving balance %d / %d", i+1, len(rows)))
+
+		balance, err := builder.wallet.DecryptBalance(addr, balances[i], asset, false, 0, true, ctx, statusCallback)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		proof, err := balance_proof.CreateBalanceProof(addr.PrivateKey.Key, asset, height, blockHash, balances[i], balance, rows[i].MinBalance, challenge)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		results[i].Proof = proof.Encode()
+	}
+
+	return results, nil
+}
+
+// VerifyBalanceProof checks the proof and that the block and the encrypted balance are the ones of the blockchain at the height of the proof
+func VerifyBalanceProof(proof *balance_proof.BalanceProof) (*BalanceProofOutput, error) {
+
+	if err := proof.Verify(); err != nil {
+		return nil, err
+	}
+
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		if !bytes.Equal(reader.Get("blockHash_ByHeight"+strconv.FormatUint(proof.Height, 10)), proof.BlockHash) {
+			return errors.New("Block hash is not matching the blockchain")
+		}
+
+		chainHeight, _ := binary.Uvarint(reader.Get("chainHeight"))
+
+		acc, err := getBalanceProofAccountAt(reader, proof.PublicKey, proof.Asset, proof.Height, chainHeight)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(acc.Balance.Amount.Serialize(), proof.Balance) {
+			return errors.New("Account balance is not matching the blockchain")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	addr, err := addresses.CreateAddr(proof.PublicKey, false, nil, nil, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BalanceProofOutput{
+		addr.EncodeAddr(),
+		proof.Asset,
+		proof.Height,
+		proof.BlockHash,
+		proof.MinBalance,
+		proof.Challenge,
+	}, nil
+}
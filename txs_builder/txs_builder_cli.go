@@ -8,22 +8,29 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"pandora-pay/addresses"
 	"pandora-pay/blockchain/data_storage/assets"
 	"pandora-pay/blockchain/data_storage/assets/asset"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
+	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
 	"pandora-pay/config/config_assets"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto/balance_proof"
 	"pandora-pay/gui"
 	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/helpers/files"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
 	"pandora-pay/txs_builder/wizard"
+	"pandora-pay/wallet/wallet_address"
+	"strconv"
+	"strings"
 )
 
 func (builder *TxsBuilderType) showWarningIfNotSyncCLI() {
@@ -87,9 +94,9 @@ func (builder *TxsBuilderType) readAddress(text string, leaveEmpty bool) (addres
 
 func (builder *TxsBuilderType) readAddressOptional(text string, assetId []byte, allowRandomAddress bool) (address *addresses.Address, addressEncoded string, amount uint64, err error) {
 
-	text2 := text
+	text2 := text + " or Contact name"
 	if allowRandomAddress {
-		text2 = text + ". Leave empty for none"
+		text2 = text2 + ". Leave empty for none"
 	}
 
 	for {
@@ -98,6 +105,7 @@ func (builder *TxsBuilderType) readAddressOptional(text string, assetId []byte,
 			return
 		}
 
+		str, _ = builder.wallet.GetActiveWallet().ResolveContact(str)
 		if address, err = addresses.DecodeAddr(str); err != nil {
 			gui.GUI.OutputWrite("Invalid Address")
 			continue
@@ -119,6 +127,7 @@ func (builder *TxsBuilderType) readZetherRingConfiguration(payload *TxBuilderCre
 	payload.RingConfiguration = &ZetherRingConfiguration{
 		&ZetherSenderRingType{},
 		&ZetherRecipientRingType{},
+		nil,
 	}
 
 	payload.RingSize = gui.GUI.OutputReadInt("Ring Size (2,4,8,16,32,64,128,256). Leave empty for random", true, -1, func(value int) bool {
@@ -134,6 +143,10 @@ func (builder *TxsBuilderType) readZetherRingConfiguration(payload *TxBuilderCre
 		return value >= 0
 	})
 
+	if gui.GUI.OutputReadBool("Prefer recently active accounts as decoys? y/n. Leave empty for no", true, false) {
+		payload.RingConfiguration.Strategy = &ZetherRingStrategyConfiguration{ZETHER_RING_STRATEGY_RECENT_ACTIVITY, 0, true, true}
+	}
+
 }
 
 func (builder *TxsBuilderType) readFee(assetId []byte) (fee *wizard.WizardTransactionFee) {
@@ -195,7 +208,8 @@ func (builder *TxsBuilderType) initCLI() {
 			Payloads: []*TxBuilderCreateZetherTxPayload{{}},
 		}
 
-		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address to Transfer", ctx); err != nil {
+		var senderAddr *wallet_address.WalletAddress
+		if senderAddr, txData.Payloads[0].Sender, _, err = builder.wallet.GetActiveWallet().CliSelectAddress("Select Address to Transfer", ctx); err != nil {
 			return
 		}
 
@@ -210,7 +224,11 @@ func (builder *TxsBuilderType) initCLI() {
 		txData.Payloads[0].Fee = builder.readZetherFee(txData.Payloads[0].Asset)
 		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
 
-		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+		if senderAddr.RemoteSpendSigner != nil {
+			txData.SpendConfirmationCode = gui.GUI.OutputReadString("Co-signer confirmation code. Leave empty for none")
+		}
+
+		tx, err := builder.CreateZetherTx(builder.wallet.GetActiveWallet(), txData, nil, propagate, true, true, false, ctx, func(status string) {
 			gui.GUI.OutputWrite(status)
 		})
 		if err != nil {
@@ -221,6 +239,184 @@ func (builder *TxsBuilderType) initCLI() {
 		return
 	}
 
+	cliPrivateBatchTransfer := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		_, sender, _, err := builder.wallet.GetActiveWallet().CliSelectAddress("Select Address to pay from", ctx)
+		if err != nil {
+			return
+		}
+
+		filename := gui.GUI.OutputReadFilename("Path to the payouts file. Columns: recipient, amount, asset, message", "csv", false)
+		rows, err := ReadBatchTransferFile(filename)
+		if err != nil {
+			return
+		}
+
+		ringSize := gui.GUI.OutputReadInt("Ring Size (2,4,8,16,32,64,128,256). Leave empty for 32", true, 32, func(value int) bool {
+			switch value {
+			case 2, 4, 8, 16, 32, 64, 128, 256:
+				return true
+			default:
+				return false
+			}
+		})
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		results, txs, err := builder.CreateZetherBatchTx(builder.wallet.GetActiveWallet(), sender, rows, ringSize, propagate, true, true, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+
+		for _, result := range results {
+			if result.Error != "" {
+				gui.GUI.OutputWrite(fmt.Sprintf("%4d %s NOT PAID %s", result.Row, result.Recipient, result.Error))
+			} else {
+				gui.GUI.OutputWrite(fmt.Sprintf("%4d %s %s", result.Row, result.Recipient, base64.StdEncoding.EncodeToString(result.TxId)))
+			}
+		}
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("%d rows paid in %d txs %s", len(rows), len(txs), cmd))
+		return
+	}
+
+	cliPrivateTransferPrepareOffline := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: []*TxBuilderCreateZetherTxPayload{{}},
+		}
+
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.GetActiveWallet().CliSelectAddress("Select Address to Transfer", ctx); err != nil {
+			return
+		}
+
+		txData.Payloads[0].Asset = builder.readAsset("Asset. Leave empty for Native Asset", true)
+
+		if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Recipient Address", txData.Payloads[0].Asset, false); err != nil {
+			return
+		}
+
+		builder.readZetherRingConfiguration(txData.Payloads[0])
+		txData.Payloads[0].Data = builder.readData()
+		txData.Payloads[0].Fee = builder.readZetherFee(txData.Payloads[0].Asset)
+		filename := gui.GUI.OutputReadFilename("Path to export the context", "pandoracontext", false)
+
+		txContext, err := builder.PrepareZetherTxContext(builder.wallet.GetActiveWallet(), txData, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		data, err := txContext.Serialize()
+		if err != nil {
+			return
+		}
+
+		if err = files.WriteFile(filename, base64.StdEncoding.EncodeToString(data)); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Context prepared at height %d and exported to: %s", txContext.ChainHeight, filename))
+		return
+	}
+
+	cliSignOffline := func(cmd string, ctx context.Context) (err error) {
+
+		filename := gui.GUI.OutputReadFilename("Path to import the context", "pandoracontext", false)
+
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return
+		}
+		if data, err = base64.StdEncoding.DecodeString(strings.TrimSpace(string(data))); err != nil {
+			return
+		}
+
+		txContext, err := DeserializeZetherTxContext(data)
+		if err != nil {
+			return
+		}
+		if err = txContext.validate(); err != nil {
+			return
+		}
+
+		//the offline node may not know the assets, hence only the native amounts are converted
+		formatAmount := func(ast []byte, amount uint64) string {
+			if bytes.Equal(ast, config_coins.NATIVE_ASSET_FULL) {
+				return strconv.FormatFloat(config_coins.ConvertToBase(amount), 'f', config_coins.DECIMAL_SEPARATOR, 64)
+			}
+			return strconv.FormatUint(amount, 10) + " units"
+		}
+
+		//the context was created on an online node, hence the transfers must be reviewed before signing them
+		for t, transfer := range txContext.Transfers {
+			gui.GUI.OutputWrite(fmt.Sprintf("Transfer %d", t))
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Sender", txContext.Senders[t]))
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Recipient", transfer.Recipient))
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Asset", base64.StdEncoding.EncodeToString(transfer.Asset)))
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Amount", formatAmount(transfer.Asset, transfer.Amount)))
+			if transfer.Burn > 0 {
+				gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Burn", formatAmount(transfer.Asset, transfer.Burn)))
+			}
+			fee := txContext.Fees[t]
+			if fee.PerByteAuto {
+				gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Fee", "computed automatically per byte"))
+			} else if fee.Fixed > 0 {
+				gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Fee", formatAmount(config_coins.NATIVE_ASSET_FULL, fee.Fixed)))
+			} else {
+				gui.GUI.OutputWrite(fmt.Sprintf("%18s: %d per byte and %d per extra space byte", "Fee", fee.PerByte, fee.PerByteExtraSpace))
+			}
+		}
+
+		if !gui.GUI.OutputReadBool("Sign the transfers above? y/n", false, false) {
+			return errors.New("Signing was cancelled")
+		}
+
+		tx, err := builder.SignZetherTxContext(builder.wallet.GetActiveWallet(), txContext, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		filename = gui.GUI.OutputReadFilename("Path to export the signed transaction", "pandoratx", false)
+		if err = files.WriteFile(filename, base64.StdEncoding.EncodeToString(tx.Bloom.Serialized)); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx signed: %s exported to: %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), filename))
+		return
+	}
+
+	cliBroadcastOffline := func(cmd string, ctx context.Context) (err error) {
+
+		filename := gui.GUI.OutputReadFilename("Path to import the signed transaction", "pandoratx", false)
+
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return
+		}
+		if data, err = base64.StdEncoding.DecodeString(strings.TrimSpace(string(data))); err != nil {
+			return
+		}
+
+		tx := &transaction.Transaction{}
+		if err = tx.Deserialize(advanced_buffers.NewBufferReader(data)); err != nil {
+			return
+		}
+
+		if err = builder.BroadcastZetherTx(tx, true, true, ctx); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx broadcasted: %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash)))
+		return
+	}
+
 	cliPrivateAssetCreate := func(cmd string, ctx context.Context) (err error) {
 		builder.showWarningIfNotSyncCLI()
 
@@ -232,7 +428,7 @@ func (builder *TxsBuilderType) initCLI() {
 			}},
 		}
 
-		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will create the asset", ctx); err != nil {
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.GetActiveWallet().CliSelectAddress("Select Address which will create the asset", ctx); err != nil {
 			return
 		}
 
@@ -268,7 +464,7 @@ func (builder *TxsBuilderType) initCLI() {
 
 		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
 
-		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+		tx, err := builder.CreateZetherTx(builder.wallet.GetActiveWallet(), txData, nil, propagate, true, true, false, ctx, func(status string) {
 			gui.GUI.OutputWrite(status)
 		})
 		if err != nil {
@@ -310,7 +506,7 @@ func (builder *TxsBuilderType) initCLI() {
 			}},
 		}
 
-		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will increase the supply of asset", ctx); err != nil {
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.GetActiveWallet().CliSelectAddress("Select Address which will increase the supply of asset", ctx); err != nil {
 			return
 		}
 
@@ -335,7 +531,7 @@ func (builder *TxsBuilderType) initCLI() {
 		txData.Payloads[0].Fee = builder.readZetherFee(config_coins.NATIVE_ASSET_FULL)
 		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
 
-		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+		tx, err := builder.CreateZetherTx(builder.wallet.GetActiveWallet(), txData, nil, propagate, true, true, false, ctx, func(status string) {
 			gui.GUI.OutputWrite(status)
 		})
 		if err != nil {
@@ -358,7 +554,7 @@ func (builder *TxsBuilderType) initCLI() {
 			}},
 		}
 
-		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will fund a plain account", ctx); err != nil {
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.GetActiveWallet().CliSelectAddress("Select Address which will fund a plain account", ctx); err != nil {
 			return
 		}
 
@@ -379,7 +575,7 @@ func (builder *TxsBuilderType) initCLI() {
 
 		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
 
-		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+		tx, err := builder.CreateZetherTx(builder.wallet.GetActiveWallet(), txData, nil, propagate, true, true, false, ctx, func(status string) {
 			gui.GUI.OutputWrite(status)
 		})
 		if err != nil {
@@ -401,7 +597,7 @@ func (builder *TxsBuilderType) initCLI() {
 			}, {}},
 		}
 
-		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address to Transfer", ctx); err != nil {
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.GetActiveWallet().CliSelectAddress("Select Address to Transfer", ctx); err != nil {
 			return
 		}
 		txData.Payloads[1].Sender = txData.Payloads[0].Sender
@@ -455,6 +651,7 @@ func (builder *TxsBuilderType) initCLI() {
 		txData.Payloads[1].RingConfiguration = &ZetherRingConfiguration{
 			&ZetherSenderRingType{false, true, []string{}, 0},
 			&ZetherRecipientRingType{false, true, []string{}, txData.Payloads[0].RingConfiguration.RecipientRingType.NewAccounts},
+			txData.Payloads[0].RingConfiguration.Strategy,
 		}
 
 		txData.Payloads[0].Data = builder.readData()
@@ -463,7 +660,7 @@ func (builder *TxsBuilderType) initCLI() {
 		txData.Payloads[1].Fee = txData.Payloads[0].Fee
 		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
 
-		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+		tx, err := builder.CreateZetherTx(builder.wallet.GetActiveWallet(), txData, nil, propagate, true, true, false, ctx, func(status string) {
 			gui.GUI.OutputWrite(status)
 		})
 		if err != nil {
@@ -484,7 +681,7 @@ func (builder *TxsBuilderType) initCLI() {
 			FeeVersion: true,
 		}
 
-		if _, txData.Sender, _, err = builder.wallet.CliSelectAddress("Select Address to Publicly Update Asset Fee Liquidity", ctx); err != nil {
+		if _, txData.Sender, _, err = builder.wallet.GetActiveWallet().CliSelectAddress("Select Address to Publicly Update Asset Fee Liquidity", ctx); err != nil {
 			return
 		}
 
@@ -600,7 +797,129 @@ func (builder *TxsBuilderType) initCLI() {
 		return
 	}
 
+	cliCreateBalanceProofs := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		var encodedAddresses []string
+		if str := gui.GUI.OutputReadString("Addresses separated by comma. Leave empty to select one"); str != "" {
+			for _, address := range strings.Split(str, ",") {
+				encodedAddresses = append(encodedAddresses, strings.TrimSpace(address))
+			}
+		} else {
+			_, address, _, err := builder.wallet.GetActiveWallet().CliSelectAddress("Select Address to prove", ctx)
+			if err != nil {
+				return err
+			}
+			encodedAddresses = append(encodedAddresses, address)
+		}
+
+		asset := builder.readAsset("Asset. Leave empty for Native Asset", true)
+
+		minBalance, err := builder.readAmount(asset, "Minimum Balance")
+		if err != nil {
+			return
+		}
+
+		challenge := []byte(gui.GUI.OutputReadString("Challenge provided by the verifier"))
+
+		rows := make([]*TxBuilderBalanceProofRow, len(encodedAddresses))
+		for i, address := range encodedAddresses {
+			rows[i] = &TxBuilderBalanceProofRow{address, minBalance}
+		}
+
+		results, err := builder.CreateBalanceProofs(builder.wallet.GetActiveWallet(), rows, asset, challenge, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		for _, result := range results {
+			if result.Error != "" {
+				gui.GUI.OutputWrite(fmt.Sprintf("%s NOT PROVEN %s", result.Address, result.Error))
+			} else {
+				gui.GUI.OutputWrite(fmt.Sprintf("%s %s", result.Address, result.Proof))
+			}
+		}
+
+		return
+	}
+
+	cliVerifyBalanceProof := func(cmd string, ctx context.Context) (err error) {
+
+		proof, err := balance_proof.DecodeBalanceProof(gui.GUI.OutputReadString("Balance Proof"))
+		if err != nil {
+			return
+		}
+
+		output, err := VerifyBalanceProof(proof)
+		if err != nil {
+			return
+		}
+
+		minBalance := strconv.FormatUint(output.MinBalance, 10)
+		if bytes.Equal(output.Asset, config_coins.NATIVE_ASSET_FULL) {
+			minBalance = strconv.FormatFloat(config_coins.ConvertToBase(output.MinBalance), 'f', config_coins.DECIMAL_SEPARATOR, 64)
+		}
+
+		gui.GUI.OutputWrite("Balance Proof is valid")
+		gui.GUI.OutputWrite(fmt.Sprintf("%s has at least %s of %s at height %d", output.Address, minBalance, base64.StdEncoding.EncodeToString(output.Asset), output.Height))
+		gui.GUI.OutputWrite("Challenge: " + string(output.Challenge))
+		return
+	}
+
+	cliTxPrivacyReport := func(cmd string, ctx context.Context) (err error) {
+
+		hash := gui.GUI.OutputReadBytes("Provide TxId", func(val []byte) bool {
+			return len(val) == cryptography.HashSize
+		})
+
+		report, err := builder.GetTxPrivacyReport(hash)
+		if err != nil {
+			return
+		}
+
+		if !report.ExtendedInfo {
+			gui.GUI.Info("The node doesn't provide the extended info. The activity of the ring members is not analyzed")
+		}
+
+		for _, payload := range report.Payloads {
+			gui.GUI.OutputWrite(fmt.Sprintf("Payload %d %s ring %d", payload.Index, payload.PayloadScript, payload.RingSize))
+			gui.GUI.OutputWrite(fmt.Sprintf("   Anonymity set: sender %.1f recipient %.1f", payload.SenderAnonymitySet, payload.RecipientAnonymitySet))
+			for _, member := range payload.Members {
+				flags := make([]string, 0, 4)
+				if member.NewlyCreated {
+					flags = append(flags, "new")
+				}
+				if member.NoOtherActivity {
+					flags = append(flags, "no activity")
+				}
+				if member.SpendPublicKey {
+					flags = append(flags, "spend key")
+				}
+				if len(member.OverlappingTxs) > 0 {
+					flags = append(flags, fmt.Sprintf("reused in %d txs", len(member.OverlappingTxs)))
+				}
+				if len(flags) > 0 {
+					gui.GUI.OutputWrite(fmt.Sprintf("   %s %s", member.Address, strings.Join(flags, ", ")))
+				}
+			}
+			for _, warning := range payload.Warnings {
+				gui.GUI.OutputWrite("   " + warning)
+			}
+		}
+
+		return
+	}
+
 	gui.GUI.CommandDefineCallback("Private Transfer", cliPrivateTransfer, true)
+	gui.GUI.CommandDefineCallback("Private Batch Transfer", cliPrivateBatchTransfer, true)
+	gui.GUI.CommandDefineCallback("Private Transfer Prepare Offline", cliPrivateTransferPrepareOffline, true)
+	gui.GUI.CommandDefineCallback("Sign Offline Transaction", cliSignOffline, true)
+	gui.GUI.CommandDefineCallback("Broadcast Offline Transaction", cliBroadcastOffline, true)
+	gui.GUI.CommandDefineCallback("Tx Privacy Report", cliTxPrivacyReport, true)
+	gui.GUI.CommandDefineCallback("Create Balance Proofs", cliCreateBalanceProofs, true)
+	gui.GUI.CommandDefineCallback("Verify Balance Proof", cliVerifyBalanceProof, true)
 	gui.GUI.CommandDefineCallback("Private Asset Create", cliPrivateAssetCreate, true)
 	gui.GUI.CommandDefineCallback("Private Asset Supply Increase", cliPrivateAssetSupplyIncrease, true)
 	gui.GUI.CommandDefineCallback("Private Plain Account Fund", cliPrivatePlainAccountFund, true)
@@ -0,0 +1,320 @@
+package txs_builder
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage/registrations"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_registrations/transaction_zether_registration"
+	"pandora-pay/config"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+const PRIVACY_REPORT_LOOKBACK_TXS = 10 //recent txs of every ring member checked for overlapping rings
+
+type TxPrivacyReportMember struct {
+	Address         string           `json:"address" msgpack:"address"`
+	Sender          bool             `json:"sender" msgpack:"sender"` //the parity of the payload reveals the half of the ring holding the sender
+	NewlyCreated    bool             `json:"newlyCreated,omitempty" msgpack:"newlyCreated,omitempty"`
+	NoOtherActivity bool             `json:"noOtherActivity,omitempty" msgpack:"noOtherActivity,omitempty"`
+	SpendPublicKey  bool             `json:"spendPublicKey,omitempty" msgpack:"spendPublicKey,omitempty"`
+	OverlappingTxs  []helpers.Base64 `json:"overlappingTxs,omitempty" msgpack:"overlappingTxs,omitempty"`
+}
+
+type TxPrivacyReportPayload struct {
+	Index                 int                      `json:"index" msgpack:"index"`
+	PayloadScript         string                   `json:"payloadScript" msgpack:"payloadScript"`
+	Asset                 helpers.Base64           `json:"asset" msgpack:"asset"`
+	RingSize              int                      `json:"ringSize" msgpack:"ringSize"`
+	Members               []*TxPrivacyReportMember `json:"members" msgpack:"members"`
+	SenderAnonymitySet    float64                  `json:"senderAnonymitySet" msgpack:"senderAnonymitySet"`
+	RecipientAnonymitySet float64                  `json:"recipientAnonymitySet" msgpack:"recipientAnonymitySet"`
+	Warnings              []string                 `json:"warnings,omitempty" msgpack:"warnings,omitempty"`
+}
+
+type TxPrivacyReport struct {
+	Hash         helpers.Base64            `json:"hash" msgpack:"hash"`
+	Mempool      bool                      `json:"mempool,omitempty" msgpack:"mempool,omitempty"`
+	ExtendedInfo bool                      `json:"extendedInfo" msgpack:"extendedInfo"` //activity and overlapping rings are analyzed only by the nodes providing the extended info
+	Payloads     []*TxPrivacyReportPayload `json:"payloads" msgpack:"payloads"`
+}
+
+// privacyReportMemberWeight estimates how plausible is the member to be the real sender or recipient
+func privacyReportMemberWeight(member *TxPrivacyReportMember, excluded bool) float64 {
+	switch {
+	case excluded:
+		return 0
+	case member.NewlyCreated && member.Sender: //new accounts have no balance to be spent
+		return 0
+	case member.NewlyCreated, member.NoOtherActivity:
+		return 0.5
+	default:
+		return 1
+	}
+}
+
+// analyzePrivacyPayload fills the anonymity sets and the warnings out of the flags of the members
+func analyzePrivacyPayload(report *TxPrivacyReportPayload, spendPublicKeys [][]byte, payloadSpendPublicKey []byte) {
+
+	var newlyCreated, noActivity, spendKeys, overlapping int
+
+	for i, member := range report.Members {
+
+		excluded := false
+		if member.Sender && payloadSpendPublicKey != nil && !bytes.Equal(spendPublicKeys[i], payloadSpendPublicKey) { //only the owners of the spend key can sign
+			excluded = true
+		}
+
+		if member.Sender {
+			report.SenderAnonymitySet += privacyReportMemberWeight(member, excluded)
+		} else {
+			report.RecipientAnonymitySet += privacyReportMemberWeight(member, excluded)
+		}
+
+		if member.NewlyCreated {
+			newlyCreated++
+		}
+		if member.NoOtherActivity {
+			noActivity++
+		}
+		if member.SpendPublicKey {
+			spendKeys++
+		}
+		if len(member.OverlappingTxs) > 0 {
+			overlapping++
+		}
+	}
+
+	if report.SenderAnonymitySet < 1 {
+		report.SenderAnonymitySet = 1
+	}
+	if report.RecipientAnonymitySet < 1 {
+		report.RecipientAnonymitySet = 1
+	}
+
+	if newlyCreated > 0 {
+		report.Warnings = append(report.Warnings, "Ring contains accounts newly created in the same transaction")
+	}
+	if noActivity > 0 {
+		report.Warnings = append(report.Warnings, "Ring contains accounts without any other activity")
+	}
+	if spendKeys > 0 {
+		report.Warnings = append(report.Warnings, "Ring contains accounts with a Spend Public Key which makes the sender guessable")
+	}
+	if overlapping > 0 {
+		report.Warnings = append(report.Warnings, "Ring members were reused together in other transactions")
+	}
+	if report.SenderAnonymitySet <= 2 {
+		report.Warnings = append(report.Warnings, "Sender anonymity set is very small")
+	}
+}
+
+type privacyReportAnalyzer struct {
+	reader   store_db_interface.StoreDBTransactionInterface
+	regs     *registrations.Registrations
+	hash     []byte
+	ringsTxs map[string]map[string]bool //cache of the ring members of the other txs
+}
+
+func (analyzer *privacyReportAnalyzer) getTxRingsMembers(hash []byte) (map[string]bool, error) {
+
+	if members, ok := analyzer.ringsTxs[string(hash)]; ok {
+		return members, nil
+	}
+
+	members := make(map[string]bool)
+
+	txBase, err := getZetherTx(analyzer.reader, hash)
+	if err != nil {
+		return nil, err
+	}
+	if txBase != nil {
+		for _, list := range txBase.Bloom.PublicKeyLists {
+			for _, member := range list {
+				members[string(member)] = true
+			}
+		}
+	}
+
+	analyzer.ringsTxs[string(hash)] = members
+	return members, nil
+}
+
+// analyzeActivity flags the members without other activity and the other txs sharing at least two ring members
+func (analyzer *privacyReportAnalyzer) analyzeActivity(ring [][]byte, members []*TxPrivacyReportMember) error {
+
+	ringMembers := make(map[string]bool)
+	for _, publicKey := range ring {
+		ringMembers[string(publicKey)] = true
+	}
+
+	for i, publicKey := range ring {
+
+		hashes, err := getAccountRecentTxs(analyzer.reader, publicKey, PRIVACY_REPORT_LOOKBACK_TXS+1)
+		if err != nil {
+			return err
+		}
+
+		members[i].NoOtherActivity = true
+		for _, hash := range hashes {
+
+			if bytes.Equal(hash, analyzer.hash) {
+				continue
+			}
+			members[i].NoOtherActivity = false
+
+			otherMembers, err := analyzer.getTxRingsMembers(hash)
+			if err != nil {
+				return err
+			}
+
+			overlap := 0
+			for key := range otherMembers {
+				if ringMembers[key] {
+					overlap++
+				}
+			}
+			if overlap >= 2 {
+				members[i].OverlappingTxs = append(members[i].OverlappingTxs, hash)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (analyzer *privacyReportAnalyzer) analyzePayload(txBase *transaction_zether.TransactionZether, t int) (*TxPrivacyReportPayload, error) {
+
+	payload := txBase.Payloads[t]
+	ring := txBase.Bloom.PublicKeyLists[t]
+
+	report := &TxPrivacyReportPayload{
+		Index:         t,
+		PayloadScript: payload.PayloadScript.String(),
+		Asset:         payload.Asset,
+		RingSize:      len(ring),
+		Members:       make([]*TxPrivacyReportMember, len(ring)),
+	}
+
+	spendPublicKeys := make([][]byte, len(ring))
+
+	for i, publicKey := range ring {
+
+		addr, err := addresses.CreateAddr(publicKey, false, nil, nil, nil, 0, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		member := &TxPrivacyReportMember{
+			Address: addr.EncodeAddr(),
+			Sender:  (i%2 == 0) == payload.Parity,
+		}
+
+		if reg := payload.Registrations.Registrations[i]; reg != nil && reg.RegistrationType == transaction_zether_registration.NOT_REGISTERED {
+			member.NewlyCreated = true
+			spendPublicKeys[i] = reg.RegistrationSpendPublicKey
+		} else {
+			var reg *registration.Registration
+			if reg, err = analyzer.regs.Get(string(publicKey)); err != nil {
+				return nil, err
+			}
+			if reg != nil {
+				spendPublicKeys[i] = reg.SpendPublicKey
+			}
+		}
+		member.SpendPublicKey = len(spendPublicKeys[i]) > 0
+
+		report.Members[i] = member
+	}
+
+	if config.NODE_PROVIDE_EXTENDED_INFO_APP {
+		if err := analyzer.analyzeActivity(ring, report.Members); err != nil {
+			return nil, err
+		}
+	}
+
+	var payloadSpendPublicKey []byte
+	if payload.PayloadScript == transaction_zether_payload_script.SCRIPT_SPEND {
+		payloadSpendPublicKey = payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend).SenderSpendPublicKey.EncodeCompressed()
+	}
+
+	analyzePrivacyPayload(report, spendPublicKeys, payloadSpendPublicKey)
+
+	return report, nil
+}
+
+// CreateTxPrivacyReport estimates how private are the rings of a zether transaction
+func CreateTxPrivacyReport(tx *transaction.Transaction) (report *TxPrivacyReport, err error) {
+
+	if tx.Version != transaction_type.TX_ZETHER {
+		return nil, errors.New("Only zether transactions have rings")
+	}
+	if tx.Bloom == nil {
+		if err = tx.BloomAll(); err != nil {
+			return
+		}
+	}
+
+	txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+
+	report = &TxPrivacyReport{
+		Hash:         tx.Bloom.Hash,
+		ExtendedInfo: config.NODE_PROVIDE_EXTENDED_INFO_APP,
+		Payloads:     make([]*TxPrivacyReportPayload, len(txBase.Payloads)),
+	}
+
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		analyzer := &privacyReportAnalyzer{reader, registrations.NewRegistrations(reader), tx.Bloom.Hash, make(map[string]map[string]bool)}
+		for t := range txBase.Payloads {
+			if report.Payloads[t], err = analyzer.analyzePayload(txBase, t); err != nil {
+				return
+			}
+		}
+
+		return
+	}); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// GetTxPrivacyReport loads the transaction from the mempool or from the blockchain
+func (builder *TxsBuilderType) GetTxPrivacyReport(hash []byte) (*TxPrivacyReport, error) {
+
+	if txMempool := builder.mempool.Txs.Get(string(hash)); txMempool != nil {
+		report, err := CreateTxPrivacyReport(txMempool.Tx)
+		if err != nil {
+			return nil, err
+		}
+		report.Mempool = true
+		return report, nil
+	}
+
+	tx := &transaction.Transaction{}
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		data := reader.Get("tx:" + string(hash))
+		if data == nil {
+			return errors.New("Tx not found")
+		}
+		return tx.Deserialize(advanced_buffers.NewBufferReader(data))
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.BloomAll(); err != nil {
+		return nil, err
+	}
+
+	return CreateTxPrivacyReport(tx)
+}
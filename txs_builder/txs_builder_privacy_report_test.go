@@ -0,0 +1,44 @@
+package txs_builder
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/helpers"
+	"testing"
+)
+
+func TestAnalyzePrivacyPayload(t *testing.T) {
+
+	newReport := func() *TxPrivacyReportPayload {
+		report := &TxPrivacyReportPayload{RingSize: 8, Members: make([]*TxPrivacyReportMember, 8)}
+		for i := range report.Members {
+			report.Members[i] = &TxPrivacyReportMember{Sender: i%2 == 0}
+		}
+		return report
+	}
+
+	report := newReport()
+	analyzePrivacyPayload(report, make([][]byte, 8), nil)
+	assert.Equal(t, 4.0, report.SenderAnonymitySet)
+	assert.Equal(t, 4.0, report.RecipientAnonymitySet)
+	assert.Empty(t, report.Warnings)
+
+	report = newReport()
+	report.Members[0].NewlyCreated = true
+	report.Members[1].NewlyCreated = true
+	report.Members[2].NoOtherActivity = true
+	report.Members[3].OverlappingTxs = []helpers.Base64{{1}}
+	analyzePrivacyPayload(report, make([][]byte, 8), nil)
+	assert.Equal(t, 2.5, report.SenderAnonymitySet)
+	assert.Equal(t, 3.5, report.RecipientAnonymitySet)
+	assert.Len(t, report.Warnings, 3)
+
+	//a spend payload reveals the spend key, only the senders having it are plausible
+	report = newReport()
+	spendPublicKeys := make([][]byte, 8)
+	spendPublicKeys[4] = []byte{1, 2, 3}
+	report.Members[4].SpendPublicKey = true
+	analyzePrivacyPayload(report, spendPublicKeys, []byte{1, 2, 3})
+	assert.Equal(t, 1.0, report.SenderAnonymitySet)
+	assert.Equal(t, 4.0, report.RecipientAnonymitySet)
+	assert.Contains(t, report.Warnings, "Sender anonymity set is very small")
+}
@@ -0,0 +1,95 @@
+package txs_builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/helpers"
+	"pandora-pay/txs_builder/wizard"
+	"pandora-pay/wallet/wallet_address"
+	"strings"
+	"time"
+)
+
+const REMOTE_SPEND_SIGNER_TIMEOUT = 30 * time.Second
+
+type remoteSpendSignerRequest struct {
+	Tx             helpers.Base64 `json:"tx"`
+	PayloadIndex   int            `json:"payloadIndex"`
+	R              helpers.Base64 `json:"r"`
+	SenderIndex    int            `json:"senderIndex"`
+	RecipientIndex int            `json:"recipientIndex"`
+	Amount         uint64         `json:"amount"`
+	Code           string         `json:"code,omitempty"`
+}
+
+type remoteSpendSignerReply struct {
+	Signature helpers.Base64 `json:"signature"`
+}
+
+// newRemoteSpendSigner requests the spend signature from the co-signer node using the cosigner/sign api
+func newRemoteSpendSigner(signer *wallet_address.WalletAddressRemoteSpendSigner, code string) wizard.WizardZetherSpendSigner {
+	return func(tx *transaction.Transaction, payloadIndex int, witness *wizard.WizardZetherSpendWitness) ([]byte, error) {
+
+		data, err := json.Marshal(&struct {
+			Token string                    `json:"token"`
+			Req   *remoteSpendSignerRequest `json:"req"`
+		}{
+			signer.Token,
+			&remoteSpendSignerRequest{
+				tx.SerializeManualToBytes(),
+				payloadIndex,
+				witness.R,
+				witness.SenderIndex,
+				witness.RecipientIndex,
+				witness.Amount,
+				code,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		client := &http.Client{Timeout: REMOTE_SPEND_SIGNER_TIMEOUT}
+		res, err := client.Post(strings.TrimSuffix(signer.URL, "/")+"/cosigner/sign", "application/json", bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode != http.StatusOK {
+			return nil, errors.New("Co-signer refused to sign: " + strings.TrimSpace(string(body)))
+		}
+
+		reply := &remoteSpendSignerReply{}
+		if err = json.Unmarshal(body, reply); err != nil {
+			return nil, err
+		}
+
+		return reply.Signature, nil
+	}
+}
+
+// setSenderSpendKey provides either the Spend Private Key of the address or the co-signer holding it
+func setSenderSpendKey(transfer *wizard.WizardZetherTransfer, addr *wallet_address.WalletAddress, spendPublicKey []byte, code string) (err error) {
+
+	if addr.RemoteSpendSigner == nil {
+		transfer.SenderSpendPrivateKey, err = getSpendPrivateKey(addr, spendPublicKey)
+		return
+	}
+
+	if !bytes.Equal(addr.SpendPublicKey, spendPublicKey) {
+		return errors.New("Wallet Spend Public Key is not matching")
+	}
+
+	transfer.SenderSpendPublicKey = spendPublicKey
+	transfer.SenderSpendSigner = newRemoteSpendSigner(addr.RemoteSpendSigner, code)
+	return
+}
@@ -15,41 +15,27 @@ import (
 	"pandora-pay/blockchain/data_storage/accounts"
 	"pandora-pay/blockchain/data_storage/accounts/account"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
-	"pandora-pay/blockchain/data_storage/registrations"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config"
 	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_nodes"
 	"pandora-pay/cryptography/bn256"
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/gui"
+	"pandora-pay/helpers"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
 	"pandora-pay/txs_builder/txs_builder_zether_helper"
 	"pandora-pay/txs_builder/wizard"
 	"pandora-pay/txs_validator"
+	"pandora-pay/wallet"
 	"pandora-pay/wallet/wallet_address"
 )
 
-func (builder *TxsBuilderType) getRandomAccount(accs *accounts.Accounts, regs *registrations.Registrations) (addr *addresses.Address, acc *account.Account, reg *registration.Registration, err error) {
-
-	if acc, err = accs.GetRandom(); err != nil {
-		return nil, nil, nil, err
-	}
-	if acc == nil {
-		return nil, nil, nil, errors.New("Error getting any random account")
-	}
-
-	if reg, err = regs.Get(string(acc.Key)); err != nil {
-		return nil, nil, nil, err
-	}
-
-	if addr, err = addresses.CreateAddr(acc.Key, false, nil, nil, nil, 0, nil); err != nil {
-		return nil, nil, nil, err
-	}
-
-	return
-}
+// ZETHER_RING_MAX_SAMPLING_ATTEMPTS limits the random accounts sampled for a ring as the accounts may be filtered out by the ring configuration
+const ZETHER_RING_MAX_SAMPLING_ATTEMPTS = 10000
 
 func (builder *TxsBuilderType) presetZetherRing(payload *TxBuilderCreateZetherTxPayload) error {
 
@@ -114,13 +100,21 @@ func (builder *TxsBuilderType) createZetherRing(allAlreadyUsed map[string]bool,
 		return
 	}
 
+	var sampler *zetherRingSampler
+	if sampler, err = builder.createZetherRingSampler(payload, dataStorage.DBTx); err != nil {
+		return
+	}
+
 	setAddress := func(ring *[]string, address *string, requireStakedAccounts, avoidStakedAccounts bool) (err error) {
 		if *address == "" {
 			if accs.Count == uint64(len(alreadyUsed)) {
 				return errors.New("Accounts have only member. Impossible to get random recipient")
 			}
-			for {
-				if addr, _, reg, err = builder.getRandomAccount(accs, dataStorage.Regs); err != nil {
+			for attempts := 0; ; attempts++ {
+				if attempts == ZETHER_RING_MAX_SAMPLING_ATTEMPTS {
+					return errors.New("No account matching the ring configuration was found")
+				}
+				if addr, _, reg, err = sampler.getRandomAccount(accs, dataStorage.Regs); err != nil {
 					return
 				}
 				if avoidStakedAccounts && reg.Staked {
@@ -207,15 +201,16 @@ func (builder *TxsBuilderType) createZetherRing(allAlreadyUsed map[string]bool,
 
 	newRandomAccounts := func(ring *[]string, requireStakedAccounts, avoidStakedAccounts bool) (err error) {
 
-		for len(*ring) < payload.RingSize/2 {
+		for attempts := 0; len(*ring) < payload.RingSize/2; attempts++ {
 
-			if accs.Count <= uint64(len(alreadyUsed)) {
+			//the decoys of the other payloads can't be used again. In case the accounts left don't match the ring configuration, new accounts are used
+			if accs.Count <= uint64(len(allAlreadyUsed)) || attempts >= ZETHER_RING_MAX_SAMPLING_ATTEMPTS {
 				priv := addresses.GenerateNewPrivateKey()
 				if addr, err = priv.GenerateAddress(requireStakedAccounts, nil, true, nil, 0, nil); err != nil {
 					return
 				}
 			} else {
-				if addr, _, reg, err = builder.getRandomAccount(accs, dataStorage.Regs); err != nil {
+				if addr, _, reg, err = sampler.getRandomAccount(accs, dataStorage.Regs); err != nil {
 					return
 				}
 				if alreadyUsed[string(addr.PublicKey)] || allAlreadyUsed[string(addr.PublicKey)] {
@@ -268,7 +263,8 @@ func (builder *TxsBuilderType) createZetherRing(allAlreadyUsed map[string]bool,
 	return
 }
 
-func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pendingTxs []*transaction.Transaction, blockHeight uint64, prevKernelHash []byte, ctx context.Context, statusCallback func(string)) ([]*wizard.WizardZetherTransfer, map[string]map[string][]byte, map[string]bool, [][]*bn256.G1, [][]*bn256.G1, map[string]*wizard.WizardZetherPublicKeyIndex, uint64, []byte, error) {
+// prebuild collects the chain data required by the zether proofs. In case offline is true, no private key is used and the balances are not decrypted
+func (builder *TxsBuilderType) prebuild(senderWallet *wallet.Wallet, txData *TxBuilderCreateZetherTxData, pendingTxs []*transaction.Transaction, blockHeight uint64, prevKernelHash []byte, offline bool, ctx context.Context, statusCallback func(string)) (*TxBuilderZetherTxContext, error) {
 
 	sendersPrivateKeys := make([]*addresses.PrivateKey, len(txData.Payloads))
 	sendersWalletAddresses := make([]*wallet_address.WalletAddress, len(txData.Payloads))
@@ -278,42 +274,88 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 
 	for t, payload := range txData.Payloads {
 
+		//a contact name can be used instead of the recipient address
+		if payload.Recipient != "" {
+			var contact *wallet.WalletContact
+			if payload.Recipient, contact = senderWallet.ResolveContact(payload.Recipient); contact != nil && payload.Asset == nil && contact.DefaultAsset != nil {
+				payload.Asset = contact.DefaultAsset
+			}
+		}
+
 		if payload.Asset == nil {
 			payload.Asset = config_coins.NATIVE_ASSET_FULL
 		}
+
+		var recipientAddr *addresses.Address
+		if payload.Recipient != "" {
+			var err error
+			if recipientAddr, err = addresses.DecodeAddr(payload.Recipient); err != nil {
+				return nil, err
+			}
+		}
+		integrated := recipientAddr != nil && recipientAddr.IsIntegratedPaymentID()
+
 		if payload.Data == nil {
-			payload.Data = &wizard.WizardTransactionData{[]byte{}, false}
+			payload.Data = &wizard.WizardTransactionData{[]byte{}, integrated}
+		}
+
+		//the PaymentID of an integrated address is sent only in the encrypted data
+		if integrated {
+			if !payload.Data.Encrypt {
+				return nil, errors.New("The data of a payment to an integrated address with PaymentID must be encrypted")
+			}
+			if !addresses.HasPaymentIDData(payload.Data.Data, recipientAddr.PaymentID) {
+				payload.Data = &wizard.WizardTransactionData{addresses.EncodePaymentIDData(recipientAddr.PaymentID, payload.Data.Data), true}
+			}
 		}
+
 		if payload.RingConfiguration == nil {
-			payload.RingConfiguration = &ZetherRingConfiguration{&ZetherSenderRingType{false, false, nil, 0}, &ZetherRecipientRingType{false, false, nil, 0}}
+			payload.RingConfiguration = &ZetherRingConfiguration{&ZetherSenderRingType{false, false, nil, 0}, &ZetherRecipientRingType{false, false, nil, 0}, nil}
 		}
 		if payload.Fee == nil {
 			payload.Fee = &wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, true}, false, 0, 0}
 		}
 
 		sendAssets[t] = payload.Asset
-		if payload.Sender == "" {
+		if offline {
+
+			if payload.Sender == "" {
+				return nil, errors.New("Sender is required for offline transactions")
+			}
+			if payload.Extra != nil {
+				return nil, errors.New("Offline transactions support only transfers")
+			}
+			if _, err := addresses.DecodeAddr(payload.Sender); err != nil {
+				return nil, err
+			}
+
+		} else if payload.Sender == "" {
 
 			sendersPrivateKeys[t] = addresses.GenerateNewPrivateKey()
 			addr, err := sendersPrivateKeys[t].GenerateAddress(false, nil, true, nil, 0, nil)
 			if err != nil {
-				return nil, nil, nil, nil, nil, nil, 0, nil, err
+				return nil, err
 			}
 			payload.Sender = addr.EncodeAddr()
 
 		} else {
 
-			addr, err := builder.wallet.GetWalletAddressByEncodedAddress(payload.Sender, true)
+			addr, err := senderWallet.GetWalletAddressByEncodedAddress(payload.Sender, true)
 			if err != nil {
-				return nil, nil, nil, nil, nil, nil, 0, nil, err
+				return nil, err
 			}
 
 			if addr.PrivateKey == nil {
-				return nil, nil, nil, nil, nil, nil, 0, nil, errors.New("Can't be used for transactions as the private key is missing")
+				return nil, errors.New("Can't be used for transactions as the private key is missing")
+			}
+			if addr.WatchOnly {
+				if _, staking := payload.Extra.(*wizard.WizardZetherPayloadExtraStaking); !staking {
+					return nil, fmt.Errorf("Address %s is watch-only and can't spend", payload.Sender)
+				}
 			}
 
 			if sendersPrivateKeys[t], err = addresses.NewPrivateKey(addr.PrivateKey.Key); err != nil {
-				return nil, nil, nil, nil, nil, nil, 0, nil, err
+				return nil, err
 			}
 			sendersWalletAddresses[t] = addr
 
@@ -325,6 +367,7 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 	recipientRingMembers := make([][]string, len(txData.Payloads))
 
 	transfers := make([]*wizard.WizardZetherTransfer, len(txData.Payloads))
+	fees := make([]*wizard.WizardTransactionFee, len(txData.Payloads))
 	emap := wizard.InitializeEmap(sendAssets)
 
 	ringsSenderMembers := make([][]*bn256.G1, len(txData.Payloads))
@@ -336,10 +379,11 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 	publicKeyIndexes := make(map[string]*wizard.WizardZetherPublicKeyIndex)
 
 	sendersEncryptedBalances := make([][]byte, len(txData.Payloads))
+	sendersSpendPublicKeys := make([][]byte, len(txData.Payloads))
 
 	for _, payload := range txData.Payloads {
 		if err := builder.presetZetherRing(payload); err != nil {
-			return nil, nil, nil, nil, nil, nil, 0, nil, err
+			return nil, err
 		}
 	}
 
@@ -363,6 +407,11 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 			if payload.Extra != nil {
 				switch payload.Extra.(type) {
 				case *wizard.WizardZetherPayloadExtraStakingReward:
+					//the delegator commission is paid from a new temporary account using its own rings
+					if _, ok := txData.Payloads[t-1].Extra.(*wizard.WizardZetherPayloadExtraStaking); !ok {
+						break
+					}
+
 					recipientRingMembers[t] = append(recipientRingMembers[t], senderRingMembers[t-1]...)
 					senderRingMembers[t] = append(senderRingMembers[t], recipientRingMembers[t-1]...)
 					payload.Recipient = txData.Payloads[t-1].Sender
@@ -395,7 +444,7 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 
 		return
 	}); err != nil {
-		return nil, nil, nil, nil, nil, nil, 0, nil, err
+		return nil, err
 	}
 
 	var chainHeight uint64
@@ -432,17 +481,20 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 			}
 
 			transfers[t] = &wizard.WizardZetherTransfer{
-				Asset:            payload.Asset,
-				SenderPrivateKey: sendersPrivateKeys[t].Key[:],
-				Recipient:        payload.Recipient,
-				Amount:           payload.Amount,
-				Burn:             payload.Burn,
-				Data:             payload.Data,
-				FeeRate:          payload.Fee.Rate,
-				FeeLeadingZeros:  payload.Fee.LeadingZeros,
-				PayloadExtra:     payload.Extra,
-				WitnessIndexes:   payload.WitnessIndexes,
+				Asset:           payload.Asset,
+				Recipient:       payload.Recipient,
+				Amount:          payload.Amount,
+				Burn:            payload.Burn,
+				Data:            payload.Data,
+				FeeRate:         payload.Fee.Rate,
+				FeeLeadingZeros: payload.Fee.LeadingZeros,
+				PayloadExtra:    payload.Extra,
+				WitnessIndexes:  payload.WitnessIndexes,
+			}
+			if sendersPrivateKeys[t] != nil {
+				transfers[t].SenderPrivateKey = sendersPrivateKeys[t].Key[:]
 			}
+			fees[t] = payload.Fee.WizardTransactionFee
 
 			//parity := transfers[t].WitnessIndexes[0]%2 == 0
 
@@ -527,13 +579,12 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 				if sender {
 					if reg != nil && len(reg.SpendPublicKey) > 0 && payload.Extra == nil {
 						transfers[t].SenderSpendRequired = true
-						if sendersWalletAddresses[t].SpendPrivateKey == nil {
-							return errors.New("Spend Private Key is missing")
-						}
-						if !bytes.Equal(sendersWalletAddresses[t].SpendPublicKey, reg.SpendPublicKey) {
-							return errors.New("Wallet Spend Public Key is not matching")
+						sendersSpendPublicKeys[t] = reg.SpendPublicKey
+						if !offline { //offline, the spend private key is provided when signing
+							if err = setSenderSpendKey(transfers[t], sendersWalletAddresses[t], reg.SpendPublicKey, txData.SpendConfirmationCode); err != nil {
+								return
+							}
 						}
-						transfers[t].SenderSpendPrivateKey = sendersWalletAddresses[t].SpendPrivateKey.Key
 					}
 				}
 
@@ -564,52 +615,117 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 
 		return
 	}); err != nil {
-		return nil, nil, nil, nil, nil, nil, 0, nil, err
+		return nil, err
 	}
 	statusCallback("Balances checked")
 
-	for t := range transfers {
+	senders := make([]string, len(txData.Payloads))
+	decryptedBalances := make([]uint64, len(txData.Payloads))
+	for t, payload := range txData.Payloads {
+		senders[t] = payload.Sender
+		decryptedBalances[t] = payload.DecryptedBalance
+	}
+
+	txContext := &TxBuilderZetherTxContext{
+		TX_BUILDER_ZETHER_TX_CONTEXT_VERSION,
+		transfers,
+		emap,
+		hasRollovers,
+		senderRingMembers,
+		recipientRingMembers,
+		publicKeyIndexes,
+		fees,
+		senders,
+		sendersEncryptedBalances,
+		sendersSpendPublicKeys,
+		decryptedBalances,
+		chainHeight,
+		chainKernelHash,
+		ringsSenderMembers,
+		ringsRecipientMembers,
+	}
+
+	if !offline {
+		if err := builder.decryptSendersBalances(txContext, sendersWalletAddresses, ctx, statusCallback); err != nil {
+			return nil, err
+		}
+	}
+
+	return txContext, nil
+}
+
+// decryptSendersBalances decrypts the balances of the senders and verifies the funds
+func (builder *TxsBuilderType) decryptSendersBalances(txContext *TxBuilderZetherTxContext, sendersWalletAddresses []*wallet_address.WalletAddress, ctx context.Context, statusCallback func(string)) (err error) {
+
+	remaining := make(map[string]uint64) //the same sender can be used in multiple payloads
 
+	for t, transfer := range txContext.Transfers {
+
+		key := txContext.Senders[t] + string(transfer.Asset)
 		verify := true
 
 		if sendersWalletAddresses[t] == nil {
-			transfers[t].SenderDecryptedBalance = transfers[t].Amount
-		} else if sendersEncryptedBalances[t] != nil {
+			transfer.SenderDecryptedBalance = transfer.Amount
+		} else if txContext.SendersEncryptedBalances[t] != nil {
 
-			if txData.Payloads[t].DecryptedBalance > 0 { // in case it was specified to avoid getting stuck
-				decrypted, err := builder.wallet.DecryptBalance(sendersWalletAddresses[t], sendersEncryptedBalances[t], transfers[t].Asset, true, txData.Payloads[t].DecryptedBalance, true, ctx, statusCallback)
-				if err != nil {
-					return nil, nil, nil, nil, nil, nil, 0, nil, err
+			if txContext.DecryptedBalances[t] > 0 { // in case it was specified to avoid getting stuck
+				if transfer.SenderDecryptedBalance, err = builder.wallet.DecryptBalance(sendersWalletAddresses[t], txContext.SendersEncryptedBalances[t], transfer.Asset, true, txContext.DecryptedBalances[t], true, ctx, statusCallback); err != nil {
+					return
 				}
-				transfers[t].SenderDecryptedBalance = decrypted
 			} else {
-				decrypted, err := builder.wallet.DecryptBalance(sendersWalletAddresses[t], sendersEncryptedBalances[t], transfers[t].Asset, false, 0, true, ctx, statusCallback)
-				if err != nil {
-					return nil, nil, nil, nil, nil, nil, 0, nil, err
+				if transfer.SenderDecryptedBalance, err = builder.wallet.DecryptBalance(sendersWalletAddresses[t], txContext.SendersEncryptedBalances[t], transfer.Asset, false, 0, true, ctx, statusCallback); err != nil {
+					return
 				}
-				transfers[t].SenderDecryptedBalance = decrypted
 			}
+		} else if balance, ok := remaining[key]; ok {
+			transfer.SenderDecryptedBalance = balance
 		} else {
 			verify = false
 		}
 
 		if verify {
-			if transfers[t].SenderDecryptedBalance == 0 {
-				return nil, nil, nil, nil, nil, nil, 0, nil, errors.New("You have no funds")
+			if transfer.SenderDecryptedBalance == 0 {
+				return errors.New("You have no funds")
+			}
+
+			if transfer.SenderDecryptedBalance < transfer.Amount {
+				return errors.New("Not enough funds")
 			}
 
-			if transfers[t].SenderDecryptedBalance < txData.Payloads[t].Amount {
-				return nil, nil, nil, nil, nil, nil, 0, nil, errors.New("Not enough funds")
+			if sendersWalletAddresses[t] != nil {
+				remaining[key] = transfer.SenderDecryptedBalance - transfer.Amount
+				if remaining[key] < transfer.Burn {
+					remaining[key] = 0
+				} else {
+					remaining[key] -= transfer.Burn
+				}
 			}
 		}
 	}
 
 	statusCallback("Balances decoded")
 
-	return transfers, emap, hasRollovers, ringsSenderMembers, ringsRecipientMembers, publicKeyIndexes, chainHeight, chainKernelHash, nil
+	return
 }
 
-func (builder *TxsBuilderType) CreateZetherTx(txData *TxBuilderCreateZetherTxData, pendingTxs []*transaction.Transaction, propagateTx, awaitAnswer, awaitBroadcast bool, validateTx bool, ctx context.Context, statusCallback func(string)) (*transaction.Transaction, error) {
+func getSpendPrivateKey(addr *wallet_address.WalletAddress, spendPublicKey []byte) ([]byte, error) {
+	if addr.SpendPrivateKey == nil {
+		return nil, errors.New("Spend Private Key is missing")
+	}
+	if !bytes.Equal(addr.SpendPublicKey, spendPublicKey) {
+		return nil, errors.New("Wallet Spend Public Key is not matching")
+	}
+	return addr.SpendPrivateKey.Key, nil
+}
+
+// CreateZetherTx creates the transaction. The senders are resolved only in the senderWallet
+func (builder *TxsBuilderType) CreateZetherTx(senderWallet *wallet.Wallet, txData *TxBuilderCreateZetherTxData, pendingTxs []*transaction.Transaction, propagateTx, awaitAnswer, awaitBroadcast bool, validateTx bool, ctx context.Context, statusCallback func(string)) (*transaction.Transaction, error) {
+	tx, _, err := builder.createZetherTx(senderWallet, txData, pendingTxs, propagateTx, awaitAnswer, awaitBroadcast, ctx, statusCallback)
+	return tx, err
+}
+
+// createZetherTx returns also the context used to create the transaction
+func (builder *TxsBuilderType) createZetherTx(senderWallet *wallet.Wallet, txData *TxBuilderCreateZetherTxData, pendingTxs []*transaction.Transaction, propagateTx, awaitAnswer, awaitBroadcast bool, ctx context.Context, statusCallback func(string)) (*transaction.Transaction, *TxBuilderZetherTxContext, error) {
 
 	if pendingTxs == nil {
 		pendingTxs = builder.mempool.Txs.GetTxsOnlyList()
@@ -618,32 +734,27 @@ func (builder *TxsBuilderType) CreateZetherTx(txData *TxBuilderCreateZetherTxDat
 	builder.lock.Lock()
 	defer builder.lock.Unlock()
 
-	transfers, emap, hasRollovers, ringsSenderMembers, ringsRecipientMembers, publicKeyIndexes, chainHeight, chainKernelHash, err := builder.prebuild(txData, pendingTxs, 0, nil, ctx, statusCallback)
+	txContext, err := builder.prebuild(senderWallet, txData, pendingTxs, 0, nil, false, ctx, statusCallback)
 	if err != nil {
-		return nil, err
-	}
-
-	feesFinal := make([]*wizard.WizardTransactionFee, len(txData.Payloads))
-	for t, payload := range txData.Payloads {
-		feesFinal[t] = payload.Fee.WizardTransactionFee
+		return nil, nil, err
 	}
 
 	var tx *transaction.Transaction
-	if tx, err = wizard.CreateZetherTx(transfers, emap, hasRollovers, ringsSenderMembers, ringsRecipientMembers, chainHeight-1, chainKernelHash, publicKeyIndexes, feesFinal, ctx, statusCallback); err != nil {
-		return nil, err
+	if tx, err = wizard.CreateZetherTx(txContext.Transfers, txContext.Emap, txContext.HasRollovers, txContext.ringsSenderMembers, txContext.ringsRecipientMembers, txContext.ChainHeight-1, txContext.ChainKernelHash, txContext.PublicKeyIndexes, txContext.Fees, ctx, statusCallback); err != nil {
+		return nil, nil, err
 	}
 
 	if err = txs_validator.TxsValidator.MarkAsValidatedTx(tx); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if propagateTx {
-		if err = builder.mempool.AddTxToMempool(tx, chainHeight, true, awaitAnswer, awaitBroadcast, advanced_connection_types.UUID_ALL, ctx); err != nil {
-			return nil, err
+		if err = builder.mempool.AddTxToMempool(tx, txContext.ChainHeight, true, awaitAnswer, awaitBroadcast, advanced_connection_types.UUID_ALL, ctx); err != nil {
+			return nil, nil, err
 		}
 	}
 
-	return tx, nil
+	return tx, txContext, nil
 }
 
 func (builder *TxsBuilderType) CreateForgingTransactions(blkComplete *block_complete.BlockComplete, forgerPublicKey []byte, decryptedBalance uint64, pendingTxs []*transaction.Transaction) (*transaction.Transaction, error) {
@@ -663,6 +774,32 @@ func (builder *TxsBuilderType) CreateForgingTransactions(blkComplete *block_comp
 		return nil, err
 	}
 
+	forgerWallet := builder.wallet.GetWalletByPublicKey(forgerPublicKey)
+	if forgerWallet == nil {
+		return nil, errors.New("Forger address was not found in the loaded wallets")
+	}
+
+	//the delegated stakes pay the agreed commission to the delegator node, only after the activation height
+	var commission uint64
+	var feeAddress string
+	if blkComplete.Height >= config.NETWORK_SELECTED_DELEGATOR_COMMISSION_ACTIVATION_HEIGHT {
+
+		var feeRate uint64
+		if feeRate, feeAddress, err = builder.wallet.GetDelegationCommission(forgerPublicKey); err != nil {
+			return nil, err
+		}
+
+		commission = finalForgerReward
+		if err = helpers.SafeUint64Mul(&commission, feeRate); err != nil {
+			return nil, err
+		}
+		commission /= config_nodes.DELEGATOR_FEE_DIVISOR
+		if commission > 0 && commission >= finalForgerReward { //the staking reward payload can not be empty
+			commission = finalForgerReward - 1
+		}
+		finalForgerReward -= commission
+	}
+
 	chainHeight := blkComplete.Height
 	if chainHeight > 0 {
 		chainHeight--
@@ -684,7 +821,7 @@ func (builder *TxsBuilderType) CreateForgingTransactions(blkComplete *block_comp
 				config_coins.NATIVE_ASSET_FULL,
 				0,
 				decryptedBalance,
-				&ZetherRingConfiguration{&ZetherSenderRingType{true, false, nil, 0}, &ZetherRecipientRingType{true, false, nil, 0}},
+				&ZetherRingConfiguration{&ZetherSenderRingType{true, false, nil, 0}, &ZetherRecipientRingType{true, false, nil, 0}, nil},
 				blkComplete.StakingAmount,
 				nil,
 				&wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, false}, false, 0, 0},
@@ -700,7 +837,7 @@ func (builder *TxsBuilderType) CreateForgingTransactions(blkComplete *block_comp
 				config_coins.NATIVE_ASSET_FULL,
 				finalForgerReward,
 				finalForgerReward, //reward will be the encrypted Balance
-				&ZetherRingConfiguration{&ZetherSenderRingType{true, false, nil, 0}, &ZetherRecipientRingType{true, false, nil, 0}},
+				&ZetherRingConfiguration{&ZetherSenderRingType{true, false, nil, 0}, &ZetherRecipientRingType{true, false, nil, 0}, nil},
 				0,
 				nil,
 				&wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, false}, false, 0, 0},
@@ -709,20 +846,34 @@ func (builder *TxsBuilderType) CreateForgingTransactions(blkComplete *block_comp
 		},
 	}
 
-	transfers, emap, hasRollovers, ringsSenderMembers, ringsRecipientMembers, publicKeyIndexes, _, _, err := builder.prebuild(txData, pendingTxs, blkComplete.Height, blkComplete.PrevKernelHash, context.Background(), func(string) {})
+	if commission > 0 {
+		txData.Payloads = append(txData.Payloads, &TxBuilderCreateZetherTxPayload{
+			txs_builder_zether_helper.TxsBuilderZetherTxPayloadBase{
+				"",
+				feeAddress,
+				64,
+				nil,
+			},
+			config_coins.NATIVE_ASSET_FULL,
+			commission,
+			commission,
+			&ZetherRingConfiguration{&ZetherSenderRingType{false, false, nil, 0}, &ZetherRecipientRingType{false, false, nil, 0}, nil},
+			0,
+			nil,
+			&wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, false}, false, 0, 0},
+			&wizard.WizardZetherPayloadExtraStakingReward{nil, commission},
+		})
+	}
+
+	txContext, err := builder.prebuild(forgerWallet, txData, pendingTxs, blkComplete.Height, blkComplete.PrevKernelHash, false, context.Background(), func(string) {})
 	if err != nil {
 		return nil, err
 	}
 
 	gui.GUI.Info("CreateForgingTransactions 2")
 
-	feesFinal := make([]*wizard.WizardTransactionFee, len(txData.Payloads))
-	for t, payload := range txData.Payloads {
-		feesFinal[t] = payload.Fee.WizardTransactionFee
-	}
-
 	var tx *transaction.Transaction
-	if tx, err = wizard.CreateZetherTx(transfers, emap, hasRollovers, ringsSenderMembers, ringsRecipientMembers, chainHeight, blkComplete.PrevKernelHash, publicKeyIndexes, feesFinal, context.Background(), func(string) {}); err != nil {
+	if tx, err = wizard.CreateZetherTx(txContext.Transfers, txContext.Emap, txContext.HasRollovers, txContext.ringsSenderMembers, txContext.ringsRecipientMembers, chainHeight, blkComplete.PrevKernelHash, txContext.PublicKeyIndexes, txContext.Fees, context.Background(), func(string) {}); err != nil {
 		return nil, err
 	}
 
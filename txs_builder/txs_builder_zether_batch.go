@@ -0,0 +1,291 @@
+package txs_builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage/assets"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload"
+	"pandora-pay/config"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/txs_builder/wizard"
+	"pandora-pay/wallet"
+	"strconv"
+	"strings"
+)
+
+const BATCH_TX_MAX_PAYLOADS = math.MaxUint8 //number of payloads is serialized as a byte
+
+var BATCH_TX_MAX_SIZE = config.BLOCK_MAX_SIZE / 4
+
+type TxBuilderBatchTransferRow struct {
+	Recipient string         `json:"recipient" msgpack:"recipient"` //address or contact name
+	Amount    float64        `json:"amount" msgpack:"amount"`
+	Asset     helpers.Base64 `json:"asset,omitempty" msgpack:"asset,omitempty"`
+	Message   string         `json:"message,omitempty" msgpack:"message,omitempty"`
+}
+
+type TxBuilderBatchTransferResult struct {
+	Row       int            `json:"row" msgpack:"row"`
+	Recipient string         `json:"recipient" msgpack:"recipient"`
+	TxId      helpers.Base64 `json:"txId,omitempty" msgpack:"txId,omitempty"`
+	Error     string         `json:"error,omitempty" msgpack:"error,omitempty"`
+}
+
+// ParseBatchTransferCSV reads the columns recipient, amount, asset (base64, empty for the native asset) and message. The header is optional
+func ParseBatchTransferCSV(data string) ([]*TxBuilderBatchTransferRow, error) {
+
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*TxBuilderBatchTransferRow, 0, len(records))
+	for i, record := range records {
+
+		if i == 0 && len(record) > 0 && strings.EqualFold(record[0], "recipient") {
+			continue
+		}
+		if len(record) < 2 || len(record) > 4 {
+			return nil, fmt.Errorf("Line %d must have between 2 and 4 columns", i+1)
+		}
+
+		row := &TxBuilderBatchTransferRow{Recipient: strings.TrimSpace(record[0])}
+		if row.Amount, err = strconv.ParseFloat(strings.TrimSpace(record[1]), 64); err != nil {
+			return nil, fmt.Errorf("Line %d has an invalid amount", i+1)
+		}
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			if row.Asset, err = base64.StdEncoding.DecodeString(strings.TrimSpace(record[2])); err != nil {
+				return nil, fmt.Errorf("Line %d has an invalid asset", i+1)
+			}
+		}
+		if len(record) > 3 {
+			row.Message = record[3]
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ReadBatchTransferFile reads the rows in JSON when the filename ends in .json, otherwise in CSV
+func ReadBatchTransferFile(filename string) ([]*TxBuilderBatchTransferRow, error) {
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(strings.ToLower(filename), ".json") {
+		rows := []*TxBuilderBatchTransferRow{}
+		if err = json.Unmarshal(data, &rows); err != nil {
+			return nil, err
+		}
+		return rows, nil
+	}
+
+	return ParseBatchTransferCSV(string(data))
+}
+
+// estimateZetherPayloadSize returns an upper estimation of the serialized size of a transfer payload
+func estimateZetherPayloadSize(ringSize int, assetId []byte) uint64 {
+
+	m := int(math.Log2(float64(ringSize)))
+
+	size := 2 + len(assetId)                                                             //PayloadScript + Burn + Asset
+	size += ringSize * (1 + cryptography.PublicKeySize + 3 + cryptography.SignatureSize) //registrations in the worst case
+	size += 1 + transaction_zether_payload.PAYLOAD_LIMIT                                 //dataVersion + data
+	size += ringSize*33*4 + 33 + 1                                                       //statement
+	size += 33*(21+m*8) + 32*10 + 2*m*32                                                 //proof
+	size += 2 * 33                                                                       //whispers
+	return uint64(size)
+}
+
+// resolveBatchTransferRows converts the rows into payloads. Contacts are replaced by their addresses
+func (builder *TxsBuilderType) resolveBatchTransferRows(senderWallet *wallet.Wallet, sender string, rows []*TxBuilderBatchTransferRow, ringSize int) ([]*TxBuilderCreateZetherTxPayload, error) {
+
+	payloads := make([]*TxBuilderCreateZetherTxPayload, len(rows))
+
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		asts := assets.NewAssets(reader)
+		for i, row := range rows {
+
+			recipient, contact := senderWallet.ResolveContact(row.Recipient)
+			if _, err = addresses.DecodeAddr(recipient); err != nil {
+				return fmt.Errorf("Row %d has an invalid recipient", i+1)
+			}
+
+			assetId := []byte(row.Asset)
+			if len(assetId) == 0 && contact != nil {
+				assetId = contact.DefaultAsset
+			}
+			if len(assetId) == 0 {
+				assetId = config_coins.NATIVE_ASSET_FULL
+			}
+			if len(assetId) != config_coins.ASSET_LENGTH {
+				return fmt.Errorf("Row %d has an invalid asset", i+1)
+			}
+
+			var ast *asset.Asset
+			if ast, err = asts.Get(string(assetId)); err != nil {
+				return
+			}
+			if ast == nil {
+				return fmt.Errorf("Row %d asset was not found", i+1)
+			}
+
+			var amount uint64
+			if amount, err = ast.ConvertToUnits(row.Amount); err != nil {
+				return fmt.Errorf("Row %d has an invalid amount: %s", i+1, err)
+			}
+			if amount == 0 {
+				return fmt.Errorf("Row %d amount is zero", i+1)
+			}
+
+			data := &wizard.WizardTransactionData{[]byte{}, false}
+			if len(row.Message) > 0 {
+				data = &wizard.WizardTransactionData{[]byte(row.Message), true}
+			}
+
+			fee := &wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, true}, false, 0, 0}
+			if !bytes.Equal(assetId, config_coins.NATIVE_ASSET_FULL) {
+				fee.Auto = true
+			}
+
+			payloads[i] = &TxBuilderCreateZetherTxPayload{}
+			payloads[i].Sender = sender
+			payloads[i].Recipient = recipient
+			payloads[i].RingSize = ringSize
+			payloads[i].Asset = assetId
+			payloads[i].Amount = amount
+			payloads[i].Data = data
+			payloads[i].Fee = fee
+		}
+
+		return
+	}); err != nil {
+		return nil, err
+	}
+
+	return payloads, nil
+}
+
+// splitBatchTransferPayloads groups the payloads in as many transactions as the size limits allow
+func splitBatchTransferPayloads(payloads []*TxBuilderCreateZetherTxPayload) [][]int {
+
+	groups := [][]int{}
+
+	var group []int
+	var size uint64
+
+	for i, payload := range payloads {
+		payloadSize := estimateZetherPayloadSize(payload.RingSize, payload.Asset)
+		if len(group) > 0 && (len(group) >= BATCH_TX_MAX_PAYLOADS || size+payloadSize > BATCH_TX_MAX_SIZE) {
+			groups = append(groups, group)
+			group, size = nil, 0
+		}
+		group = append(group, i)
+		size += payloadSize
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// CreateZetherBatchTx pays all the rows from the same sender using multi-payload transactions.
+// The transactions are created one after another, each of them spending the balance left by the previous ones.
+// In case a transaction fails, the remaining rows are not paid
+func (builder *TxsBuilderType) CreateZetherBatchTx(senderWallet *wallet.Wallet, sender string, rows []*TxBuilderBatchTransferRow, ringSize int, propagateTx, awaitAnswer, awaitBroadcast bool, ctx context.Context, statusCallback func(string)) ([]*TxBuilderBatchTransferResult, []*transaction.Transaction, error) {
+
+	if len(rows) == 0 {
+		return nil, nil, errors.New("There are no rows to be paid")
+	}
+	if ringSize == 0 {
+		ringSize = 32
+	}
+	if ringSize < 2 || ringSize > 256 || !crypto.IsPowerOf2(ringSize) {
+		return nil, nil, errors.New("ring size is not a power of 2")
+	}
+
+	payloads, err := builder.resolveBatchTransferRows(senderWallet, sender, rows, ringSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]*TxBuilderBatchTransferResult, len(rows))
+	for i, payload := range payloads {
+		results[i] = &TxBuilderBatchTransferResult{i + 1, payload.Recipient, nil, ""}
+	}
+
+	groups := splitBatchTransferPayloads(payloads)
+	txs := make([]*transaction.Transaction, 0, len(groups))
+
+	var senderRing []string
+
+	for g, group := range groups {
+
+		statusCallback(fmt.Sprintf("Creating transaction %d of %d with %d payloads", g+1, len(groups), len(group)))
+
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: make([]*TxBuilderCreateZetherTxPayload, len(group)),
+		}
+		for i, index := range group {
+			txData.Payloads[i] = payloads[index]
+		}
+
+		//the payloads of the same transaction share the sender ring. The next transactions reuse the sender ring of the first one, otherwise the intersection of the rings would reveal the sender
+		if senderRing != nil {
+			txData.Payloads[0].RingConfiguration = &ZetherRingConfiguration{&ZetherSenderRingType{false, false, senderRing, 0}, &ZetherRecipientRingType{false, false, nil, 0}, nil}
+		}
+
+		//the transactions created before must be taken into account even if they were not propagated
+		pendingTxs := builder.mempool.Txs.GetTxsOnlyList()
+		if !propagateTx {
+			pendingTxs = append(pendingTxs, txs...)
+		}
+
+		var tx *transaction.Transaction
+		var txContext *TxBuilderZetherTxContext
+		if tx, txContext, err = builder.createZetherTx(senderWallet, txData, pendingTxs, propagateTx, awaitAnswer, awaitBroadcast, ctx, statusCallback); err != nil {
+			for _, nextGroup := range groups[g:] {
+				for _, index := range nextGroup {
+					results[index].Error = err.Error()
+				}
+			}
+			return results, txs, err
+		}
+
+		for _, index := range group {
+			results[index].TxId = tx.Bloom.Hash
+		}
+		txs = append(txs, tx)
+
+		if senderRing == nil {
+			senderRing = txContext.RingsSenders[0]
+		}
+	}
+
+	return results, txs, nil
+}
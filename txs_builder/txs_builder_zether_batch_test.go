@@ -0,0 +1,134 @@
+package txs_builder
+
+import (
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseBatchTransferCSV(t *testing.T) {
+
+	assetId := helpers.RandomBytes(config_coins.ASSET_LENGTH)
+	assetBase64 := base64.StdEncoding.EncodeToString(assetId)
+
+	for _, test := range []struct {
+		name  string
+		data  string
+		rows  []*TxBuilderBatchTransferRow
+		valid bool
+	}{
+		{"header", "recipient,amount,asset,message\nalice,1.5\n", []*TxBuilderBatchTransferRow{{"alice", 1.5, nil, ""}}, true},
+		{"no header", "alice, 1.5\nbob,2\n", []*TxBuilderBatchTransferRow{{"alice", 1.5, nil, ""}, {"bob", 2, nil, ""}}, true},
+		{"header only", "Recipient,Amount\n", []*TxBuilderBatchTransferRow{}, true},
+		{"asset", "alice,1," + assetBase64 + "\n", []*TxBuilderBatchTransferRow{{"alice", 1, assetId, ""}}, true},
+		{"empty asset", "alice,1,,invoice 12\n", []*TxBuilderBatchTransferRow{{"alice", 1, nil, "invoice 12"}}, true},
+		{"quoted message", "alice,1,," + `"paid, ""thanks"""` + "\n", []*TxBuilderBatchTransferRow{{"alice", 1, nil, `paid, "thanks"`}}, true},
+		{"multiline message", "alice,1,,\"line1\nline2\"\n", []*TxBuilderBatchTransferRow{{"alice", 1, nil, "line1\nline2"}}, true},
+		{"one column", "alice\n", nil, false},
+		{"five columns", "alice,1,,message,extra\n", nil, false},
+		{"invalid amount", "alice,one\n", nil, false},
+		{"header not first", "alice,1\nrecipient,amount\n", nil, false},
+		{"invalid asset", "alice,1,not-base64!\n", nil, false},
+		{"unclosed quote", "alice,1,,\"message\n", nil, false},
+	} {
+		rows, err := ParseBatchTransferCSV(test.data)
+		if test.valid {
+			assert.NoError(t, err, test.name)
+			assert.Equal(t, test.rows, rows, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+			assert.Nil(t, rows, test.name)
+		}
+	}
+}
+
+func TestReadBatchTransferFile(t *testing.T) {
+
+	dir := t.TempDir()
+
+	assetId := helpers.RandomBytes(config_coins.ASSET_LENGTH)
+	expected := []*TxBuilderBatchTransferRow{{"alice", 1.5, nil, ""}, {"bob", 2, assetId, `paid, "thanks"`}}
+
+	jsonFile := filepath.Join(dir, "rows.JSON")
+	assert.NoError(t, os.WriteFile(jsonFile, []byte(`[{"recipient":"alice","amount":1.5},{"recipient":"bob","amount":2,"asset":"`+base64.StdEncoding.EncodeToString(assetId)+`","message":"paid, \"thanks\""}]`), 0644))
+	rows, err := ReadBatchTransferFile(jsonFile)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, rows)
+
+	csvFile := filepath.Join(dir, "rows.csv")
+	assert.NoError(t, os.WriteFile(csvFile, []byte("alice,1.5\nbob,2,"+base64.StdEncoding.EncodeToString(assetId)+`,"paid, ""thanks"""`+"\n"), 0644))
+	rows, err = ReadBatchTransferFile(csvFile)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, rows)
+
+	invalidFile := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalidFile, []byte(`[{"recipient":"alice","amount":"one"}]`), 0644))
+	_, err = ReadBatchTransferFile(invalidFile)
+	assert.Error(t, err)
+
+	_, err = ReadBatchTransferFile(filepath.Join(dir, "missing.csv"))
+	assert.Error(t, err)
+}
+
+func TestSplitBatchTransferPayloads(t *testing.T) {
+
+	maxSize := BATCH_TX_MAX_SIZE
+	defer func() {
+		BATCH_TX_MAX_SIZE = maxSize
+	}()
+
+	createPayloads := func(count, ringSize int) []*TxBuilderCreateZetherTxPayload {
+		payloads := make([]*TxBuilderCreateZetherTxPayload, count)
+		for i := range payloads {
+			payloads[i] = &TxBuilderCreateZetherTxPayload{}
+			payloads[i].RingSize = ringSize
+			payloads[i].Asset = config_coins.NATIVE_ASSET_FULL
+		}
+		return payloads
+	}
+
+	groupsLengths := func(groups [][]int) []int {
+		lengths := make([]int, len(groups))
+		for i, group := range groups {
+			lengths[i] = len(group)
+		}
+		return lengths
+	}
+
+	assert.Len(t, splitBatchTransferPayloads(nil), 0)
+
+	//the number of payloads is limited by BATCH_TX_MAX_PAYLOADS
+	BATCH_TX_MAX_SIZE = 1 << 40
+	groups := splitBatchTransferPayloads(createPayloads(2*BATCH_TX_MAX_PAYLOADS+10, 2))
+	assert.Equal(t, []int{BATCH_TX_MAX_PAYLOADS, BATCH_TX_MAX_PAYLOADS, 10}, groupsLengths(groups))
+
+	//the payloads keep their order
+	index := 0
+	for _, group := range groups {
+		for _, i := range group {
+			assert.Equal(t, index, i)
+			index++
+		}
+	}
+
+	size := estimateZetherPayloadSize(32, config_coins.NATIVE_ASSET_FULL)
+
+	BATCH_TX_MAX_SIZE = 3 * size
+	assert.Equal(t, []int{3, 3, 1}, groupsLengths(splitBatchTransferPayloads(createPayloads(7, 32))))
+
+	BATCH_TX_MAX_SIZE = 3*size - 1
+	assert.Equal(t, []int{2, 2, 2, 1}, groupsLengths(splitBatchTransferPayloads(createPayloads(7, 32))))
+
+	//a payload larger than the limit gets its own transaction
+	BATCH_TX_MAX_SIZE = size - 1
+	assert.Equal(t, []int{1, 1, 1}, groupsLengths(splitBatchTransferPayloads(createPayloads(3, 32))))
+
+	//the larger rings fill the transactions faster
+	payloads := append(createPayloads(2, 32), createPayloads(2, 256)...)
+	BATCH_TX_MAX_SIZE = 2*size + estimateZetherPayloadSize(256, config_coins.NATIVE_ASSET_FULL)
+	assert.Equal(t, [][]int{{0, 1, 2}, {3}}, splitBatchTransferPayloads(payloads))
+}
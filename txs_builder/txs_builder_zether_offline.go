@@ -0,0 +1,274 @@
+package txs_builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/txs_builder/wizard"
+	"pandora-pay/txs_validator"
+	"pandora-pay/wallet"
+	"pandora-pay/wallet/wallet_address"
+	"strconv"
+)
+
+const TX_BUILDER_ZETHER_TX_CONTEXT_VERSION = uint64(0)
+
+// TxBuilderZetherTxContext contains all the chain data required to create the zether proofs without having access to the blockchain.
+// It doesn't contain any private key when it was prepared offline
+type TxBuilderZetherTxContext struct {
+	Version                  uint64                                        `json:"version" msgpack:"version"`
+	Transfers                []*wizard.WizardZetherTransfer                `json:"transfers" msgpack:"transfers"`
+	Emap                     map[string]map[string][]byte                  `json:"emap" msgpack:"emap"`
+	HasRollovers             map[string]bool                               `json:"hasRollovers" msgpack:"hasRollovers"`
+	RingsSenders             [][]string                                    `json:"ringsSenders" msgpack:"ringsSenders"`
+	RingsRecipients          [][]string                                    `json:"ringsRecipients" msgpack:"ringsRecipients"`
+	PublicKeyIndexes         map[string]*wizard.WizardZetherPublicKeyIndex `json:"publicKeyIndexes" msgpack:"publicKeyIndexes"`
+	Fees                     []*wizard.WizardTransactionFee                `json:"fees" msgpack:"fees"`
+	Senders                  []string                                      `json:"senders" msgpack:"senders"`
+	SendersEncryptedBalances [][]byte                                      `json:"sendersEncryptedBalances" msgpack:"sendersEncryptedBalances"`
+	SendersSpendPublicKeys   [][]byte                                      `json:"sendersSpendPublicKeys" msgpack:"sendersSpendPublicKeys"`
+	DecryptedBalances        []uint64                                      `json:"decryptedBalances" msgpack:"decryptedBalances"`
+	ChainHeight              uint64                                        `json:"chainHeight" msgpack:"chainHeight"`
+	ChainKernelHash          []byte                                        `json:"chainKernelHash" msgpack:"chainKernelHash"`
+	ringsSenderMembers       [][]*bn256.G1
+	ringsRecipientMembers    [][]*bn256.G1
+}
+
+func (txContext *TxBuilderZetherTxContext) validate() error {
+
+	if txContext.Version != TX_BUILDER_ZETHER_TX_CONTEXT_VERSION {
+		return errors.New("Context version is not supported")
+	}
+	if txContext.ChainHeight == 0 || len(txContext.ChainKernelHash) == 0 {
+		return errors.New("Context chain height is missing")
+	}
+
+	count := len(txContext.Transfers)
+	if count == 0 {
+		return errors.New("Context has no transfers")
+	}
+	if len(txContext.RingsSenders) != count || len(txContext.RingsRecipients) != count || len(txContext.Fees) != count || len(txContext.Senders) != count ||
+		len(txContext.SendersEncryptedBalances) != count || len(txContext.SendersSpendPublicKeys) != count || len(txContext.DecryptedBalances) != count {
+		return errors.New("Context is corrupted")
+	}
+
+	for t, transfer := range txContext.Transfers {
+		if transfer.PayloadExtra != nil {
+			return errors.New("Offline transactions support only transfers")
+		}
+		if len(txContext.RingsSenders[t]) == 0 || txContext.RingsSenders[t][0] != txContext.Senders[t] {
+			return errors.New("Context sender is not the first ring member")
+		}
+	}
+
+	return nil
+}
+
+// initRings decodes the ring members as they are not serialized
+func (txContext *TxBuilderZetherTxContext) initRings() (err error) {
+
+	decodeRing := func(ring []string) ([]*bn256.G1, error) {
+		out := make([]*bn256.G1, len(ring))
+		for i, member := range ring {
+			addr, err := addresses.DecodeAddr(member)
+			if err != nil {
+				return nil, err
+			}
+			p, err := addr.GetPoint()
+			if err != nil {
+				return nil, err
+			}
+			out[i] = p.G1()
+		}
+		return out, nil
+	}
+
+	txContext.ringsSenderMembers = make([][]*bn256.G1, len(txContext.Transfers))
+	txContext.ringsRecipientMembers = make([][]*bn256.G1, len(txContext.Transfers))
+	for t := range txContext.Transfers {
+		if txContext.ringsSenderMembers[t], err = decodeRing(txContext.RingsSenders[t]); err != nil {
+			return
+		}
+		if txContext.ringsRecipientMembers[t], err = decodeRing(txContext.RingsRecipients[t]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (txContext *TxBuilderZetherTxContext) Serialize() ([]byte, error) {
+	return msgpack.Marshal(txContext)
+}
+
+func DeserializeZetherTxContext(data []byte) (*TxBuilderZetherTxContext, error) {
+	txContext := &TxBuilderZetherTxContext{}
+	if err := msgpack.Unmarshal(data, txContext); err != nil {
+		return nil, err
+	}
+	return txContext, nil
+}
+
+// PrepareZetherTxContext runs on the online node. It selects the rings and collects the balances, the registrations and the fee liquidity without using any private key
+func (builder *TxsBuilderType) PrepareZetherTxContext(senderWallet *wallet.Wallet, txData *TxBuilderCreateZetherTxData, ctx context.Context, statusCallback func(string)) (*TxBuilderZetherTxContext, error) {
+
+	pendingTxs := builder.mempool.Txs.GetTxsOnlyList()
+
+	builder.lock.Lock()
+	defer builder.lock.Unlock()
+
+	return builder.prebuild(senderWallet, txData, pendingTxs, 0, nil, true, ctx, statusCallback)
+}
+
+// SignZetherTxContext runs on the offline node. It decrypts the balances and creates the proofs using the private keys of the senderWallet
+func (builder *TxsBuilderType) SignZetherTxContext(senderWallet *wallet.Wallet, txContext *TxBuilderZetherTxContext, ctx context.Context, statusCallback func(string)) (*transaction.Transaction, error) {
+
+	if err := txContext.validate(); err != nil {
+		return nil, err
+	}
+
+	builder.lock.Lock()
+	defer builder.lock.Unlock()
+
+	var err error
+
+	sendersWalletAddresses := make([]*wallet_address.WalletAddress, len(txContext.Transfers))
+	for t, transfer := range txContext.Transfers {
+
+		addr, err := senderWallet.GetWalletAddressByEncodedAddress(txContext.Senders[t], true)
+		if err != nil {
+			return nil, err
+		}
+		if addr.PrivateKey == nil {
+			return nil, errors.New("Can't be used for transactions as the private key is missing")
+		}
+		if addr.WatchOnly {
+			return nil, fmt.Errorf("Address %s is watch-only and can't spend", txContext.Senders[t])
+		}
+
+		transfer.SenderPrivateKey = addr.PrivateKey.Key
+		if transfer.SenderSpendRequired {
+			//the co-signers requiring a confirmation code refuse to sign offline transactions
+			if err = setSenderSpendKey(transfer, addr, txContext.SendersSpendPublicKeys[t], ""); err != nil {
+				return nil, err
+			}
+		}
+		sendersWalletAddresses[t] = addr
+	}
+
+	if err = builder.decryptSendersBalances(txContext, sendersWalletAddresses, ctx, statusCallback); err != nil {
+		return nil, err
+	}
+
+	if err = txContext.initRings(); err != nil {
+		return nil, err
+	}
+
+	var tx *transaction.Transaction
+	if tx, err = wizard.CreateZetherTx(txContext.Transfers, txContext.Emap, txContext.HasRollovers, txContext.ringsSenderMembers, txContext.ringsRecipientMembers, txContext.ChainHeight-1, txContext.ChainKernelHash, txContext.PublicKeyIndexes, txContext.Fees, ctx, statusCallback); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// checkZetherPayloadBalances verifies that the encrypted balances of the senders ring used by the payload are the balances stored in the chain
+func checkZetherPayloadBalances(payload *transaction_zether_payload.TransactionZetherPayload, publicKeyList [][]byte, dataStorage *data_storage.DataStorage) error {
+
+	accs, err := dataStorage.AccsCollection.GetMap(payload.Asset)
+	if err != nil {
+		return err
+	}
+
+	for i, publicKey := range publicKeyList {
+		if (i%2 == 0) != payload.Parity { //recipient
+			continue
+		}
+
+		acc, err := accs.Get(string(publicKey))
+		if err != nil {
+			return err
+		}
+		if acc == nil {
+			continue
+		}
+
+		balance := acc.GetBalance().Add(crypto.ConstructElGamal(payload.Statement.C[i], payload.Statement.D))
+		if payload.Statement.CLn[i].String() != balance.Left.String() || payload.Statement.CRn[i].String() != balance.Right.String() {
+			return errors.New("the encrypted balances of the ring changed")
+		}
+	}
+
+	return nil
+}
+
+// CheckZetherTxChainContext verifies that the chain and the encrypted balances used to create the zether transaction were not changed in the meanwhile and returns the current chain height
+func CheckZetherTxChainContext(tx *transaction.Transaction) (chainHeight uint64, err error) {
+
+	if err = tx.BloomAll(); err != nil {
+		return
+	}
+
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		chainHeight, _ = binary.Uvarint(reader.Get("chainHeight"))
+		if tx.Version != transaction_type.TX_ZETHER {
+			return nil
+		}
+
+		txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+		if txBase.ChainHeight >= chainHeight { //the node is behind, the mempool will verify it later
+			return nil
+		}
+		if !bytes.Equal(reader.Get("blockKernelHash_ByHeight"+strconv.FormatUint(txBase.ChainHeight, 10)), txBase.ChainKernelHash) {
+			return fmt.Errorf("Transaction was created on a stale context at height %d. It needs to be prepared again", txBase.ChainHeight)
+		}
+
+		//the payloads are included one by one as the next payloads see the balances updated by the previous ones
+		dataStorage := data_storage.NewDataStorage(reader)
+		for t, payload := range txBase.Payloads {
+			if payload.PayloadScript != transaction_zether_payload_script.SCRIPT_STAKING_REWARD {
+				if err := checkZetherPayloadBalances(payload, txBase.Bloom.PublicKeyLists[t], dataStorage); err != nil {
+					return fmt.Errorf("Transaction was created on a stale context at height %d as %s. It needs to be prepared again", txBase.ChainHeight, err.Error())
+				}
+			}
+			if err := payload.IncludePayload(tx.Bloom.Hash, byte(t), txBase.Bloom.PublicKeyLists[t], chainHeight, dataStorage); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// BroadcastZetherTx broadcasts a transaction signed offline after checking that its context is not stale
+func (builder *TxsBuilderType) BroadcastZetherTx(tx *transaction.Transaction, awaitAnswer, awaitBroadcast bool, ctx context.Context) error {
+
+	if err := txs_validator.TxsValidator.ValidateTx(tx); err != nil {
+		return err
+	}
+
+	chainHeight, err := CheckZetherTxChainContext(tx)
+	if err != nil {
+		return err
+	}
+
+	return builder.mempool.AddTxToMempool(tx, chainHeight, true, awaitAnswer, awaitBroadcast, advanced_connection_types.UUID_ALL, ctx)
+}
@@ -0,0 +1,328 @@
+package txs_builder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage/accounts"
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/registrations"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/config"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/wallet"
+	"strconv"
+)
+
+type ZetherRingStrategyType string
+
+const (
+	ZETHER_RING_STRATEGY_RANDOM          ZetherRingStrategyType = "random"
+	ZETHER_RING_STRATEGY_RECENT_ACTIVITY ZetherRingStrategyType = "recent-activity"
+)
+
+const (
+	ZETHER_RING_STRATEGY_MIN_WEIGHT     = 0.05 //accounts are never excluded entirely by the activity weights
+	ZETHER_RING_STRATEGY_MAX_ATTEMPTS   = 64   //after that many rejections, the candidate is accepted to avoid getting stuck
+	ZETHER_RING_STRATEGY_RECENT_BLOCKS  = uint64(1000)
+	ZETHER_RING_STRATEGY_HISTORY_TXS    = 10 //recent txs of the sender whose ring members are avoided
+	ZETHER_RING_STRATEGY_ASSET_LOOKBACK = 5  //recent txs of a candidate checked for the asset
+)
+
+type ZetherRingStrategyConfiguration struct {
+	Type               ZetherRingStrategyType `json:"type" msgpack:"type"`
+	RecentBlocks       uint64                 `json:"recentBlocks,omitempty" msgpack:"recentBlocks,omitempty"` //the activity weight halves every RecentBlocks
+	AvoidReusedMembers bool                   `json:"avoidReusedMembers,omitempty" msgpack:"avoidReusedMembers,omitempty"`
+	MatchAssetActivity bool                   `json:"matchAssetActivity,omitempty" msgpack:"matchAssetActivity,omitempty"`
+}
+
+// ZetherRingStrategy weighs the candidates picked at random as decoys
+type ZetherRingStrategy interface {
+	// Weight returns the probability in [0,1] to accept the account as a ring member
+	Weight(publicKey []byte) (float64, error)
+}
+
+type zetherRingSampler struct {
+	rng        *rand.Rand
+	strategies []ZetherRingStrategy
+}
+
+func newZetherRingSampler(rng *rand.Rand, strategies ...ZetherRingStrategy) *zetherRingSampler {
+	return &zetherRingSampler{rng, strategies}
+}
+
+// accept multiplies the weights of all strategies and draws against the result
+func (sampler *zetherRingSampler) accept(publicKey []byte) (bool, error) {
+
+	weight := 1.0
+	for _, strategy := range sampler.strategies {
+		w, err := strategy.Weight(publicKey)
+		if err != nil {
+			return false, err
+		}
+		weight *= w
+	}
+
+	if weight >= 1 {
+		return true, nil
+	}
+	return sampler.rng.Float64() < weight, nil
+}
+
+func (sampler *zetherRingSampler) getRandomAccount(accs *accounts.Accounts, regs *registrations.Registrations) (addr *addresses.Address, acc *account.Account, reg *registration.Registration, err error) {
+
+	if accs.Count == 0 {
+		return nil, nil, nil, errors.New("Error getting any random account")
+	}
+
+	for attempt := 0; ; attempt++ {
+
+		if acc, err = accs.GetByIndex(sampler.rng.Uint64() % accs.Count); err != nil {
+			return nil, nil, nil, err
+		}
+		if acc == nil {
+			return nil, nil, nil, errors.New("Error getting any random account")
+		}
+
+		if attempt < ZETHER_RING_STRATEGY_MAX_ATTEMPTS {
+			var accepted bool
+			if accepted, err = sampler.accept(acc.Key); err != nil {
+				return nil, nil, nil, err
+			}
+			if !accepted {
+				continue
+			}
+		}
+		break
+	}
+
+	if reg, err = regs.Get(string(acc.Key)); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if addr, err = addresses.CreateAddr(acc.Key, false, nil, nil, nil, 0, nil); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return
+}
+
+// getAccountRecentTxs returns the hashes of the last count transactions of the account. It requires the extended info
+func getAccountRecentTxs(reader store_db_interface.StoreDBTransactionInterface, publicKey []byte, count int) ([][]byte, error) {
+
+	data := reader.Get("addrTxsCount:" + string(publicKey))
+	if data == nil {
+		return nil, nil
+	}
+
+	total, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([][]byte, 0, count)
+	for i := total; i > 0 && len(hashes) < count; i-- {
+		hash := reader.Get("addrTx:" + string(publicKey) + ":" + strconv.FormatUint(i-1, 10))
+		if hash == nil {
+			return nil, errors.New("addrTx: was not found")
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, nil
+}
+
+func getZetherTx(reader store_db_interface.StoreDBTransactionInterface, hash []byte) (*transaction_zether.TransactionZether, error) {
+
+	data := reader.Get("tx:" + string(hash))
+	if data == nil {
+		return nil, errors.New("Tx was not found in the storage")
+	}
+
+	tx := &transaction.Transaction{}
+	if err := tx.Deserialize(advanced_buffers.NewBufferReader(data)); err != nil {
+		return nil, err
+	}
+	if tx.Version != transaction_type.TX_ZETHER {
+		return nil, nil
+	}
+	if err := tx.BloomAll(); err != nil {
+		return nil, err
+	}
+
+	return tx.TransactionBaseInterface.(*transaction_zether.TransactionZether), nil
+}
+
+// recentActivityWeight halves the weight every recentBlocks since the last activity
+func recentActivityWeight(chainHeight, lastHeight, recentBlocks uint64) float64 {
+
+	if lastHeight >= chainHeight {
+		return 1
+	}
+
+	weight := math.Pow(0.5, float64(chainHeight-lastHeight)/float64(recentBlocks))
+	if weight < ZETHER_RING_STRATEGY_MIN_WEIGHT {
+		return ZETHER_RING_STRATEGY_MIN_WEIGHT
+	}
+	return weight
+}
+
+// zetherRingStrategyRecentActivity prefers the accounts which were active recently, like the real senders and recipients
+type zetherRingStrategyRecentActivity struct {
+	reader       store_db_interface.StoreDBTransactionInterface
+	chainHeight  uint64
+	recentBlocks uint64
+}
+
+func (strategy *zetherRingStrategyRecentActivity) Weight(publicKey []byte) (float64, error) {
+
+	hashes, err := getAccountRecentTxs(strategy.reader, publicKey, 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(hashes) == 0 {
+		return ZETHER_RING_STRATEGY_MIN_WEIGHT, nil
+	}
+
+	lastHeight, _ := binary.Uvarint(strategy.reader.Get("txBlock:" + string(hashes[0])))
+	return recentActivityWeight(strategy.chainHeight, lastHeight, strategy.recentBlocks), nil
+}
+
+// zetherRingStrategyAvoidReused rejects the members already used in the recent rings of the same user
+type zetherRingStrategyAvoidReused struct {
+	used map[string]bool
+}
+
+func (strategy *zetherRingStrategyAvoidReused) Weight(publicKey []byte) (float64, error) {
+	if strategy.used[string(publicKey)] {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// zetherRingStrategyMatchAsset prefers the accounts which recently transacted the same asset
+type zetherRingStrategyMatchAsset struct {
+	reader store_db_interface.StoreDBTransactionInterface
+	asset  []byte
+}
+
+func (strategy *zetherRingStrategyMatchAsset) Weight(publicKey []byte) (float64, error) {
+
+	hashes, err := getAccountRecentTxs(strategy.reader, publicKey, ZETHER_RING_STRATEGY_ASSET_LOOKBACK)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, hash := range hashes {
+
+		txBase, err := getZetherTx(strategy.reader, hash)
+		if err != nil {
+			return 0, err
+		}
+		if txBase == nil {
+			continue
+		}
+
+		for t, payload := range txBase.Payloads {
+			if !bytes.Equal(payload.Asset, strategy.asset) {
+				continue
+			}
+			for _, member := range txBase.Bloom.PublicKeyLists[t] {
+				if bytes.Equal(member, publicKey) {
+					return 1, nil
+				}
+			}
+		}
+	}
+
+	return ZETHER_RING_STRATEGY_MIN_WEIGHT, nil
+}
+
+// getRecentRingMembers returns the ring members used in the last transactions of the wallet address
+func (builder *TxsBuilderType) getRecentRingMembers(reader store_db_interface.StoreDBTransactionInterface, senderWallet *wallet.Wallet, publicKey []byte) (map[string]bool, error) {
+
+	used := make(map[string]bool)
+
+	historyTxs, _, err := senderWallet.GetHistory(publicKey, 0, ZETHER_RING_STRATEGY_HISTORY_TXS)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, historyTx := range historyTxs {
+
+		txBase, err := getZetherTx(reader, historyTx.TxHash)
+		if err != nil {
+			return nil, err
+		}
+		if txBase == nil {
+			continue
+		}
+
+		for _, list := range txBase.Bloom.PublicKeyLists {
+			for _, member := range list {
+				used[string(member)] = true
+			}
+		}
+	}
+
+	return used, nil
+}
+
+// createZetherRingSampler returns the sampler used to pick the decoys of the payload
+func (builder *TxsBuilderType) createZetherRingSampler(payload *TxBuilderCreateZetherTxPayload, reader store_db_interface.StoreDBTransactionInterface) (*zetherRingSampler, error) {
+
+	sampler := newZetherRingSampler(rand.New(rand.NewSource(rand.Int63())))
+
+	if payload.RingConfiguration == nil || payload.RingConfiguration.Strategy == nil {
+		return sampler, nil
+	}
+	strategy := payload.RingConfiguration.Strategy
+
+	switch strategy.Type {
+	case "", ZETHER_RING_STRATEGY_RANDOM:
+	case ZETHER_RING_STRATEGY_RECENT_ACTIVITY:
+	default:
+		return nil, errors.New("Invalid ring strategy")
+	}
+
+	//the activity of the accounts is stored only by the nodes providing the extended info
+	if !config.NODE_PROVIDE_EXTENDED_INFO_APP {
+		return sampler, nil
+	}
+
+	if strategy.Type == ZETHER_RING_STRATEGY_RECENT_ACTIVITY {
+		recentBlocks := strategy.RecentBlocks
+		if recentBlocks == 0 {
+			recentBlocks = ZETHER_RING_STRATEGY_RECENT_BLOCKS
+		}
+		chainHeight, _ := binary.Uvarint(reader.Get("chainHeight"))
+		sampler.strategies = append(sampler.strategies, &zetherRingStrategyRecentActivity{reader, chainHeight, recentBlocks})
+	}
+
+	if strategy.AvoidReusedMembers && payload.Sender != "" {
+		addr, err := addresses.DecodeAddr(payload.Sender)
+		if err != nil {
+			return nil, err
+		}
+		if senderWallet := builder.wallet.GetWalletByPublicKey(addr.PublicKey); senderWallet != nil {
+			used, err := builder.getRecentRingMembers(reader, senderWallet, addr.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+			sampler.strategies = append(sampler.strategies, &zetherRingStrategyAvoidReused{used})
+		}
+	}
+
+	if strategy.MatchAssetActivity {
+		sampler.strategies = append(sampler.strategies, &zetherRingStrategyMatchAsset{reader, payload.Asset})
+	}
+
+	return sampler, nil
+}
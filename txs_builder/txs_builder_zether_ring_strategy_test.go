@@ -0,0 +1,84 @@
+package txs_builder
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+type testZetherRingStrategy struct {
+	weights map[string]float64
+}
+
+func (strategy *testZetherRingStrategy) Weight(publicKey []byte) (float64, error) {
+	if weight, ok := strategy.weights[string(publicKey)]; ok {
+		return weight, nil
+	}
+	return 1, nil
+}
+
+func TestRecentActivityWeight(t *testing.T) {
+
+	assert.Equal(t, 1.0, recentActivityWeight(100, 100, 10))
+	assert.Equal(t, 1.0, recentActivityWeight(100, 150, 10))
+	assert.InDelta(t, 0.5, recentActivityWeight(100, 90, 10), 1e-9)
+	assert.InDelta(t, 0.25, recentActivityWeight(100, 80, 10), 1e-9)
+	assert.Equal(t, ZETHER_RING_STRATEGY_MIN_WEIGHT, recentActivityWeight(1000, 0, 10))
+
+	assert.Greater(t, recentActivityWeight(1000, 990, 100), recentActivityWeight(1000, 900, 100))
+}
+
+func TestZetherRingSamplerAccept(t *testing.T) {
+
+	strategy := &testZetherRingStrategy{map[string]float64{
+		"never":  0,
+		"always": 1,
+		"half":   0.5,
+	}}
+
+	sampler := newZetherRingSampler(rand.New(rand.NewSource(1)), strategy, &zetherRingStrategyAvoidReused{map[string]bool{"reused": true}})
+
+	for i := 0; i < 100; i++ {
+		accepted, err := sampler.accept([]byte("never"))
+		assert.NoError(t, err)
+		assert.False(t, accepted)
+
+		accepted, err = sampler.accept([]byte("reused"))
+		assert.NoError(t, err)
+		assert.False(t, accepted)
+
+		accepted, err = sampler.accept([]byte("always"))
+		assert.NoError(t, err)
+		assert.True(t, accepted)
+	}
+
+	count := 0
+	for i := 0; i < 10000; i++ {
+		accepted, err := sampler.accept([]byte("half"))
+		assert.NoError(t, err)
+		if accepted {
+			count++
+		}
+	}
+	assert.InDelta(t, 5000, count, 300)
+}
+
+func TestZetherRingSamplerDeterministic(t *testing.T) {
+
+	strategy := &testZetherRingStrategy{map[string]float64{"a": 0.3, "b": 0.6, "c": 0.9}}
+
+	draw := func(seed int64) (out []bool) {
+		sampler := newZetherRingSampler(rand.New(rand.NewSource(seed)), strategy)
+		for i := 0; i < 50; i++ {
+			for _, key := range []string{"a", "b", "c"} {
+				accepted, err := sampler.accept([]byte(key))
+				assert.NoError(t, err)
+				out = append(out, accepted)
+			}
+		}
+		return
+	}
+
+	assert.Equal(t, draw(42), draw(42))
+	assert.NotEqual(t, draw(42), draw(43))
+}
@@ -20,8 +20,9 @@ type ZetherRecipientRingType struct {
 }
 
 type ZetherRingConfiguration struct {
-	SenderRingType    *ZetherSenderRingType    `json:"senderRingType" msgpack:"senderRingType"`
-	RecipientRingType *ZetherRecipientRingType `json:"recipientRingType" msgpack:"recipientRingType"`
+	SenderRingType    *ZetherSenderRingType            `json:"senderRingType" msgpack:"senderRingType"`
+	RecipientRingType *ZetherRecipientRingType         `json:"recipientRingType" msgpack:"recipientRingType"`
+	Strategy          *ZetherRingStrategyConfiguration `json:"strategy,omitempty" msgpack:"strategy,omitempty"` //nil picks the decoys uniformly at random
 }
 
 type TxBuilderCreateZetherTxPayload struct {
@@ -37,5 +38,6 @@ type TxBuilderCreateZetherTxPayload struct {
 }
 
 type TxBuilderCreateZetherTxData struct {
-	Payloads []*TxBuilderCreateZetherTxPayload `json:"payloads" msgpack:"payloads"`
+	Payloads              []*TxBuilderCreateZetherTxPayload `json:"payloads" msgpack:"payloads"`
+	SpendConfirmationCode string                            `json:"spendConfirmationCode,omitempty" msgpack:"spendConfirmationCode,omitempty"` //sent to the co-signers of the senders
 }
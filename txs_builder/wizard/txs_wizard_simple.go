@@ -10,6 +10,7 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_type"
 	"pandora-pay/cryptography"
 	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
 )
 
 func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCallback func(string)) (tx2 *transaction.Transaction, err error) {
@@ -57,12 +58,19 @@ func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCal
 		}
 		txBase.TxScript = transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT
 		transfer.Fee = &WizardTransactionFee{0, 0, 0, false}
+	case *WizardTxSimpleExtraSlashingEvidence:
+		extra := &transaction_simple_extra.TransactionSimpleExtraSlashingEvidence{}
+		if err = extra.Deserialize(advanced_buffers.NewBufferReader(append(helpers.CloneBytes(txExtra.Block1), txExtra.Block2...))); err != nil {
+			return
+		}
+		txBase.Extra = extra
+		txBase.TxScript = transaction_simple.SCRIPT_SLASHING_EVIDENCE
 	}
 
 	var privateKey *addresses.PrivateKey
 
 	switch txBase.TxScript {
-	case transaction_simple.SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY:
+	case transaction_simple.SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY, transaction_simple.SCRIPT_SLASHING_EVIDENCE:
 		if privateKey, err = addresses.NewPrivateKey(transfer.Key); err != nil {
 			return nil, err
 		}
@@ -23,6 +23,12 @@ type WizardTxSimpleExtraResolutionConditionalPayment struct {
 	Signatures          [][]byte `json:"signatures" msgpack:"signatures"`
 }
 
+type WizardTxSimpleExtraSlashingEvidence struct {
+	WizardTxSimpleExtra `json:"-"  msgpack:"-"`
+	Block1              []byte `json:"block1" msgpack:"block1"` //serialized block header
+	Block2              []byte `json:"block2" msgpack:"block2"` //serialized block header
+}
+
 type WizardTxSimpleTransfer struct {
 	Extra WizardTxSimpleExtra    `json:"extra" msgpack:"extra"`
 	Data  *WizardTransactionData `json:"data" msgpack:"data"`
@@ -281,6 +281,18 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 
 				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_SPEND
 
+				if transfer.SenderSpendSigner != nil {
+					spendPublicKey := new(bn256.G1)
+					if err = spendPublicKey.DecodeCompressed(transfer.SenderSpendPublicKey); err != nil {
+						return
+					}
+					payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend{nil,
+						spendPublicKey,
+						nil,
+					}
+					break
+				}
+
 				if privateKeysForSign[t], err = addresses.NewPrivateKey(transfer.SenderSpendPrivateKey); err != nil {
 					return
 				}
@@ -328,6 +340,7 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 
 	var witness_list []crypto.Witness
 	sender_secrets := make([]*big.Int, len(transfers))
+	spendWitnesses := make([]*WizardZetherSpendWitness, len(transfers))
 
 	otherFee := uint64(0)
 	for t, transfer := range transfers {
@@ -371,6 +384,10 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 		value := transfers[t].Amount
 		burn_value := transfers[t].Burn
 
+		if payload.PayloadScript == transaction_zether_payload_script.SCRIPT_SPEND && transfer.SenderSpendSigner != nil {
+			spendWitnesses[t] = &WizardZetherSpendWitness{crypto.ConvertBigIntToByte(r), witness_index[0], witness_index[1], value}
+		}
+
 		//whisper the value to the sender
 		if payload.PayloadScript != transaction_zether_payload_script.SCRIPT_STAKING && payload.PayloadScript != transaction_zether_payload_script.SCRIPT_STAKING_REWARD {
 			v2 := crypto.ReducedHash(new(bn256.G1).ScalarMult(publickeylist[witness_index[0]], r).EncodeCompressed())
@@ -545,6 +562,16 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 
 		witness := GenerateWitness(sender_secrets[t], r, value, balance-value-fee-burn_value, witness_index)
 
+		//the next payload of the same sender and asset starts from the remaining balance to avoid decrypting it by brute force
+		if payload.PayloadScript != transaction_zether_payload_script.SCRIPT_STAKING && payload.PayloadScript != transaction_zether_payload_script.SCRIPT_STAKING_REWARD {
+			for j := t + 1; j < len(transfers); j++ {
+				if bytes.Equal(transfers[j].Asset, transfer.Asset) && bytes.Equal(transfers[j].SenderPrivateKey, transfer.SenderPrivateKey) {
+					transfers[j].SenderDecryptedBalance = balance - value - fee - burn_value
+					break
+				}
+			}
+		}
+
 		witness_list = append(witness_list, witness)
 
 		// this goes to proof.u
@@ -653,6 +680,29 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 		}
 	}
 
+	//the remote signers receive the tx with empty spend signatures to be able to deserialize it
+	for t := range transfers {
+		if spendWitnesses[t] != nil {
+			txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend).SenderSpendSignature = helpers.EmptyBytes(cryptography.SignatureSize)
+		}
+	}
+	for t, transfer := range transfers {
+		if spendWitnesses[t] != nil {
+
+			statusCallback(fmt.Sprintf("Requesting the spend signature of payload %d", t))
+
+			var signature []byte
+			if signature, err = transfer.SenderSpendSigner(tx, t, spendWitnesses[t]); err != nil {
+				return
+			}
+			if len(signature) != cryptography.SignatureSize {
+				return fmt.Errorf("Spend signature of payload %d is invalid", t)
+			}
+
+			txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend).SenderSpendSignature = signature
+		}
+	}
+
 	statusCallback("Transaction Zether Proofs generated")
 	return
 }
@@ -661,7 +711,11 @@ func CreateZetherTx(transfers []*WizardZetherTransfer, emap map[string]map[strin
 
 	for i, transfer := range transfers {
 		if transfer.SenderSpendRequired {
-			if len(transfer.SenderSpendPrivateKey) != cryptography.PrivateKeySize {
+			if transfer.SenderSpendSigner != nil {
+				if len(transfer.SenderSpendPublicKey) != cryptography.PublicKeySize {
+					return nil, fmt.Errorf("SpendPublicKey is invalid for payload %d", i)
+				}
+			} else if len(transfer.SenderSpendPrivateKey) != cryptography.PrivateKeySize {
 				return nil, fmt.Errorf("SpendPrivateKey is invalid for payload %d", i)
 			}
 			if transfer.PayloadExtra != nil {
@@ -2,6 +2,7 @@ package wizard
 
 import (
 	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/blockchain/transactions/transaction"
 )
 
 type WizardZetherPayloadExtraStaking struct {
@@ -52,6 +53,8 @@ type WizardZetherTransfer struct {
 	SenderDecryptedBalance uint64                   `json:"senderDecryptedBalance" msgpack:"senderDecryptedBalance"`
 	SenderSpendRequired    bool                     `json:"senderSpendRequired" msgpack:"senderSpendRequired"`
 	SenderSpendPrivateKey  []byte                   `json:"senderSpendPrivateKey" msgpack:"senderSpendPrivateKey"`
+	SenderSpendPublicKey   []byte                   `json:"senderSpendPublicKey,omitempty" msgpack:"senderSpendPublicKey,omitempty"` //required when the spend signature is created by SenderSpendSigner
+	SenderSpendSigner      WizardZetherSpendSigner  `json:"-" msgpack:"-"`
 	Recipient              string                   `json:"recipient" msgpack:"recipient"`
 	Amount                 uint64                   `json:"amount" msgpack:"amount"`
 	Burn                   uint64                   `json:"burn" msgpack:"burn"`
@@ -62,6 +65,17 @@ type WizardZetherTransfer struct {
 	WitnessIndexes         []int                    `json:"witnessIndexes" msgpack:"witnessIndexes"`
 }
 
+// WizardZetherSpendWitness reveals the blinding scalar R. Using it, the co-signer can verify the amount and the recipient of the payload
+type WizardZetherSpendWitness struct {
+	R              []byte `json:"r" msgpack:"r"`
+	SenderIndex    int    `json:"senderIndex" msgpack:"senderIndex"`
+	RecipientIndex int    `json:"recipientIndex" msgpack:"recipientIndex"`
+	Amount         uint64 `json:"amount" msgpack:"amount"`
+}
+
+// WizardZetherSpendSigner returns the spend signature of the payload without having the Spend Private Key
+type WizardZetherSpendSigner func(tx *transaction.Transaction, payloadIndex int, witness *WizardZetherSpendWitness) ([]byte, error)
+
 type WizardZetherPublicKeyIndex struct {
 	Registered                 bool   `json:"registered" msgpack:"registered"`
 	RegisteredIndex            uint64 `json:"registeredIndex" msgpack:"registeredIndex"`
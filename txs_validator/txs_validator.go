@@ -5,6 +5,7 @@ import (
 	"pandora-pay/config"
 	"pandora-pay/gui"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/metrics"
 	"sync/atomic"
 	"time"
 )
@@ -17,6 +18,8 @@ type TxsValidatorType struct {
 
 var TxsValidator *TxsValidatorType
 
+var metricsValidationLatency = metrics.NewSummary("txs_validation_seconds", "Time spent validating the transactions")
+
 func (validator *TxsValidatorType) MarkAsValidatedTx(tx *transaction.Transaction) error {
 
 	foundWork, loaded := validator.all.LoadOrStore(tx.Bloom.HashStr, &txValidatedWork{make(chan struct{}), TX_VALIDATED_INIT, tx, 0, nil, nil})
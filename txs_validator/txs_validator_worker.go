@@ -59,6 +59,7 @@ func (worker *TxsValidatorWorker) run() {
 	for {
 		foundWork, _ := <-worker.newValidationWorkCn
 
+		start := time.Now()
 		if err := foundWork.tx.BloomAll(); err != nil {
 			foundWork.result = err
 		} else {
@@ -68,6 +69,8 @@ func (worker *TxsValidatorWorker) run() {
 			}
 		}
 
+		metricsValidationLatency.Observe(time.Since(start))
+
 		foundWork.tx = nil
 		foundWork.time = time.Now().Add(EXPIRE_TIME_MS).Unix()
 		atomic.StoreInt32(&foundWork.status, TX_VALIDATED_PROCCESSED)
@@ -7,11 +7,13 @@ import (
 	"pandora-pay/config"
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/mempool"
+	"pandora-pay/store"
 	"pandora-pay/wallet/wallet_address"
 	"sync"
 )
 
 type Wallet struct {
+	Name                    string                          `json:"-" msgpack:"-"`
 	Encryption              *WalletEncryption               `json:"encryption" msgpack:"encryption"`
 	Version                 Version                         `json:"version" msgpack:"version"`
 	Mnemonic                string                          `json:"mnemonic" msgpack:"mnemonic"`
@@ -23,21 +25,37 @@ type Wallet struct {
 	Loaded                  bool                            `json:"loaded" msgpack:"loaded"`
 	DelegatesCount          int                             `json:"delegatesCount" msgpack:"delegatesCount"`
 	addressesMap            map[string]*wallet_address.WalletAddress
+	lockedForging           [][]byte //public keys of the addresses still forging after the wallet was locked
+	store                   *store.Store
+	wallets                 *walletsList
+	autoLock                *walletAutoLock
 	forging                 *forging.Forging
 	mempool                 *mempool.Mempool
 	addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor
 	updateNewChainUpdate    *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]
+	UpdateInvoices          *multicast.MulticastChannel[*WalletInvoice] `json:"-" msgpack:"-"`
 	nonHardening            bool         `json:"nonHardening" msgpack:"nonHardening"`
 	Lock                    sync.RWMutex `json:"-" msgpack:"-"`
 }
 
-func createWallet(forging *forging.Forging, mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor, updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) (wallet *Wallet) {
+// createWallet creates the default wallet when wallets is nil. Otherwise, the named wallet shares the list and the services of the default wallet
+func createWallet(name string, walletStore *store.Store, wallets *walletsList, forging *forging.Forging, mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor, updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) (wallet *Wallet) {
 	wallet = &Wallet{
+		Name:                    name,
+		store:                   walletStore,
+		wallets:                 wallets,
+		autoLock:                &walletAutoLock{},
 		forging:                 forging,
 		mempool:                 mempool,
 		updateNewChainUpdate:    updateNewChainUpdate,
 		addressBalanceDecryptor: addressBalanceDecryptor,
 	}
+	if wallets == nil {
+		wallet.wallets = createWalletsList(wallet)
+		wallet.UpdateInvoices = multicast.NewMulticastChannel[*WalletInvoice]()
+	} else {
+		wallet.UpdateInvoices = wallets.main.UpdateInvoices
+	}
 	wallet.clearWallet()
 	return
 }
@@ -50,6 +68,7 @@ func (wallet *Wallet) clearWallet() {
 	wallet.SeedIndex = 0
 	wallet.Count = 0
 	wallet.CountImportedIndex = 0
+	wallet.DelegatesCount = 0
 	wallet.Addresses = make([]*wallet_address.WalletAddress, 0)
 	wallet.addressesMap = make(map[string]*wallet_address.WalletAddress)
 	wallet.Encryption = createEncryption(wallet)
@@ -60,12 +79,14 @@ func (wallet *Wallet) clearWallet() {
 //must be locked before
 func (wallet *Wallet) setLoaded(newValue bool) {
 	wallet.Loaded = newValue
-	wallet.initWalletCLI()
+	if wallet.isActive() {
+		wallet.initWalletCLI()
+	}
 }
 
 func CreateWallet(forging *forging.Forging, mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor) (*Wallet, error) {
 
-	wallet := createWallet(forging, mempool, addressBalanceDecryptor, nil)
+	wallet := createWallet(WALLET_DEFAULT_NAME, store.StoreWallet, nil, forging, mempool, addressBalanceDecryptor, nil)
 
 	if err := wallet.loadWallet("", true); err != nil {
 		if err.Error() == "cipher: message authentication failed" {
@@ -82,13 +103,18 @@ func CreateWallet(forging *forging.Forging, mempool *mempool.Mempool, addressBal
 	return wallet, nil
 }
 
-func (wallet *Wallet) InitializeWallet(updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) {
+func (wallet *Wallet) InitializeWallet(updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates], updateTransactions *multicast.MulticastChannel[[]*blockchain_types.BlockchainTransactionUpdate]) {
 
 	wallet.Lock.Lock()
 	wallet.updateNewChainUpdate = updateNewChainUpdate
 	wallet.Lock.Unlock()
 
+	wallet.processHistory(updateTransactions)
+	wallet.processInvoices(updateNewChainUpdate)
+
 	if config.NODE_CONSENSUS == config.NODE_CONSENSUS_TYPE_FULL {
 		wallet.processRefreshWallets()
 	}
+
+	wallet.processAutoLock()
 }
@@ -20,12 +20,20 @@ type WalletAddress struct {
 	Staked                     bool                                     `json:"staked" msgpack:"staked"`
 	SpendRequired              bool                                     `json:"spendRequired" msgpack:"spendRequired"`
 	SpendPublicKey             []byte                                   `json:"spendPublicKey" msgpack:"spendPublicKey"`
+	WatchOnly                  bool                                     `json:"watchOnly,omitempty" msgpack:"watchOnly,omitempty"` //the SpendPrivateKey is never stored
 	IsSharedStaked             bool                                     `json:"isSharedStaked,omitempty" msgpack:"isSharedStaked,omitempty"`
 	SharedStaked               *shared_staked.WalletAddressSharedStaked `json:"sharedStaked,omitempty" msgpack:"sharedStaked,omitempty"`
+	RemoteSpendSigner          *WalletAddressRemoteSpendSigner          `json:"remoteSpendSigner,omitempty" msgpack:"remoteSpendSigner,omitempty"` //the SpendPrivateKey is held by a co-signer
 	AddressEncoded             string                                   `json:"addressEncoded" msgpack:"addressEncoded"`
 	AddressRegistrationEncoded string                                   `json:"addressRegistrationEncoded" msgpack:"addressRegistrationEncoded"`
 }
 
+// WalletAddressRemoteSpendSigner is the co-signer node holding the SpendPrivateKey
+type WalletAddressRemoteSpendSigner struct {
+	URL   string `json:"url" msgpack:"url"`
+	Token string `json:"token" msgpack:"token"` //API token with the cosigner scope
+}
+
 func (addr *WalletAddress) DeriveSharedStaked() (*shared_staked.WalletAddressSharedStaked, error) {
 
 	if addr.PrivateKey == nil {
@@ -68,6 +76,26 @@ func (addr *WalletAddress) VerifySignedMessage(message, signature []byte) (bool,
 	return address.VerifySignedMessage(message, signature), nil
 }
 
+// GetWatchOnly returns a copy without the secret and the spend private key. It can decrypt and stake, but it can't spend
+func (addr *WalletAddress) GetWatchOnly() (*WalletAddress, error) {
+
+	if addr.PrivateKey == nil {
+		return nil, errors.New("Private Key is missing")
+	}
+	if !addr.SpendRequired || len(addr.SpendPublicKey) == 0 {
+		return nil, errors.New("Address doesn't require the Spend Key. The Private Key alone can spend")
+	}
+
+	watchOnly := addr.Clone()
+	watchOnly.SeedIndex = 0
+	watchOnly.IsImported = true
+	watchOnly.SecretKey = nil
+	watchOnly.SpendPrivateKey = nil
+	watchOnly.WatchOnly = true
+
+	return watchOnly, nil
+}
+
 func (addr *WalletAddress) Clone() *WalletAddress {
 
 	if addr == nil {
@@ -79,6 +107,11 @@ func (addr *WalletAddress) Clone() *WalletAddress {
 		sharedStaked = &shared_staked.WalletAddressSharedStaked{addr.SharedStaked.PrivateKey, addr.SharedStaked.PublicKey}
 	}
 
+	var remoteSpendSigner *WalletAddressRemoteSpendSigner
+	if addr.RemoteSpendSigner != nil {
+		remoteSpendSigner = &WalletAddressRemoteSpendSigner{addr.RemoteSpendSigner.URL, addr.RemoteSpendSigner.Token}
+	}
+
 	return &WalletAddress{
 		addr.Version,
 		addr.Name,
@@ -93,8 +126,10 @@ func (addr *WalletAddress) Clone() *WalletAddress {
 		addr.Staked,
 		addr.SpendRequired,
 		addr.SpendPublicKey,
+		addr.WatchOnly,
 		addr.IsSharedStaked,
 		sharedStaked,
+		remoteSpendSigner,
 		addr.AddressEncoded,
 		addr.AddressRegistrationEncoded,
 	}
@@ -3,6 +3,7 @@ package wallet
 import (
 	"encoding/base64"
 	"errors"
+	"math"
 	"pandora-pay/config/arguments"
 	"pandora-pay/wallet/wallet_address"
 	"strconv"
@@ -27,6 +28,12 @@ func (wallet *Wallet) ProcessWalletArguments() (err error) {
 		}
 	}
 
+	if shares := arguments.Arguments["--wallet-import-secret-shares"]; shares != nil {
+		if err = wallet.ImportSecretShares(strings.Split(shares.(string), ",")); err != nil {
+			return
+		}
+	}
+
 	if str := arguments.Arguments["--wallet-encrypt"]; str != nil {
 		v := strings.Split(str.(string), ",")
 
@@ -86,5 +93,28 @@ func (wallet *Wallet) ProcessWalletArguments() (err error) {
 
 	}
 
+	if str := arguments.Arguments["--wallet-export-history"]; str != nil {
+		v := strings.Split(str.(string), ",")
+		if len(v) != 3 {
+			return errors.New("--wallet-export-history must be \"file,from,to\"")
+		}
+
+		from, to := uint64(0), uint64(math.MaxUint64)
+		if v[1] != "" {
+			if from, err = strconv.ParseUint(v[1], 10, 64); err != nil {
+				return
+			}
+		}
+		if v[2] != "" {
+			if to, err = strconv.ParseUint(v[2], 10, 64); err != nil {
+				return
+			}
+		}
+
+		if _, err = wallet.ExportHistoryToFile(v[0], nil, from, to); err != nil {
+			return
+		}
+	}
+
 	return
 }
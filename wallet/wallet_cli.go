@@ -1,34 +1,47 @@
 package wallet
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/tyler-smith/go-bip39"
+	"math"
 	"os"
 	"pandora-pay/addresses"
 	"pandora-pay/blockchain/data_storage"
 	"pandora-pay/blockchain/data_storage/accounts"
 	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/assets"
 	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/blockchain/data_storage/pending_stakes_list/pending_stakes"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
 	"pandora-pay/blockchain/data_storage/registrations"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payment_proof"
 	"pandora-pay/config"
 	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_cosigner"
+	"pandora-pay/config/config_reward"
+	"pandora-pay/config/config_stake"
 	"pandora-pay/cryptography"
 	"pandora-pay/cryptography/crypto"
+	"pandora-pay/cryptography/shamir"
 	"pandora-pay/gui"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/files"
+	"pandora-pay/helpers/totp"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
 	"pandora-pay/wallet/wallet_address"
 	"pandora-pay/wallet/wallet_address/shared_staked"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func (wallet *Wallet) exportSharedStakedAddress(addr *wallet_address.WalletAddress, path string, print bool) (*shared_staked.WalletAddressSharedStakedAddressExported, error) {
@@ -122,6 +135,7 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 		registration            *registration.Registration
 		plainAcc                *plain_account.PlainAccount
 		assetsList              []*AddressAsset
+		pendingStakes           []*pending_stakes.PendingStakes
 		publicKey               []byte
 		name                    string
 		addressString           string
@@ -140,6 +154,9 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 
 	for i, walletAddress := range wallet.Addresses {
 		addresses[i] = &Address{publicKey: helpers.CloneBytes(walletAddress.PublicKey), name: walletAddress.Name, addressString: walletAddress.GetAddress(false), addressRegisteredString: walletAddress.GetAddress(true)}
+		if walletAddress.WatchOnly {
+			addresses[i].name += " (watch-only)"
+		}
 	}
 	wallet.Lock.RUnlock()
 
@@ -147,6 +164,8 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 
 		dataStorage := data_storage.NewDataStorage(reader)
 
+		chainHeight, _ := binary.Uvarint(reader.Get("chainHeight"))
+
 		var ast *asset.Asset
 		var accs *accounts.Accounts
 		var acc *account.Account
@@ -166,6 +185,10 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 				return
 			}
 
+			if addresses[i].pendingStakes, err = dataStorage.PendingStakes.GetPublicKeyPendingStakes(address.publicKey, chainHeight, chainHeight+config_stake.GetPendingStakeWindow(chainHeight)); err != nil {
+				return
+			}
+
 			if len(assetsList) > 0 {
 
 				for _, assetId := range assetsList {
@@ -254,6 +277,31 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 
 		}
 
+		if len(addresses[i].pendingStakes) > 0 {
+
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s %d", "PENDING STAKES", "", len(addresses[i].pendingStakes)))
+			for _, pendingStakes := range addresses[i].pendingStakes {
+				for _, pending := range pendingStakes.Pending {
+					gui.GUI.OutputWrite(fmt.Sprintf("%18d: %64s", pendingStakes.Height, base64.StdEncoding.EncodeToString(pending.PendingAmount)))
+				}
+			}
+
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s", "Decrypting...."))
+
+			for _, pendingStakes := range addresses[i].pendingStakes {
+				for _, pending := range pendingStakes.Pending {
+					gui.GUI.Info2Update("Decrypting", "")
+
+					if decrypted, err = wallet.decryptPendingStake(address.publicKey, pendingStakes.Height, pending, ctx); err != nil {
+						return
+					}
+
+					gui.GUI.OutputWrite(fmt.Sprintf("%18s: %18s", "Active at "+strconv.FormatUint(pendingStakes.Height, 10), strconv.FormatFloat(config_coins.ConvertToBase(decrypted), 'f', config_coins.DECIMAL_SEPARATOR, 64)))
+				}
+			}
+
+		}
+
 		gui.GUI.Info2Update("Decoding", "")
 
 	}
@@ -263,8 +311,34 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 	return
 }
 
+//decryptPendingStake checks first the amounts known by the wallet, 0 for the ring members and the block reward for the forged blocks.
+//Only the other pending stakes are decrypted
+func (wallet *Wallet) decryptPendingStake(publicKey []byte, activationHeight uint64, pending *pending_stakes.PendingStake, ctx context.Context) (uint64, error) {
+
+	knownAmounts := []uint64{0}
+	if window := config_stake.GetPendingStakeWindow(activationHeight); activationHeight >= window {
+		knownAmounts = append(knownAmounts, config_reward.GetRewardAt(activationHeight-window))
+	}
+
+	for _, amount := range knownAmounts {
+		found, err := wallet.TryDecryptBalanceByPublicKey(publicKey, pending.PendingAmount, true, amount)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			return amount, nil
+		}
+	}
+
+	return wallet.DecryptBalanceByPublicKey(publicKey, pending.PendingAmount, config_coins.NATIVE_ASSET_FULL, false, 0, false, true, ctx, func(status string) {
+		gui.GUI.Info2Update("Decrypted", status)
+	})
+}
+
 func (wallet *Wallet) CliSelectAddress(text string, ctx context.Context) (*wallet_address.WalletAddress, string, int, error) {
 
+	wallet.touch()
+
 	if err := wallet.CliListAddresses("", ctx); err != nil {
 		return nil, "", 0, err
 	}
@@ -325,6 +399,7 @@ func (wallet *Wallet) initWalletCLI() {
 		}
 
 		index := gui.GUI.OutputReadInt("Select Address to be Exported", false, 0, nil)
+		watchOnly := gui.GUI.OutputReadBool("Export watch-only (without the Spend Private Key)? y/n. Leave empty for no", true, false)
 		filename := gui.GUI.OutputReadFilename("Path to export", "pandora", false)
 
 		wallet.Lock.RLock()
@@ -338,6 +413,11 @@ func (wallet *Wallet) initWalletCLI() {
 		}
 
 		obj := wallet.Addresses[index]
+		if watchOnly {
+			if obj, err = obj.GetWatchOnly(); err != nil {
+				return
+			}
+		}
 
 		var marshal []byte
 		if marshal, err = json.Marshal(obj); err != nil {
@@ -427,6 +507,22 @@ func (wallet *Wallet) initWalletCLI() {
 		return wallet.CliListAddresses(cmd, ctx)
 	}
 
+	cliCreateCosignedAddress := func(cmd string, ctx context.Context) (err error) {
+
+		name := gui.GUI.OutputReadString("Name of your new address")
+		staked := gui.GUI.OutputReadBool("Staked address ? y/n. Leave empty for n", true, false)
+		spendPublicKey := gui.GUI.OutputReadBytes("Spend Public Key created by the co-signer", func(input []byte) bool {
+			return len(input) == cryptography.PublicKeySize
+		})
+		url := gui.GUI.OutputReadString("Co-signer URL")
+		token := gui.GUI.OutputReadString("Co-signer API token")
+
+		if _, err = wallet.AddNewCosignedAddress(name, staked, spendPublicKey, &wallet_address.WalletAddressRemoteSpendSigner{url, token}); err != nil {
+			return
+		}
+		return wallet.CliListAddresses(cmd, ctx)
+	}
+
 	cliRemoveAddress := func(cmd string, ctx context.Context) (err error) {
 
 		_, _, index, err := wallet.CliSelectAddress("Select Address to be Removed", ctx)
@@ -542,6 +638,56 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliShowSecretShares := func(cmd string, ctx context.Context) (err error) {
+
+		count := gui.GUI.OutputReadInt("Number of shares", false, 0, func(value int) bool {
+			return value >= 2 && value <= 255
+		})
+		threshold := gui.GUI.OutputReadInt("Number of shares required to recover the wallet", false, 0, func(value int) bool {
+			return value >= 2 && value <= count
+		})
+
+		phrases, err := wallet.GetSecretShares(threshold, count)
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Secret Shares. Any %d of %d shares recover the wallet", threshold, count))
+		gui.GUI.OutputWrite("---------------------")
+		for i, phrase := range phrases {
+			gui.GUI.OutputWrite(fmt.Sprintf("Share %d: %s", i+1, phrase))
+		}
+
+		return
+	}
+
+	cliImportSecretShares := func(cmd string, ctx context.Context) (err error) {
+
+		gui.GUI.OutputWrite("WARNING!!! THIS COMMAND WILL DELETE YOUR EXISTING WALLET!", config.LineBreak, config.LineBreak)
+
+		if !gui.GUI.OutputReadBool("Are you sure you want to clear the existing wallet and import secret shares? y/n", false, false) {
+			return
+		}
+
+		first, err := shamir.DecodeSharePhrase(gui.GUI.OutputReadString("Provide the share 1"))
+		if err != nil {
+			return
+		}
+
+		phrases := []string{first.EncodePhrase()}
+		for i := 1; i < int(first.Threshold); i++ {
+			phrases = append(phrases, gui.GUI.OutputReadString(fmt.Sprintf("Provide the share %d of %d", i+1, first.Threshold)))
+		}
+
+		if err = wallet.ImportSecretShares(phrases); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("A new wallet has been created using the secret shares provided!")
+
+		return
+	}
+
 	cliShowAddressSecretKey := func(cmd string, ctx context.Context) (err error) {
 
 		_, _, index, err := wallet.CliSelectAddress("Select Address to show the secret key", ctx)
@@ -558,6 +704,342 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliShowHistory := func(cmd string, ctx context.Context) (err error) {
+
+		addr, _, _, err := wallet.CliSelectAddress("Select Address to show the history", ctx)
+		if err != nil {
+			return
+		}
+
+		if err = wallet.ScanHistory(addr.PublicKey, ctx); err != nil {
+			return
+		}
+
+		historyTxs, total, err := wallet.GetHistory(addr.PublicKey, 0, 100)
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("History %d transactions", total))
+		for _, historyTx := range historyTxs {
+			gui.GUI.OutputWrite(fmt.Sprintf("%s Height %d Confirmations %d Timestamp %d", base64.StdEncoding.EncodeToString(historyTx.TxHash), historyTx.BlockHeight, historyTx.Confirmations, historyTx.BlockTimestamp))
+			for _, payload := range historyTx.Payloads {
+
+				direction := "RECEIVED"
+				if payload.Sent {
+					direction = "SENT"
+				}
+
+				amount := strconv.FormatUint(payload.Amount, 10)
+				if bytes.Equal(payload.Asset, config_coins.NATIVE_ASSET_FULL) {
+					amount = strconv.FormatFloat(config_coins.ConvertToBase(payload.Amount), 'f', config_coins.DECIMAL_SEPARATOR, 64)
+				}
+
+				if payload.Contact != "" {
+					direction += " TO " + payload.Contact
+				}
+
+				gui.GUI.OutputWrite(fmt.Sprintf("%18s: %18s %s %s %s", direction, amount, base64.StdEncoding.EncodeToString(payload.Asset), base64.StdEncoding.EncodeToString(payload.PaymentID), string(payload.Message)))
+			}
+		}
+
+		return
+	}
+
+	cliExportHistory := func(cmd string, ctx context.Context) (err error) {
+
+		if err = wallet.CliListAddresses("", ctx); err != nil {
+			return
+		}
+
+		index := gui.GUI.OutputReadInt("Select Address to be Exported. Leave empty for all", true, -1, func(value int) bool {
+			return value >= -1 && value < wallet.GetAddressesCount()
+		})
+		fromHeight := gui.GUI.OutputReadUint64("From block height. Leave empty for the beginning", true, 0, nil)
+		toHeight := gui.GUI.OutputReadUint64("To block height. Leave empty for the last block", true, math.MaxUint64, nil)
+		filename := gui.GUI.OutputReadFilename("Path to export. Use .json for JSON", "csv", false)
+
+		publicKeys := [][]byte{}
+		if index != -1 {
+			var addr *wallet_address.WalletAddress
+			if addr, err = wallet.GetWalletAddress(index, true); err != nil {
+				return
+			}
+			publicKeys = append(publicKeys, addr.PublicKey)
+		} else {
+			wallet.Lock.RLock()
+			for _, addr := range wallet.Addresses {
+				publicKeys = append(publicKeys, addr.PublicKey)
+			}
+			wallet.Lock.RUnlock()
+		}
+
+		for _, publicKey := range publicKeys {
+			if err = wallet.ScanHistory(publicKey, ctx); err != nil {
+				return
+			}
+		}
+
+		count, err := wallet.ExportHistoryToFile(filename, publicKeys, fromHeight, toHeight)
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Exported %d rows successfully to: %s", count, filename))
+		return
+	}
+
+	cliCreateInvoice := func(cmd string, ctx context.Context) (err error) {
+
+		addr, _, _, err := wallet.CliSelectAddress("Select Address to be paid", ctx)
+		if err != nil {
+			return
+		}
+
+		assetId := gui.GUI.OutputReadBytes("Asset. Leave empty for Native Asset", func(input []byte) bool {
+			return len(input) == 0 || len(input) == config_coins.ASSET_LENGTH
+		})
+		if len(assetId) == 0 {
+			assetId = config_coins.NATIVE_ASSET_FULL
+		}
+
+		amountFloat := gui.GUI.OutputReadFloat64("Amount", false, 0, func(value float64) bool {
+			return value > 0
+		})
+		confirmations := gui.GUI.OutputReadUint64(fmt.Sprintf("Confirmations. Leave empty for %d", config.WALLET_INVOICE_CONFIRMATIONS), true, 0, nil)
+
+		var amount uint64
+		if bytes.Equal(assetId, config_coins.NATIVE_ASSET_FULL) {
+			if amount, err = config_coins.ConvertToUnits(amountFloat); err != nil {
+				return
+			}
+		} else if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+			var ast *asset.Asset
+			if ast, err = assets.NewAssets(reader).Get(string(assetId)); err != nil {
+				return
+			}
+			if ast == nil {
+				return errors.New("Asset was not found")
+			}
+			amount, err = ast.ConvertToUnits(amountFloat)
+			return
+		}); err != nil {
+			return
+		}
+
+		invoice, err := wallet.CreateInvoice(addr.PublicKey, assetId, amount, confirmations)
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("PaymentID", base64.StdEncoding.EncodeToString(invoice.PaymentID))
+		gui.GUI.OutputWrite("Address to be paid", invoice.Address)
+		return
+	}
+
+	cliShowInvoices := func(cmd string, ctx context.Context) (err error) {
+
+		invoices, total, err := wallet.GetInvoices(0, 100)
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Invoices %d", total))
+		for _, invoice := range invoices {
+			gui.GUI.OutputWrite(fmt.Sprintf("%s %10s Amount %d Received %d Confirmations %d/%d Payments %d", base64.StdEncoding.EncodeToString(invoice.PaymentID), invoice.Status, invoice.Amount, invoice.Received, invoice.Confirmations, invoice.RequiredConfirmations, len(invoice.Payments)))
+		}
+
+		return
+	}
+
+	cliCreatePaymentProof := func(cmd string, ctx context.Context) (err error) {
+
+		addr, _, _, err := wallet.CliSelectAddress("Select Address which sent or received the payment", ctx)
+		if err != nil {
+			return
+		}
+
+		hash := gui.GUI.OutputReadBytes("Tx Hash", func(input []byte) bool {
+			return len(input) == cryptography.HashSize
+		})
+		payloadIndex := gui.GUI.OutputReadUint64("Payload Index. Leave empty for 0", true, 0, nil)
+
+		tx, err := loadStoredTx(hash)
+		if err != nil {
+			return
+		}
+
+		proof, err := wallet.CreatePaymentProof(tx, int(payloadIndex), addr.PublicKey)
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Payment Proof", proof.Encode())
+		return
+	}
+
+	cliVerifyPaymentProof := func(cmd string, ctx context.Context) (err error) {
+
+		proof, err := transaction_zether_payment_proof.DecodePaymentProof(gui.GUI.OutputReadString("Payment Proof"))
+		if err != nil {
+			return
+		}
+
+		tx, err := loadStoredTx(proof.TxHash)
+		if err != nil {
+			return
+		}
+
+		output, err := proof.Verify(tx)
+		if err != nil {
+			return
+		}
+
+		amount := strconv.FormatUint(output.Amount, 10)
+		if bytes.Equal(output.Asset, config_coins.NATIVE_ASSET_FULL) {
+			amount = strconv.FormatFloat(config_coins.ConvertToBase(output.Amount), 'f', config_coins.DECIMAL_SEPARATOR, 64)
+		}
+
+		gui.GUI.OutputWrite("Payment Proof is valid")
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx %s payload %d paid %s %s to %s", base64.StdEncoding.EncodeToString(output.TxHash), output.PayloadIndex, amount, base64.StdEncoding.EncodeToString(output.Asset), output.Recipient))
+		return
+	}
+
+	cliReadContact := func(name string) *WalletContact {
+
+		contact := &WalletContact{Name: name}
+		contact.Address = gui.GUI.OutputReadString("Contact address")
+		contact.DefaultAsset = gui.GUI.OutputReadBytes("Default Asset. Leave empty for none", func(input []byte) bool {
+			return len(input) == 0 || len(input) == config_coins.ASSET_LENGTH
+		})
+		if len(contact.DefaultAsset) == 0 {
+			contact.DefaultAsset = nil
+		}
+		contact.Notes = gui.GUI.OutputReadString("Notes")
+
+		return contact
+	}
+
+	cliListContacts := func(cmd string, ctx context.Context) (err error) {
+
+		contacts, err := wallet.GetContacts()
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Contacts %d", len(contacts)))
+		for _, contact := range contacts {
+			gui.GUI.OutputWrite(fmt.Sprintf("%20s %s %s %s", contact.Name, contact.Address, base64.StdEncoding.EncodeToString(contact.DefaultAsset), contact.Notes))
+		}
+
+		return
+	}
+
+	cliCosignerCreateAccount := func(cmd string, ctx context.Context) (err error) {
+
+		name := gui.GUI.OutputReadString("Account name")
+
+		policy := &WalletCosignerPolicy{}
+		policy.DailyLimit = gui.GUI.OutputReadUint64("Daily limit. Leave empty for no limit", true, 0, nil)
+		if recipients := strings.TrimSpace(gui.GUI.OutputReadString("Allowed recipients separated by comma. Leave empty for any")); recipients != "" {
+			policy.AllowedRecipients = strings.Split(recipients, ",")
+		}
+		policy.RequireCode = gui.GUI.OutputReadBool("Require TOTP confirmation codes? y/n. Leave empty for y", true, true)
+
+		account, totpSecret, err := wallet.CreateCosignerAccount(name, policy)
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Spend Public Key: " + base64.StdEncoding.EncodeToString(account.SpendPublicKey))
+		if totpSecret != nil {
+			gui.GUI.OutputWrite("TOTP secret (displayed only once): " + totp.EncodeSecret(totpSecret))
+		}
+		return
+	}
+
+	cliCosignerListAccounts := func(cmd string, ctx context.Context) (err error) {
+
+		accounts, err := wallet.GetCosignerAccounts()
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Co-signer accounts %d", len(accounts)))
+		for _, account := range accounts {
+			gui.GUI.OutputWrite(fmt.Sprintf("%20s %s Spent %d/%d Recipients %d Code %t", account.Name, base64.StdEncoding.EncodeToString(account.SpendPublicKey), account.SpentToday, account.Policy.DailyLimit, len(account.Policy.AllowedRecipients), account.Policy.RequireCode))
+		}
+
+		return
+	}
+
+	cliCosignerAuditLog := func(cmd string, ctx context.Context) (err error) {
+
+		entries, total, err := wallet.GetCosignerAuditLog(0, 100)
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Audit log %d", total))
+		for _, entry := range entries {
+			status := "approved"
+			if !entry.Approved {
+				status = "rejected: " + entry.Reason
+			}
+			gui.GUI.OutputWrite(fmt.Sprintf("%s %20s Amount %d Fee %d Burn %d To %s %s", time.Unix(entry.Timestamp, 0).UTC().Format(time.RFC3339), entry.Account, entry.Amount, entry.Fee, entry.Burn, entry.Recipient, status))
+		}
+
+		return
+	}
+
+	cliAddContact := func(cmd string, ctx context.Context) (err error) {
+
+		if err = wallet.AddContact(cliReadContact(gui.GUI.OutputReadString("Contact name"))); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Contact was added")
+		return
+	}
+
+	cliEditContact := func(cmd string, ctx context.Context) (err error) {
+
+		if err = cliListContacts(cmd, ctx); err != nil {
+			return
+		}
+
+		name := gui.GUI.OutputReadString("Contact to be edited")
+		if wallet.GetContact(name) == nil {
+			return errors.New("Contact was not found")
+		}
+
+		contact := cliReadContact(gui.GUI.OutputReadString("New contact name. Leave empty to keep it"))
+		if contact.Name == "" {
+			contact.Name = name
+		}
+
+		if err = wallet.EditContact(name, contact); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Contact was edited")
+		return
+	}
+
+	cliRemoveContact := func(cmd string, ctx context.Context) (err error) {
+
+		if err = cliListContacts(cmd, ctx); err != nil {
+			return
+		}
+
+		if err = wallet.RemoveContact(gui.GUI.OutputReadString("Contact to be removed")); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Contact was removed")
+		return
+	}
+
 	cliImportAddressSecretKey := func(cmd string, ctx context.Context) (err error) {
 
 		secretKey := gui.GUI.OutputReadBytes("Write Secret key", func(input []byte) bool {
@@ -605,6 +1087,13 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliLockWallet := func(cmd string, ctx context.Context) (err error) {
+		if err = wallet.LockWallet(); err == nil {
+			gui.GUI.OutputWrite("Wallet locked successfully")
+		}
+		return
+	}
+
 	cliRemoveEncryption := func(cmd string, ctx context.Context) (err error) {
 		gui.GUI.OutputWrite("Wallet removing encryption...")
 		if err = wallet.Encryption.RemoveEncryption(); err == nil {
@@ -613,6 +1102,69 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliListWallets := func(cmd string, ctx context.Context) (err error) {
+
+		list, err := wallet.GetWallets()
+		if err != nil {
+			return
+		}
+
+		for _, info := range list {
+			status := "locked"
+			if info.Loaded {
+				status = fmt.Sprintf("loaded %d addresses %s", info.Count, info.Encrypted)
+			}
+			if info.Active {
+				status += " ACTIVE"
+			}
+			gui.GUI.OutputWrite(fmt.Sprintf("%-32s %s", info.Name, status))
+		}
+
+		return
+	}
+
+	cliCreateWallet := func(cmd string, ctx context.Context) (err error) {
+
+		name := gui.GUI.OutputReadString("New Wallet name")
+		if err = wallet.CreateNamedWallet(name); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Wallet " + name + " has been created! Use Switch Wallet to activate it")
+		return
+	}
+
+	cliLoadWallet := func(cmd string, ctx context.Context) (err error) {
+
+		name := gui.GUI.OutputReadString("Wallet name")
+		password := gui.GUI.OutputReadString("Password. Leave empty if the wallet is not encrypted")
+
+		gui.GUI.OutputWrite("Wallet loading...")
+
+		if err = wallet.LoadNamedWallet(name, password); err == nil {
+			gui.GUI.OutputWrite("Wallet " + name + " loaded successfully")
+		}
+		return
+	}
+
+	cliUnloadWallet := func(cmd string, ctx context.Context) (err error) {
+
+		name := gui.GUI.OutputReadString("Wallet name")
+		if err = wallet.UnloadNamedWallet(name); err == nil {
+			gui.GUI.OutputWrite("Wallet " + name + " unloaded successfully")
+		}
+		return
+	}
+
+	cliSwitchWallet := func(cmd string, ctx context.Context) (err error) {
+
+		name := gui.GUI.OutputReadString("Wallet name")
+		if err = wallet.SwitchWallet(name); err == nil {
+			gui.GUI.OutputWrite("Wallet " + name + " is active")
+		}
+		return
+	}
+
 	cliCreatePair := func(cmd string, ctx context.Context) (err error) {
 		key := addresses.GenerateNewPrivateKey()
 		pub := key.GeneratePublicKey()
@@ -713,13 +1265,31 @@ func (wallet *Wallet) initWalletCLI() {
 	gui.GUI.CommandDefineCallback("List Addresses", wallet.CliListAddresses, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Scan Addresses", wallet.CliScanAddresses, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Create New Address", cliCreateNewAddress, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Create Co-Signed Address", cliCreateCosignedAddress, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Clear & Create new empty Wallet", cliClearWallet, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Show Mnemnonic", cliShowMnemonic, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Mnemnonic", cliImportMnemonic, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Show Entropy", cliShowEntropy, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Entropy", cliImportEntropy, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Show Secret Shares", cliShowSecretShares, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Import Secret Shares", cliImportSecretShares, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Show Address Secret Key", cliShowAddressSecretKey, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Address Secret Key", cliImportAddressSecretKey, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Show History", cliShowHistory, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Export History", cliExportHistory, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Create Invoice", cliCreateInvoice, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Show Invoices", cliShowInvoices, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Create Payment Proof", cliCreatePaymentProof, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Verify Payment Proof", cliVerifyPaymentProof, true)
+	gui.GUI.CommandDefineCallback("List Contacts", cliListContacts, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Add Contact", cliAddContact, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Edit Contact", cliEditContact, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Remove Contact", cliRemoveContact, wallet.Loaded)
+	if config_cosigner.COSIGNER_ENABLED {
+		gui.GUI.CommandDefineCallback("Co-Signer Create Account", cliCosignerCreateAccount, wallet.Loaded)
+		gui.GUI.CommandDefineCallback("Co-Signer List Accounts", cliCosignerListAccounts, wallet.Loaded)
+		gui.GUI.CommandDefineCallback("Co-Signer Audit Log", cliCosignerAuditLog, wallet.Loaded)
+	}
 	gui.GUI.CommandDefineCallback("Remove Address", cliRemoveAddress, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Staked Staked Address", cliExportSharedStakedAddress, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Addresses", cliExportAddresses, wallet.Loaded)
@@ -730,6 +1300,12 @@ func (wallet *Wallet) initWalletCLI() {
 	gui.GUI.CommandDefineCallback("Encrypt Wallet", cliEncryptWallet, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Remove Encryption", cliRemoveEncryption, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Decrypt Wallet", cliDecryptWallet, !wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Lock Wallet", cliLockWallet, wallet.Loaded && wallet.Encryption.Encrypted != ENCRYPTED_VERSION_PLAIN_TEXT)
+	gui.GUI.CommandDefineCallback("List Wallets", cliListWallets, true)
+	gui.GUI.CommandDefineCallback("Create Wallet", cliCreateWallet, true)
+	gui.GUI.CommandDefineCallback("Load Wallet", cliLoadWallet, true)
+	gui.GUI.CommandDefineCallback("Unload Wallet", cliUnloadWallet, true)
+	gui.GUI.CommandDefineCallback("Switch Wallet", cliSwitchWallet, true)
 
 	gui.GUI.CommandDefineCallback("Create (PublicKey, PrivateKey) pair", cliCreatePair, true)
 	gui.GUI.CommandDefineCallback("Sign message using PrivateKey", cliSignMessage, true)
@@ -0,0 +1,255 @@
+package wallet
+
+import (
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strings"
+)
+
+type WalletContact struct {
+	Name         string `json:"name" msgpack:"name"`
+	Address      string `json:"address" msgpack:"address"`
+	DefaultAsset []byte `json:"defaultAsset,omitempty" msgpack:"defaultAsset,omitempty"`
+	Notes        string `json:"notes,omitempty" msgpack:"notes,omitempty"`
+	publicKey    []byte
+}
+
+func (contact *WalletContact) validate() (err error) {
+
+	contact.Name = strings.TrimSpace(contact.Name)
+	if len(contact.Name) == 0 || len(contact.Name) > 64 {
+		return errors.New("Contact name must have between 1 and 64 characters")
+	}
+	if _, err = addresses.DecodeAddr(contact.Name); err == nil {
+		return errors.New("Contact name can not be an address")
+	}
+
+	var address *addresses.Address
+	if address, err = addresses.DecodeAddr(contact.Address); err != nil {
+		return errors.New("Contact address is invalid")
+	}
+	contact.publicKey = address.PublicKey
+
+	if len(contact.DefaultAsset) != 0 && len(contact.DefaultAsset) != config_coins.ASSET_LENGTH {
+		return errors.New("Contact default asset is invalid")
+	}
+	if len(contact.Notes) > 1024 {
+		return errors.New("Contact notes are too long")
+	}
+
+	return nil
+}
+
+//must be locked before
+func (wallet *Wallet) loadContacts(reader store_db_interface.StoreDBTransactionInterface) ([]*WalletContact, error) {
+
+	contacts := []*WalletContact{}
+
+	data := reader.Get("walletContacts")
+	if data == nil {
+		return contacts, nil
+	}
+
+	data, err := wallet.Encryption.decryptData(data)
+	if err != nil {
+		return nil, err
+	}
+	if err = msgpack.Unmarshal(data, &contacts); err != nil {
+		return nil, err
+	}
+
+	for _, contact := range contacts {
+		if address, err := addresses.DecodeAddr(contact.Address); err == nil {
+			contact.publicKey = address.PublicKey
+		}
+	}
+
+	return contacts, nil
+}
+
+//must be locked before
+func (wallet *Wallet) saveContacts(writer store_db_interface.StoreDBTransactionInterface, contacts []*WalletContact) error {
+
+	data, err := msgpack.Marshal(contacts)
+	if err != nil {
+		return err
+	}
+	if data, err = wallet.Encryption.encryptData(data); err != nil {
+		return err
+	}
+
+	writer.Put("walletContacts", data)
+	return nil
+}
+
+// readContactsRaw adds the contacts decrypted to out. It is used to encrypt the contacts again when the wallet encryption is changed
+//must be locked before
+func (wallet *Wallet) readContactsRaw(out map[string][]byte) error {
+	return wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		contacts, err := wallet.loadContacts(reader)
+		if err != nil {
+			return
+		}
+		out["walletContacts"], err = msgpack.Marshal(contacts)
+		return
+	})
+}
+
+func (wallet *Wallet) GetContacts() (contacts []*WalletContact, err error) {
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, errors.New("Wallet was not loaded!")
+	}
+
+	err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		contacts, err = wallet.loadContacts(reader)
+		return
+	})
+	return
+}
+
+// GetContact returns nil in case the contact doesn't exist
+func (wallet *Wallet) GetContact(name string) *WalletContact {
+
+	contacts, err := wallet.GetContacts()
+	if err != nil {
+		return nil
+	}
+
+	name = strings.TrimSpace(name)
+	for _, contact := range contacts {
+		if contact.Name == name {
+			return contact
+		}
+	}
+	return nil
+}
+
+// ResolveContact returns the contact in case nameOrAddress is the name of a contact
+func (wallet *Wallet) ResolveContact(nameOrAddress string) (string, *WalletContact) {
+	if contact := wallet.GetContact(nameOrAddress); contact != nil {
+		return contact.Address, contact
+	}
+	return nameOrAddress, nil
+}
+
+// getContactsNames maps public keys to contact names. It is used to label the counterparties in the history
+//must be locked before
+func (wallet *Wallet) getContactsNames(reader store_db_interface.StoreDBTransactionInterface) (map[string]string, error) {
+
+	contacts, err := wallet.loadContacts(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	for _, contact := range contacts {
+		if contact.publicKey != nil {
+			names[string(contact.publicKey)] = contact.Name
+		}
+	}
+	return names, nil
+}
+
+func (wallet *Wallet) AddContact(contact *WalletContact) error {
+
+	if err := contact.validate(); err != nil {
+		return err
+	}
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+
+	return wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var contacts []*WalletContact
+		if contacts, err = wallet.loadContacts(writer); err != nil {
+			return
+		}
+
+		for _, it := range contacts {
+			if it.Name == contact.Name {
+				return errors.New("Contact already exists")
+			}
+		}
+
+		return wallet.saveContacts(writer, append(contacts, contact))
+	})
+}
+
+func (wallet *Wallet) EditContact(name string, contact *WalletContact) error {
+
+	if err := contact.validate(); err != nil {
+		return err
+	}
+
+	name = strings.TrimSpace(name)
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+
+	return wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var contacts []*WalletContact
+		if contacts, err = wallet.loadContacts(writer); err != nil {
+			return
+		}
+
+		index := -1
+		for i, it := range contacts {
+			if it.Name == name {
+				index = i
+			} else if it.Name == contact.Name {
+				return errors.New("Contact already exists")
+			}
+		}
+		if index == -1 {
+			return errors.New("Contact was not found")
+		}
+
+		contacts[index] = contact
+		return wallet.saveContacts(writer, contacts)
+	})
+}
+
+func (wallet *Wallet) RemoveContact(name string) error {
+
+	name = strings.TrimSpace(name)
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+
+	return wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var contacts []*WalletContact
+		if contacts, err = wallet.loadContacts(writer); err != nil {
+			return
+		}
+
+		for i, it := range contacts {
+			if it.Name == name {
+				return wallet.saveContacts(writer, append(contacts[:i], contacts[i+1:]...))
+			}
+		}
+
+		return errors.New("Contact was not found")
+	})
+}
@@ -0,0 +1,382 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/helpers/totp"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const COSIGNER_LIMIT_PERIOD = int64(24 * 60 * 60) //the daily limit is computed over a rolling window
+
+type WalletCosignerPolicy struct {
+	DailyLimit        uint64   `json:"dailyLimit" msgpack:"dailyLimit"`                                   //amount, fee and burn spent in the last 24 hours. 0 means no limit
+	AllowedRecipients []string `json:"allowedRecipients,omitempty" msgpack:"allowedRecipients,omitempty"` //empty allows any recipient
+	RequireCode       bool     `json:"requireCode" msgpack:"requireCode"`                                 //TOTP confirmation code
+}
+
+type WalletCosignerSpending struct {
+	Timestamp int64  `msgpack:"timestamp"`
+	Amount    uint64 `msgpack:"amount"`
+	Hash      []byte `msgpack:"hash"` //hash of the data signed, used to avoid counting twice the same transaction
+}
+
+type WalletCosignerAccount struct {
+	Name            string                    `json:"name" msgpack:"name"`
+	SpendPublicKey  []byte                    `json:"spendPublicKey" msgpack:"spendPublicKey"`
+	SpendPrivateKey *addresses.PrivateKey     `json:"-" msgpack:"spendPrivateKey"`
+	TOTPSecret      []byte                    `json:"-" msgpack:"totpSecret,omitempty"`
+	TOTPGuard       *totp.Guard               `json:"-" msgpack:"totpGuard,omitempty"`
+	Policy          *WalletCosignerPolicy     `json:"policy" msgpack:"policy"`
+	Spendings       []*WalletCosignerSpending `json:"-" msgpack:"spendings"`
+	SpentToday      uint64                    `json:"spentToday" msgpack:"-"`
+	CreatedAt       int64                     `json:"createdAt" msgpack:"createdAt"`
+	recipients      map[string]bool
+}
+
+type WalletCosignerAuditEntry struct {
+	Timestamp      int64  `json:"timestamp" msgpack:"timestamp"`
+	Account        string `json:"account" msgpack:"account"`
+	SpendPublicKey []byte `json:"spendPublicKey" msgpack:"spendPublicKey"`
+	Recipient      string `json:"recipient,omitempty" msgpack:"recipient,omitempty"`
+	Amount         uint64 `json:"amount" msgpack:"amount"`
+	Fee            uint64 `json:"fee" msgpack:"fee"`
+	Burn           uint64 `json:"burn" msgpack:"burn"`
+	Hash           []byte `json:"hash,omitempty" msgpack:"hash,omitempty"`
+	Approved       bool   `json:"approved" msgpack:"approved"`
+	Reason         string `json:"reason,omitempty" msgpack:"reason,omitempty"`
+}
+
+func (policy *WalletCosignerPolicy) validate() error {
+
+	for i, recipient := range policy.AllowedRecipients {
+		policy.AllowedRecipients[i] = strings.TrimSpace(recipient)
+		if _, err := addresses.DecodeAddr(policy.AllowedRecipients[i]); err != nil {
+			return errors.New("Allowed recipient " + recipient + " is invalid")
+		}
+	}
+
+	return nil
+}
+
+// spentSince removes the spendings older than the limit period and returns the sum of the remaining ones
+func (account *WalletCosignerAccount) spentSince(now int64) (spent uint64) {
+
+	spendings := account.Spendings[:0]
+	for _, spending := range account.Spendings {
+		if spending.Timestamp > now-COSIGNER_LIMIT_PERIOD {
+			spendings = append(spendings, spending)
+			spent += spending.Amount
+		}
+	}
+	account.Spendings = spendings
+
+	return
+}
+
+func (account *WalletCosignerAccount) isRecipientAllowed(publicKey []byte) bool {
+
+	if len(account.Policy.AllowedRecipients) == 0 {
+		return true
+	}
+
+	if account.recipients == nil {
+		account.recipients = make(map[string]bool)
+		for _, recipient := range account.Policy.AllowedRecipients {
+			if address, err := addresses.DecodeAddr(recipient); err == nil {
+				account.recipients[string(address.PublicKey)] = true
+			}
+		}
+	}
+
+	return account.recipients[string(publicKey)]
+}
+
+//must be locked before
+func (wallet *Wallet) loadCosignerAccounts(reader store_db_interface.StoreDBTransactionInterface) ([]*WalletCosignerAccount, error) {
+
+	accounts := []*WalletCosignerAccount{}
+
+	data := reader.Get("walletCosignerAccounts")
+	if data == nil {
+		return accounts, nil
+	}
+
+	data, err := wallet.Encryption.decryptData(data)
+	if err != nil {
+		return nil, err
+	}
+	if err = msgpack.Unmarshal(data, &accounts); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+//must be locked before
+func (wallet *Wallet) saveCosignerAccounts(writer store_db_interface.StoreDBTransactionInterface, accounts []*WalletCosignerAccount) error {
+
+	data, err := msgpack.Marshal(accounts)
+	if err != nil {
+		return err
+	}
+	if data, err = wallet.Encryption.encryptData(data); err != nil {
+		return err
+	}
+
+	writer.Put("walletCosignerAccounts", data)
+	return nil
+}
+
+//must be locked before
+func (wallet *Wallet) loadCosignerAuditCount(reader store_db_interface.StoreDBTransactionInterface) (uint64, error) {
+
+	data := reader.Get("walletCosignerAuditCount")
+	if data == nil {
+		return 0, nil
+	}
+
+	data, err := wallet.Encryption.decryptData(data)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(string(data), 10, 64)
+}
+
+// addCosignerAuditEntry appends the entry to the audit log. Entries are never removed
+//must be locked before
+func (wallet *Wallet) addCosignerAuditEntry(writer store_db_interface.StoreDBTransactionInterface, entry *WalletCosignerAuditEntry) error {
+
+	count, err := wallet.loadCosignerAuditCount(writer)
+	if err != nil {
+		return err
+	}
+
+	data, err := msgpack.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if data, err = wallet.Encryption.encryptData(data); err != nil {
+		return err
+	}
+	writer.Put("walletCosignerAudit:"+strconv.FormatUint(count, 10), data)
+
+	if data, err = wallet.Encryption.encryptData([]byte(strconv.FormatUint(count+1, 10))); err != nil {
+		return err
+	}
+	writer.Put("walletCosignerAuditCount", data)
+
+	return nil
+}
+
+// readCosignerRaw adds the co-signer accounts and the audit log decrypted to out. It is used to encrypt them again when the wallet encryption is changed
+//must be locked before
+func (wallet *Wallet) readCosignerRaw(out map[string][]byte) error {
+	return wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var accounts []*WalletCosignerAccount
+		if accounts, err = wallet.loadCosignerAccounts(reader); err != nil {
+			return
+		}
+		if len(accounts) > 0 {
+			if out["walletCosignerAccounts"], err = msgpack.Marshal(accounts); err != nil {
+				return
+			}
+		}
+
+		var count uint64
+		if count, err = wallet.loadCosignerAuditCount(reader); err != nil || count == 0 {
+			return
+		}
+
+		for i := uint64(0); i < count; i++ {
+			key := "walletCosignerAudit:" + strconv.FormatUint(i, 10)
+			if out[key], err = wallet.Encryption.decryptData(reader.Get(key)); err != nil {
+				return
+			}
+		}
+
+		out["walletCosignerAuditCount"] = []byte(strconv.FormatUint(count, 10))
+		return
+	})
+}
+
+// CreateCosignerAccount generates a new spend key held by the co-signer. The TOTP secret is returned only once
+func (wallet *Wallet) CreateCosignerAccount(name string, policy *WalletCosignerPolicy) (account *WalletCosignerAccount, totpSecret []byte, err error) {
+
+	name = strings.TrimSpace(name)
+	if len(name) == 0 || len(name) > 64 {
+		return nil, nil, errors.New("Account name must have between 1 and 64 characters")
+	}
+	if policy == nil {
+		policy = &WalletCosignerPolicy{}
+	}
+	if err = policy.validate(); err != nil {
+		return
+	}
+
+	spendPrivateKey := addresses.GenerateNewPrivateKey()
+
+	if policy.RequireCode {
+		if totpSecret, err = totp.GenerateSecret(); err != nil {
+			return
+		}
+	}
+
+	account = &WalletCosignerAccount{
+		Name:            name,
+		SpendPublicKey:  spendPrivateKey.GeneratePublicKey(),
+		SpendPrivateKey: spendPrivateKey,
+		TOTPSecret:      totpSecret,
+		Policy:          policy,
+		Spendings:       []*WalletCosignerSpending{},
+		CreatedAt:       time.Now().Unix(),
+	}
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, nil, errors.New("Wallet was not loaded!")
+	}
+
+	if err = wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var accounts []*WalletCosignerAccount
+		if accounts, err = wallet.loadCosignerAccounts(writer); err != nil {
+			return
+		}
+
+		for _, it := range accounts {
+			if it.Name == name {
+				return errors.New("Account already exists")
+			}
+		}
+
+		return wallet.saveCosignerAccounts(writer, append(accounts, account))
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return
+}
+
+// UpdateCosignerAccount replaces the policy of the account. A new TOTP secret is returned when the confirmation codes are enabled
+func (wallet *Wallet) UpdateCosignerAccount(spendPublicKey []byte, policy *WalletCosignerPolicy) (totpSecret []byte, err error) {
+
+	if policy == nil {
+		return nil, errors.New("Policy is missing")
+	}
+	if err = policy.validate(); err != nil {
+		return
+	}
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, errors.New("Wallet was not loaded!")
+	}
+
+	err = wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var accounts []*WalletCosignerAccount
+		if accounts, err = wallet.loadCosignerAccounts(writer); err != nil {
+			return
+		}
+
+		for _, account := range accounts {
+			if bytes.Equal(account.SpendPublicKey, spendPublicKey) {
+
+				if policy.RequireCode && !account.Policy.RequireCode {
+					if totpSecret, err = totp.GenerateSecret(); err != nil {
+						return
+					}
+					account.TOTPSecret = totpSecret
+					account.TOTPGuard = nil
+				} else if !policy.RequireCode {
+					account.TOTPSecret = nil
+					account.TOTPGuard = nil
+				}
+
+				account.Policy = policy
+				return wallet.saveCosignerAccounts(writer, accounts)
+			}
+		}
+
+		return errors.New("Account was not found")
+	})
+	return
+}
+
+func (wallet *Wallet) GetCosignerAccounts() (accounts []*WalletCosignerAccount, err error) {
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, errors.New("Wallet was not loaded!")
+	}
+
+	if err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		accounts, err = wallet.loadCosignerAccounts(reader)
+		return
+	}); err != nil {
+		return
+	}
+
+	now := time.Now().Unix()
+	for _, account := range accounts {
+		account.SpentToday = account.spentSince(now)
+	}
+
+	return
+}
+
+// GetCosignerAuditLog returns the most recent entries first
+func (wallet *Wallet) GetCosignerAuditLog(start, count int) (out []*WalletCosignerAuditEntry, total int, err error) {
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, 0, errors.New("Wallet was not loaded!")
+	}
+
+	out = []*WalletCosignerAuditEntry{}
+
+	if err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var n uint64
+		if n, err = wallet.loadCosignerAuditCount(reader); err != nil {
+			return
+		}
+		total = int(n)
+
+		for i := total - 1 - start; i >= 0 && len(out) < count; i-- {
+
+			var data []byte
+			if data, err = wallet.Encryption.decryptData(reader.Get("walletCosignerAudit:" + strconv.Itoa(i))); err != nil {
+				return
+			}
+
+			entry := &WalletCosignerAuditEntry{}
+			if err = msgpack.Unmarshal(data, entry); err != nil {
+				return
+			}
+			out = append(out, entry)
+		}
+
+		return
+	}); err != nil {
+		return nil, 0, err
+	}
+
+	return
+}
@@ -0,0 +1,231 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/config/config_cosigner"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/totp"
+	"pandora-pay/store/store_db/store_db_interface"
+	"time"
+)
+
+// WalletCosignerSignRequest contains the transaction with an empty spend signature and the witness of the payload
+type WalletCosignerSignRequest struct {
+	Tx             []byte `json:"tx" msgpack:"tx"`
+	PayloadIndex   int    `json:"payloadIndex" msgpack:"payloadIndex"`
+	R              []byte `json:"r" msgpack:"r"`
+	SenderIndex    int    `json:"senderIndex" msgpack:"senderIndex"`
+	RecipientIndex int    `json:"recipientIndex" msgpack:"recipientIndex"`
+	Amount         uint64 `json:"amount" msgpack:"amount"`
+	Code           string `json:"code,omitempty" msgpack:"code,omitempty"`
+}
+
+// verifyCosignerWitness checks that the commitments of the statement are moving exactly Amount from the sender to the recipient
+func verifyCosignerWitness(payload *transaction_zether_payload.TransactionZetherPayload, request *WalletCosignerSignRequest) error {
+
+	statement := payload.Statement
+	ringSize := len(statement.Publickeylist)
+
+	if request.SenderIndex < 0 || request.SenderIndex >= ringSize || request.RecipientIndex < 0 || request.RecipientIndex >= ringSize {
+		return errors.New("Witness indexes are invalid")
+	}
+	if (request.SenderIndex%2 == 0) != payload.Parity || (request.RecipientIndex%2 == 0) == payload.Parity {
+		return errors.New("Witness indexes are not matching the parity")
+	}
+	if len(statement.C) != ringSize || statement.D == nil {
+		return errors.New("Statement is invalid")
+	}
+
+	r := new(big.Int).SetBytes(request.R)
+	if new(bn256.G1).ScalarMult(crypto.G, r).String() != statement.D.String() {
+		return errors.New("Witness is not matching the statement")
+	}
+
+	for i, publicKey := range statement.Publickeylist {
+
+		var x bn256.G1
+		switch i {
+		case request.SenderIndex:
+			x.ScalarMult(crypto.G, new(big.Int).SetInt64(0-int64(request.Amount)-int64(statement.Fee)-int64(payload.BurnValue)))
+		case request.RecipientIndex:
+			x.ScalarMult(crypto.G, new(big.Int).SetInt64(int64(request.Amount)))
+		default:
+			x.ScalarMult(crypto.G, new(big.Int).SetInt64(0))
+		}
+		x.Add(new(bn256.G1).Set(&x), new(bn256.G1).ScalarMult(publicKey, r))
+
+		if x.String() != statement.C[i].String() {
+			return errors.New("Witness amount is not matching the statement")
+		}
+	}
+
+	return nil
+}
+
+// authorize enforces the policy of the account
+func (account *WalletCosignerAccount) authorize(payload *transaction_zether_payload.TransactionZetherPayload, request *WalletCosignerSignRequest, hash []byte, now time.Time) (uint64, error) {
+
+	if err := verifyCosignerWitness(payload, request); err != nil {
+		return 0, err
+	}
+
+	if !account.isRecipientAllowed(payload.Statement.Publickeylist[request.RecipientIndex].EncodeCompressed()) {
+		return 0, errors.New("Recipient is not allowed")
+	}
+
+	total := request.Amount
+	if err := helpers.SafeUint64Add(&total, payload.Statement.Fee); err != nil {
+		return 0, err
+	}
+	if err := helpers.SafeUint64Add(&total, payload.BurnValue); err != nil {
+		return 0, err
+	}
+
+	spent := account.spentSince(now.Unix())
+	for _, spending := range account.Spendings {
+		if bytes.Equal(spending.Hash, hash) { //the same transaction signed again
+			total = 0
+			break
+		}
+	}
+
+	if account.Policy.DailyLimit > 0 && total > 0 {
+		if err := helpers.SafeUint64Add(&spent, total); err != nil || spent > account.Policy.DailyLimit {
+			return 0, errors.New("Daily limit exceeded")
+		}
+	}
+
+	//the code is checked last, so it is consumed only by a request that is going to be signed
+	if account.Policy.RequireCode {
+		if account.TOTPGuard == nil {
+			account.TOTPGuard = &totp.Guard{}
+		}
+		if err := account.TOTPGuard.Validate(account.TOTPSecret, request.Code, now, config_cosigner.COSIGNER_TOTP_MAX_FAILED_ATTEMPTS, config_cosigner.COSIGNER_TOTP_LOCKOUT); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
+
+// CosignerSign returns the spend signature of the payload in case the policy of the account allows it. Every request of a known account is stored in the audit log
+func (wallet *Wallet) CosignerSign(request *WalletCosignerSignRequest) (signature []byte, err error) {
+
+	tx := &transaction.Transaction{}
+	if err = tx.Deserialize(advanced_buffers.NewBufferReader(request.Tx)); err != nil {
+		return
+	}
+	if tx.Version != transaction_type.TX_ZETHER {
+		return nil, errors.New("Only zether transactions can be co-signed")
+	}
+
+	txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+	if request.PayloadIndex < 0 || request.PayloadIndex >= len(txBase.Payloads) {
+		return nil, errors.New("Payload index is invalid")
+	}
+
+	payload := txBase.Payloads[request.PayloadIndex]
+	if payload.PayloadScript != transaction_zether_payload_script.SCRIPT_SPEND {
+		return nil, errors.New("Payload is not a spend payload")
+	}
+
+	spendPublicKey := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend).SenderSpendPublicKey.EncodeCompressed()
+
+	//the signature covers the whole transaction, hence it would also authorize the other payloads of the same Spend Public Key without checking their witness
+	for t, it := range txBase.Payloads {
+		if t != request.PayloadIndex && it.PayloadScript == transaction_zether_payload_script.SCRIPT_SPEND &&
+			bytes.Equal(it.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend).SenderSpendPublicKey.EncodeCompressed(), spendPublicKey) {
+			return nil, errors.New("Only one payload can use the Spend Public Key of the account")
+		}
+	}
+
+	hash := tx.SerializeForSigning()
+	now := time.Now()
+
+	entry := &WalletCosignerAuditEntry{
+		Timestamp:      now.Unix(),
+		SpendPublicKey: spendPublicKey,
+		Amount:         request.Amount,
+		Fee:            payload.Statement.Fee,
+		Burn:           payload.BurnValue,
+		Hash:           hash,
+	}
+	if request.RecipientIndex >= 0 && request.RecipientIndex < len(payload.Statement.Publickeylist) {
+		var recipient *addresses.Address
+		if recipient, err = addresses.CreateAddr(payload.Statement.Publickeylist[request.RecipientIndex].EncodeCompressed(), false, nil, nil, nil, 0, nil); err != nil {
+			return
+		}
+		entry.Recipient = recipient.EncodeAddr()
+	}
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, errors.New("Wallet was not loaded!")
+	}
+
+	if err = wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var accounts []*WalletCosignerAccount
+		if accounts, err = wallet.loadCosignerAccounts(writer); err != nil {
+			return
+		}
+
+		var account *WalletCosignerAccount
+		for _, it := range accounts {
+			if bytes.Equal(it.SpendPublicKey, spendPublicKey) {
+				account = it
+				break
+			}
+		}
+		if account == nil {
+			return errors.New("Account was not found")
+		}
+		entry.Account = account.Name
+
+		total, reason := account.authorize(payload, request, hash, now)
+		if reason != nil {
+			entry.Reason = reason.Error()
+			if account.Policy.RequireCode { //the failed attempts of the confirmation codes are persisted
+				if err = wallet.saveCosignerAccounts(writer, accounts); err != nil {
+					return
+				}
+			}
+			return wallet.addCosignerAuditEntry(writer, entry)
+		}
+
+		if signature, err = account.SpendPrivateKey.Sign(hash); err != nil {
+			return
+		}
+		entry.Approved = true
+
+		if total > 0 {
+			account.Spendings = append(account.Spendings, &WalletCosignerSpending{now.Unix(), total, hash})
+		}
+		if err = wallet.saveCosignerAccounts(writer, accounts); err != nil {
+			return
+		}
+
+		return wallet.addCosignerAuditEntry(writer, entry)
+	}); err != nil {
+		return nil, err
+	}
+
+	if !entry.Approved {
+		return nil, errors.New(entry.Reason)
+	}
+	return
+}
@@ -34,7 +34,19 @@ type DecryptedTx struct {
 	ZetherTx *DecryptTxZether                    `json:"zetherTx" msgpack:"zetherTx"`
 }
 
-func (w *Wallet) DecryptTx(tx *transaction.Transaction, walletPublicKey []byte) (*DecryptedTx, error) {
+// computeZetherPayloadR derives the r used by the sender for the payload t out of the sender secret
+func computeZetherPayloadR(txBase *transaction_zether.TransactionZether, t int, secret *big.Int) *big.Int {
+
+	rinputs := append([]byte{}, txBase.ChainKernelHash...)
+	for _, publicKey := range txBase.Bloom.PublicKeyLists[t] {
+		rinputs = append(rinputs, publicKey...)
+	}
+
+	rencrypted := new(bn256.G1).ScalarMult(crypto.HashToPoint(crypto.HashtoNumber(append([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT), rinputs...))), secret)
+	return crypto.ReducedHash(rencrypted.EncodeCompressed())
+}
+
+func (w *Wallet) DecryptTx(tx *transaction.Transaction, walletPublicKey []byte, lock bool) (*DecryptedTx, error) {
 
 	if tx == nil {
 		return nil, errors.New("Transaction is invalid")
@@ -62,7 +74,7 @@ func (w *Wallet) DecryptTx(tx *transaction.Transaction, walletPublicKey []byte)
 					continue
 				}
 
-				if addr := w.GetWalletAddressByPublicKey(publicKey, true); addr != nil {
+				if addr := w.GetWalletAddressByPublicKey(publicKey, lock); addr != nil {
 
 					decyptedZetherPayload := &DecryptZetherPayloadOutput{
 						RecipientIndex: -1,
@@ -105,13 +117,7 @@ func (w *Wallet) DecryptTx(tx *transaction.Transaction, walletPublicKey []byte)
 
 					if output.ZetherTx.Payloads[t].WhisperSenderValid {
 
-						rinputs := append([]byte{}, txBase.ChainKernelHash...)
-						for _, publicKey2 := range txBase.Bloom.PublicKeyLists[t] {
-							rinputs = append(rinputs, publicKey2...)
-						}
-
-						rencrypted := new(bn256.G1).ScalarMult(crypto.HashToPoint(crypto.HashtoNumber(append([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT), rinputs...))), secretPoint.BigInt())
-						r := crypto.ReducedHash(rencrypted.EncodeCompressed())
+						r := computeZetherPayloadR(txBase, t, secretPoint.BigInt())
 
 						parity := payload.Proof.Parity()
 						for k := range payload.Statement.C {
@@ -0,0 +1,386 @@
+package wallet
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/accounts"
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/config/config_stake"
+	"pandora-pay/config/globals"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/wallet/wallet_address"
+)
+
+// WalletDelegation is a stake delegated to this node. The shared staked private key is stored in the wallet address
+type WalletDelegation struct {
+	PublicKey      helpers.Base64 `json:"publicKey" msgpack:"publicKey"`
+	StakedBalance  uint64         `json:"stakedBalance" msgpack:"stakedBalance"`   //balance decrypted at the last check
+	AcceptedHeight uint64         `json:"acceptedHeight" msgpack:"acceptedHeight"` //0 for the delegations accepted before they were recorded
+	CheckedHeight  uint64         `json:"checkedHeight" msgpack:"checkedHeight"`
+	FeeRate        uint64         `json:"feeRate" msgpack:"feeRate"` //commission agreed by the delegator, in config_nodes.DELEGATOR_FEE_DIVISOR parts
+}
+
+// loadDelegations also returns the shared staked addresses which were accepted without a record
+//must be locked before
+func (wallet *Wallet) loadDelegations(reader store_db_interface.StoreDBTransactionInterface) ([]*WalletDelegation, error) {
+
+	delegations := []*WalletDelegation{}
+
+	if data := reader.Get("walletDelegations"); data != nil {
+		data, err := wallet.Encryption.decryptData(data)
+		if err != nil {
+			return nil, err
+		}
+		if err = msgpack.Unmarshal(data, &delegations); err != nil {
+			return nil, err
+		}
+	}
+
+	recorded := make(map[string]bool)
+	for _, delegation := range delegations {
+		recorded[string(delegation.PublicKey)] = true
+	}
+	for _, addr := range wallet.Addresses {
+		if addr.IsSharedStaked && !recorded[string(addr.PublicKey)] {
+			delegations = append(delegations, &WalletDelegation{PublicKey: addr.PublicKey})
+		}
+	}
+
+	return delegations, nil
+}
+
+//must be locked before
+func (wallet *Wallet) saveDelegations(writer store_db_interface.StoreDBTransactionInterface, delegations []*WalletDelegation) error {
+
+	data, err := msgpack.Marshal(delegations)
+	if err != nil {
+		return err
+	}
+	if data, err = wallet.Encryption.encryptData(data); err != nil {
+		return err
+	}
+
+	writer.Put("walletDelegations", data)
+	return nil
+}
+
+// readDelegationsRaw adds the delegations decrypted to out. It is used to encrypt the delegations again when the wallet encryption is changed
+//must be locked before
+func (wallet *Wallet) readDelegationsRaw(out map[string][]byte) error {
+	return wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		delegations, err := wallet.loadDelegations(reader)
+		if err != nil || len(delegations) == 0 {
+			return
+		}
+		out["walletDelegations"], err = msgpack.Marshal(delegations)
+		return
+	})
+}
+
+func (wallet *Wallet) GetDelegations() (delegations []*WalletDelegation, err error) {
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, errors.New("Wallet was not loaded!")
+	}
+
+	err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		delegations, err = wallet.loadDelegations(reader)
+		return
+	})
+	return
+}
+
+// AddDelegation accepts the delegated stake. When DELEGATES_MAXIMUM is reached, the delegation with the smallest stake is evicted if its stake is smaller than the new one
+func (wallet *Wallet) AddDelegation(addr *wallet_address.WalletAddress, stakedBalance, feeRate, chainHeight uint64) (err error) {
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	if !wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+
+	var delegations []*WalletDelegation
+	if err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		delegations, err = wallet.loadDelegations(reader)
+		return
+	}); err != nil {
+		return
+	}
+
+	for len(delegations) >= config_nodes.DELEGATES_MAXIMUM && len(delegations) > 0 {
+
+		smallest := 0
+		for i, delegation := range delegations {
+			if delegation.StakedBalance < delegations[smallest].StakedBalance {
+				smallest = i
+			}
+		}
+
+		if delegations[smallest].StakedBalance >= stakedBalance {
+			return errors.New("DELEGATES_MAXIMUM exceeded")
+		}
+
+		evicted := delegations[smallest]
+		delegations = append(delegations[:smallest], delegations[smallest+1:]...)
+		if err = wallet.removeDelegatedAddress(evicted.PublicKey, len(delegations)); err != nil {
+			return
+		}
+		gui.GUI.Info("Delegation evicted", base64.StdEncoding.EncodeToString(evicted.PublicKey))
+	}
+
+	wallet.DelegatesCount = len(delegations)
+	if err = wallet.AddSharedStakedAddress(addr, false); err != nil {
+		return
+	}
+
+	delegations = append(delegations, &WalletDelegation{addr.PublicKey, stakedBalance, chainHeight, chainHeight, feeRate})
+
+	return wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		return wallet.saveDelegations(writer, delegations)
+	})
+}
+
+// GetDelegationCommission returns the commission rate agreed for the delegated stake and the address receiving it. The rate is 0 for the own stakes and the delegations without a commission
+func (wallet *Wallet) GetDelegationCommission(publicKey []byte) (feeRate uint64, address string, err error) {
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return
+	}
+
+	if addr := wallet.addressesMap[string(publicKey)]; addr == nil || !addr.IsSharedStaked {
+		return
+	}
+
+	var delegations []*WalletDelegation
+	if err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		delegations, err = wallet.loadDelegations(reader)
+		return
+	}); err != nil {
+		return
+	}
+
+	for _, delegation := range delegations {
+		if string(delegation.PublicKey) == string(publicKey) {
+			feeRate = delegation.FeeRate
+			break
+		}
+	}
+
+	if feeRate == 0 {
+		return
+	}
+
+	if address = config_nodes.DELEGATOR_FEE_ADDRESS; address != "" {
+		return
+	}
+
+	for _, addr := range wallet.Addresses {
+		if !addr.IsSharedStaked {
+			return feeRate, addr.AddressRegistrationEncoded, nil
+		}
+	}
+
+	return 0, "", errors.New("There is no wallet address to receive the delegator commission")
+}
+
+// removeDelegatedAddress removes the shared staked address and stops forging with it
+//must be locked before
+func (wallet *Wallet) removeDelegatedAddress(publicKey []byte, delegatesCount int) (err error) {
+
+	wallet.DelegatesCount = delegatesCount
+
+	addr := wallet.addressesMap[string(publicKey)]
+	if addr == nil || !addr.IsSharedStaked {
+		return nil
+	}
+
+	if _, err = wallet.RemoveAddressByPublicKey(publicKey, false); err != nil {
+		return
+	}
+
+	globals.MainEvents.BroadcastEvent("wallet/delegation-removed", publicKey)
+	return
+}
+
+// RevokeDelegation removes the delegated stake. It returns false in case the delegation doesn't exist
+func (wallet *Wallet) RevokeDelegation(publicKey []byte) (bool, error) {
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	if !wallet.Loaded {
+		return false, errors.New("Wallet was not loaded!")
+	}
+
+	return wallet.revokeDelegation(publicKey)
+}
+
+//must be locked before
+func (wallet *Wallet) revokeDelegation(publicKey []byte) (found bool, err error) {
+
+	var delegations []*WalletDelegation
+	if err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		delegations, err = wallet.loadDelegations(reader)
+		return
+	}); err != nil {
+		return
+	}
+
+	for i, delegation := range delegations {
+		if string(delegation.PublicKey) == string(publicKey) {
+			delegations = append(delegations[:i], delegations[i+1:]...)
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return
+	}
+
+	if err = wallet.removeDelegatedAddress(publicKey, len(delegations)); err != nil {
+		return
+	}
+
+	err = wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		return wallet.saveDelegations(writer, delegations)
+	})
+	return
+}
+
+// ExpireDelegations revokes the delegations which are no longer staked or whose balance is below the required stake
+func (wallet *Wallet) ExpireDelegations(ctx context.Context) (err error) {
+
+	type delegationCheck struct {
+		delegation *WalletDelegation
+		addr       *wallet_address.WalletAddress
+		acc        *account.Account
+		expired    bool
+	}
+
+	wallet.Lock.RLock()
+	if !wallet.Loaded {
+		wallet.Lock.RUnlock()
+		return
+	}
+
+	var delegations []*WalletDelegation
+	if err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		delegations, err = wallet.loadDelegations(reader)
+		return
+	}); err != nil || len(delegations) == 0 {
+		wallet.Lock.RUnlock()
+		return
+	}
+
+	var chainHeight uint64
+	checks := make([]*delegationCheck, len(delegations))
+
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		chainHeight, _ = binary.Uvarint(reader.Get("chainHeight"))
+		dataStorage := data_storage.NewDataStorage(reader)
+
+		var accs *accounts.Accounts
+		if accs, err = dataStorage.AccsCollection.GetMap(config_coins.NATIVE_ASSET_FULL); err != nil {
+			return
+		}
+
+		for i, delegation := range delegations {
+
+			checks[i] = &delegationCheck{delegation: delegation}
+			if addr := wallet.addressesMap[string(delegation.PublicKey)]; addr != nil {
+				checks[i].addr = addr.Clone()
+			}
+
+			var reg *registration.Registration
+			if reg, err = dataStorage.Regs.Get(string(delegation.PublicKey)); err != nil {
+				return
+			}
+			if checks[i].acc, err = accs.Get(string(delegation.PublicKey)); err != nil {
+				return
+			}
+
+			checks[i].expired = checks[i].addr == nil || reg == nil || !reg.Staked || checks[i].acc == nil
+		}
+
+		return
+	})
+	wallet.Lock.RUnlock()
+
+	if err != nil {
+		return
+	}
+
+	requiredStake := config_stake.GetRequiredStake(chainHeight)
+
+	for _, check := range checks {
+
+		if check.expired {
+			continue
+		}
+
+		if check.delegation.StakedBalance == 0 || !check.addr.PrivateKey.TryDecryptBalance(check.acc.Balance.Amount, check.delegation.StakedBalance) {
+			if check.delegation.StakedBalance, err = wallet.DecryptBalance(check.addr, check.acc.Balance.Amount.Serialize(), config_coins.NATIVE_ASSET_FULL, false, 0, false, ctx, func(string) {}); err != nil {
+				return
+			}
+		}
+
+		check.delegation.CheckedHeight = chainHeight
+		check.expired = check.delegation.StakedBalance < requiredStake
+	}
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	if !wallet.Loaded {
+		return
+	}
+
+	for _, check := range checks {
+		if check.expired {
+			if _, err = wallet.revokeDelegation(check.delegation.PublicKey); err != nil {
+				return
+			}
+			gui.GUI.Info("Delegation expired", base64.StdEncoding.EncodeToString(check.delegation.PublicKey))
+		}
+	}
+
+	//the delegations could have been changed while the balances were decrypted
+	if err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		delegations, err = wallet.loadDelegations(reader)
+		return
+	}); err != nil {
+		return
+	}
+
+	for _, delegation := range delegations {
+		for _, check := range checks {
+			if string(check.delegation.PublicKey) == string(delegation.PublicKey) {
+				delegation.StakedBalance = check.delegation.StakedBalance
+				delegation.CheckedHeight = check.delegation.CheckedHeight
+			}
+		}
+	}
+
+	return wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		return wallet.saveDelegations(writer, delegations)
+	})
+}
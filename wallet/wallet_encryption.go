@@ -39,6 +39,23 @@ func (self *WalletEncryption) Encrypt(newPassword string, difficulty int) (err e
 		return errors.New("Difficulty must be in the interval [1,10]")
 	}
 
+	history, err := self.wallet.readHistoryRaw()
+	if err != nil {
+		return
+	}
+	if err = self.wallet.readInvoicesRaw(history); err != nil {
+		return
+	}
+	if err = self.wallet.readContactsRaw(history); err != nil {
+		return
+	}
+	if err = self.wallet.readCosignerRaw(history); err != nil {
+		return
+	}
+	if err = self.wallet.readDelegationsRaw(history); err != nil {
+		return
+	}
+
 	self.Encrypted = ENCRYPTED_VERSION_ENCRYPTION_ARGON2
 	self.password = newPassword
 	self.Salt = helpers.RandomBytes(32)
@@ -51,6 +68,9 @@ func (self *WalletEncryption) Encrypt(newPassword string, difficulty int) (err e
 	if err = self.wallet.saveWalletEntire(false); err != nil {
 		return
 	}
+	if err = self.wallet.writeHistoryRaw(history); err != nil {
+		return
+	}
 
 	globals.MainEvents.BroadcastEvent("wallet/encrypted", true)
 	return
@@ -116,6 +136,23 @@ func (self *WalletEncryption) RemoveEncryption() (err error) {
 		return errors.New("Wallet is not encrypted!")
 	}
 
+	history, err := self.wallet.readHistoryRaw()
+	if err != nil {
+		return
+	}
+	if err = self.wallet.readInvoicesRaw(history); err != nil {
+		return
+	}
+	if err = self.wallet.readContactsRaw(history); err != nil {
+		return
+	}
+	if err = self.wallet.readCosignerRaw(history); err != nil {
+		return
+	}
+	if err = self.wallet.readDelegationsRaw(history); err != nil {
+		return
+	}
+
 	self.Encrypted = ENCRYPTED_VERSION_PLAIN_TEXT
 	self.password = ""
 	self.Difficulty = 0
@@ -123,6 +160,9 @@ func (self *WalletEncryption) RemoveEncryption() (err error) {
 	if err = self.wallet.saveWalletEntire(false); err != nil {
 		return
 	}
+	if err = self.wallet.writeHistoryRaw(history); err != nil {
+		return
+	}
 
 	globals.MainEvents.BroadcastEvent("wallet/removed-encryption", true)
 	return
@@ -138,6 +178,7 @@ func (self *WalletEncryption) Logout() (err error) {
 		self.wallet.Lock.Unlock()
 		return errors.New("Wallet is not encrypted!")
 	}
+	self.wallet.wipeKeys()
 	self.wallet.clearWallet()
 	self.wallet.Lock.Unlock()
 
@@ -16,65 +16,78 @@ import (
 	"time"
 )
 
-func (wallet *Wallet) processRefreshWallets() {
+// refreshForgingWallet checks 50 random addresses of the wallet if they are still able to forge
+func (wallet *Wallet) refreshForgingWallet() (err error) {
 
-	recovery.SafeGo(func() {
-		var err error
+	if wallet.GetAddressesCount() == 0 {
+		return
+	}
 
-		for {
+	accsList := []*account.Account{}
+	regsList := []*registration.Registration{}
+	addressesList := []*wallet_address.WalletAddress{}
+	var chainHeight uint64
 
-			if config_forging.FORGING_ENABLED {
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
 
-				accsList := []*account.Account{}
-				regsList := []*registration.Registration{}
-				addressesList := []*wallet_address.WalletAddress{}
-				var chainHeight uint64
+		chainHeight, _ = binary.Uvarint(reader.Get("chainHeight"))
 
-				if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		dataStorage := data_storage.NewDataStorage(reader)
 
-					chainHeight, _ = binary.Uvarint(reader.Get("chainHeight"))
+		var accs *accounts.Accounts
+		if accs, err = dataStorage.AccsCollection.GetMap(config_coins.NATIVE_ASSET_FULL); err != nil {
+			return
+		}
 
-					dataStorage := data_storage.NewDataStorage(reader)
+		visited := make(map[string]bool)
+		for i := 0; i < 50; i++ {
+			addr := wallet.GetRandomAddress()
+			if visited[string(addr.PublicKey)] {
+				continue
+			}
+			visited[string(addr.PublicKey)] = true
 
-					var accs *accounts.Accounts
-					if accs, err = dataStorage.AccsCollection.GetMap(config_coins.NATIVE_ASSET_FULL); err != nil {
-						return
-					}
+			var acc *account.Account
+			var reg *registration.Registration
 
-					visited := make(map[string]bool)
-					for i := 0; i < 50; i++ {
-						addr := wallet.GetRandomAddress()
-						if visited[string(addr.PublicKey)] {
-							continue
-						}
-						visited[string(addr.PublicKey)] = true
-
-						var acc *account.Account
-						var reg *registration.Registration
-
-						if acc, err = accs.Get(string(addr.PublicKey)); err != nil {
-							return
-						}
-						if reg, err = dataStorage.Regs.Get(string(addr.PublicKey)); err != nil {
-							return
-						}
-
-						accsList = append(accsList, acc)
-						regsList = append(regsList, reg)
-						addressesList = append(addressesList, addr)
-					}
+			if acc, err = accs.Get(string(addr.PublicKey)); err != nil {
+				return
+			}
+			if reg, err = dataStorage.Regs.Get(string(addr.PublicKey)); err != nil {
+				return
+			}
 
-					return
-				}); err != nil {
-					gui.GUI.Error("Error processRefreshWallets", err)
-				}
+			accsList = append(accsList, acc)
+			regsList = append(regsList, reg)
+			addressesList = append(addressesList, addr)
+		}
 
-				for i, acc := range accsList {
-					if err = wallet.refreshWalletAccount(acc, regsList[i], chainHeight, addressesList[i]); err != nil {
+		return
+	}); err != nil {
+		gui.GUI.Error("Error processRefreshWallets", err)
+	}
+
+	for i, acc := range accsList {
+		if err = wallet.refreshWalletAccount(acc, regsList[i], chainHeight, addressesList[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (wallet *Wallet) processRefreshWallets() {
+
+	recovery.SafeGo(func() {
+
+		for {
+
+			if config_forging.FORGING_ENABLED {
+				for _, loaded := range wallet.getLoadedWallets() {
+					if err := loaded.refreshForgingWallet(); err != nil {
 						return
 					}
 				}
-
 			}
 
 			time.Sleep(2 * time.Minute)
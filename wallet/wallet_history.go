@@ -0,0 +1,503 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/info"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_data"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/config"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/helpers/multicast"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+)
+
+type WalletHistoryPayload struct {
+	Asset     []byte `json:"asset" msgpack:"asset"`
+	Sent      bool   `json:"sent" msgpack:"sent"`
+	Amount    uint64 `json:"amount" msgpack:"amount"` //without the fee
+	Fee       uint64 `json:"fee" msgpack:"fee"`       //paid only by the sender
+	PaymentID []byte `json:"paymentID" msgpack:"paymentID"`
+	Message   []byte `json:"message" msgpack:"message"`
+	Recipient []byte `json:"recipient,omitempty" msgpack:"recipient,omitempty"` //public key of the recipient, known only by the sender
+	Contact   string `json:"contact,omitempty" msgpack:"-"`
+}
+
+type WalletHistoryTx struct {
+	TxHash         []byte                  `json:"txHash" msgpack:"txHash"`
+	BlockHeight    uint64                  `json:"blockHeight" msgpack:"blockHeight"`
+	BlockTimestamp uint64                  `json:"blockTimestamp" msgpack:"blockTimestamp"`
+	Confirmations  uint64                  `json:"confirmations" msgpack:"-"`
+	Payloads       []*WalletHistoryPayload `json:"payloads" msgpack:"payloads"`
+}
+
+// walletHistoryList keeps the order in which the transactions were included
+type walletHistoryList struct {
+	Indexed uint64   `msgpack:"indexed"` //number of addrTx: entries already processed
+	Txs     [][]byte `msgpack:"txs"`
+}
+
+//must be locked before
+func (wallet *Wallet) loadHistoryList(reader store_db_interface.StoreDBTransactionInterface, publicKey []byte) (*walletHistoryList, error) {
+
+	list := &walletHistoryList{0, [][]byte{}}
+
+	data := reader.Get("walletHistory:" + string(publicKey))
+	if data == nil {
+		return list, nil
+	}
+
+	data, err := wallet.Encryption.decryptData(data)
+	if err != nil {
+		return nil, err
+	}
+	if err = msgpack.Unmarshal(data, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+//must be locked before
+func (wallet *Wallet) saveHistoryList(writer store_db_interface.StoreDBTransactionInterface, publicKey []byte, list *walletHistoryList) error {
+
+	data, err := msgpack.Marshal(list)
+	if err != nil {
+		return err
+	}
+	if data, err = wallet.Encryption.encryptData(data); err != nil {
+		return err
+	}
+
+	writer.Put("walletHistory:"+string(publicKey), data)
+	return nil
+}
+
+//must be locked before
+func (wallet *Wallet) saveHistoryTx(writer store_db_interface.StoreDBTransactionInterface, publicKey []byte, historyTx *WalletHistoryTx) error {
+
+	list, err := wallet.loadHistoryList(writer, publicKey)
+	if err != nil {
+		return err
+	}
+
+	key := "walletHistoryTx:" + string(publicKey) + ":" + string(historyTx.TxHash)
+	if !writer.Exists(key) {
+		list.Txs = append(list.Txs, historyTx.TxHash)
+		if err = wallet.saveHistoryList(writer, publicKey, list); err != nil {
+			return err
+		}
+	}
+
+	data, err := msgpack.Marshal(historyTx)
+	if err != nil {
+		return err
+	}
+	if data, err = wallet.Encryption.encryptData(data); err != nil {
+		return err
+	}
+
+	writer.Put(key, data)
+	return nil
+}
+
+//must be locked before
+func (wallet *Wallet) removeHistoryTx(writer store_db_interface.StoreDBTransactionInterface, publicKey, txHash []byte) error {
+
+	key := "walletHistoryTx:" + string(publicKey) + ":" + string(txHash)
+	if !writer.Exists(key) {
+		return nil
+	}
+
+	list, err := wallet.loadHistoryList(writer, publicKey)
+	if err != nil {
+		return err
+	}
+
+	txs := list.Txs[:0]
+	for _, hash := range list.Txs {
+		if !bytes.Equal(hash, txHash) {
+			txs = append(txs, hash)
+		}
+	}
+	list.Txs = txs
+
+	writer.Delete(key)
+	return wallet.saveHistoryList(writer, publicKey, list)
+}
+
+//must be locked before
+func (wallet *Wallet) deleteHistory(writer store_db_interface.StoreDBTransactionInterface, publicKey []byte) error {
+
+	list, err := wallet.loadHistoryList(writer, publicKey)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range list.Txs {
+		writer.Delete("walletHistoryTx:" + string(publicKey) + ":" + string(hash))
+	}
+	writer.Delete("walletHistory:" + string(publicKey))
+
+	return nil
+}
+
+// readHistoryRaw returns all the history decrypted. It is used to encrypt the history again when the wallet encryption is changed
+//must be locked before
+func (wallet *Wallet) readHistoryRaw() (out map[string][]byte, err error) {
+
+	out = make(map[string][]byte)
+
+	err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		for _, addr := range wallet.Addresses {
+
+			var list *walletHistoryList
+			if list, err = wallet.loadHistoryList(reader, addr.PublicKey); err != nil {
+				return
+			}
+
+			for _, hash := range list.Txs {
+				key := "walletHistoryTx:" + string(addr.PublicKey) + ":" + string(hash)
+				if out[key], err = wallet.Encryption.decryptData(reader.Get(key)); err != nil {
+					return
+				}
+			}
+
+			if out["walletHistory:"+string(addr.PublicKey)], err = msgpack.Marshal(list); err != nil {
+				return
+			}
+		}
+		return
+	})
+
+	return
+}
+
+//must be locked before
+func (wallet *Wallet) writeHistoryRaw(data map[string][]byte) error {
+	return wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+		for key, value := range data {
+			if value, err = wallet.Encryption.encryptData(value); err != nil {
+				return
+			}
+			writer.Put(key, value)
+		}
+		return
+	})
+}
+
+// decryptHistoryTx returns nil in case the address didn't send or receive anything in the transaction
+//must be locked before
+func (wallet *Wallet) decryptHistoryTx(tx *transaction.Transaction, publicKey []byte, blockHeight, blockTimestamp uint64) (*WalletHistoryTx, error) {
+
+	if tx.Version != transaction_type.TX_ZETHER {
+		return nil, nil
+	}
+
+	decrypted, err := wallet.DecryptTx(tx, publicKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	historyTx := &WalletHistoryTx{
+		TxHash:         tx.Bloom.Hash,
+		BlockHeight:    blockHeight,
+		BlockTimestamp: blockTimestamp,
+		Payloads:       []*WalletHistoryPayload{},
+	}
+
+	txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+
+	for t, payload := range decrypted.ZetherTx.Payloads {
+		if payload == nil {
+			continue
+		}
+
+		//the PaymentID is sent only in the encrypted data
+		var paymentID, message []byte
+		if txBase.Payloads[t].DataVersion == transaction_data.TX_DATA_ENCRYPTED {
+			paymentID, message = addresses.DecodePaymentIDData(payload.Message)
+		} else {
+			message = payload.Message
+		}
+
+		if payload.WhisperSenderValid {
+			fee := txBase.Payloads[t].Statement.Fee
+			if payload.SentAmount < fee {
+				fee = payload.SentAmount
+			}
+			var recipient []byte
+			if payload.RecipientIndex >= 0 {
+				recipient = txBase.Bloom.PublicKeyLists[t][payload.RecipientIndex]
+			}
+			historyTx.Payloads = append(historyTx.Payloads, &WalletHistoryPayload{payload.Asset, true, payload.SentAmount - fee, fee, paymentID, message, recipient, ""})
+		} else if payload.WhisperRecipientValid && payload.ReceivedAmount > 0 { //ring members also decrypt to zero
+			historyTx.Payloads = append(historyTx.Payloads, &WalletHistoryPayload{payload.Asset, false, payload.ReceivedAmount, 0, paymentID, message, nil, ""})
+		}
+	}
+
+	if len(historyTx.Payloads) == 0 {
+		return nil, nil
+	}
+
+	return historyTx, nil
+}
+
+func (wallet *Wallet) processHistoryUpdates(updates []*blockchain_types.BlockchainTransactionUpdate) error {
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil
+	}
+
+	chainHeight, err := wallet.getChainHeight()
+	if err != nil {
+		return err
+	}
+
+	invoices := make(map[string]*WalletInvoice)
+
+	if err = wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		for _, update := range updates {
+
+			if !update.Inserted {
+				for _, addr := range wallet.Addresses {
+					if err = wallet.removeInvoicesPayments(writer, addr.PublicKey, update.TxHash, chainHeight, invoices); err != nil {
+						return
+					}
+					if err = wallet.removeHistoryTx(writer, addr.PublicKey, update.TxHash); err != nil {
+						return
+					}
+				}
+				continue
+			}
+
+			for key := range update.Tx.GetAllKeys() {
+
+				addr := wallet.addressesMap[key]
+				if addr == nil {
+					continue
+				}
+
+				var historyTx *WalletHistoryTx
+				if historyTx, err = wallet.decryptHistoryTx(update.Tx, addr.PublicKey, update.BlockHeight, update.BlockTimestamp); err != nil {
+					return
+				}
+				if historyTx == nil {
+					continue
+				}
+
+				if err = wallet.saveHistoryTx(writer, addr.PublicKey, historyTx); err != nil {
+					return
+				}
+				if err = wallet.processInvoicesPayments(writer, addr.PublicKey, historyTx, chainHeight, invoices); err != nil {
+					return
+				}
+			}
+		}
+
+		return
+	}); err != nil {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		wallet.UpdateInvoices.Broadcast(invoice)
+	}
+
+	return nil
+}
+
+func (wallet *Wallet) processHistory(updateTransactions *multicast.MulticastChannel[[]*blockchain_types.BlockchainTransactionUpdate]) {
+	recovery.SafeGo(func() {
+
+		updateTransactionsCn := updateTransactions.AddListener()
+		defer updateTransactions.RemoveChannel(updateTransactionsCn)
+
+		for {
+			updates, ok := <-updateTransactionsCn
+			if !ok {
+				return
+			}
+
+			for _, loaded := range wallet.getLoadedWallets() {
+				if err := loaded.processHistoryUpdates(updates); err != nil {
+					gui.GUI.Error("Error processing wallet history", err)
+				}
+			}
+		}
+	})
+}
+
+// ScanHistory follows the addrTx: indexes to add the transactions included before the history was tracked
+func (wallet *Wallet) ScanHistory(publicKey []byte, ctx context.Context) error {
+
+	if !config.NODE_PROVIDE_EXTENDED_INFO_APP {
+		return nil
+	}
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+
+	if wallet.addressesMap[string(publicKey)] == nil {
+		return errors.New("Address was not found")
+	}
+
+	var list *walletHistoryList
+	if err := wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		list, err = wallet.loadHistoryList(reader, publicKey)
+		return
+	}); err != nil {
+		return err
+	}
+
+	historyTxs := []*WalletHistoryTx{}
+
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var count uint64
+		if data := reader.Get("addrTxsCount:" + string(publicKey)); data != nil {
+			if count, err = strconv.ParseUint(string(data), 10, 64); err != nil {
+				return
+			}
+		}
+
+		//the index was reduced by a chain reorganization
+		if list.Indexed > count {
+			list.Indexed = count
+		}
+
+		for ; list.Indexed < count; list.Indexed++ {
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			hash := reader.Get("addrTx:" + string(publicKey) + ":" + strconv.FormatUint(list.Indexed, 10))
+			if hash == nil {
+				return errors.New("addrTx: was not found")
+			}
+
+			tx := &transaction.Transaction{}
+			if err = tx.Deserialize(advanced_buffers.NewBufferReader(reader.Get("tx:" + string(hash)))); err != nil {
+				return
+			}
+
+			blockHeight, _ := binary.Uvarint(reader.Get("txBlock:" + string(hash)))
+
+			blkInfo := &info.BlockInfo{}
+			if err = msgpack.Unmarshal(reader.Get("blockInfo_ByHash"+string(reader.Get("blockHash_ByHeight"+strconv.FormatUint(blockHeight, 10)))), blkInfo); err != nil {
+				return
+			}
+
+			var historyTx *WalletHistoryTx
+			if historyTx, err = wallet.decryptHistoryTx(tx, publicKey, blockHeight, blkInfo.Timestamp); err != nil {
+				return
+			}
+			if historyTx != nil {
+				historyTxs = append(historyTxs, historyTx)
+			}
+		}
+
+		return
+	}); err != nil {
+		return err
+	}
+
+	return wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+		for _, historyTx := range historyTxs {
+			if err = wallet.saveHistoryTx(writer, publicKey, historyTx); err != nil {
+				return
+			}
+		}
+
+		var list2 *walletHistoryList
+		if list2, err = wallet.loadHistoryList(writer, publicKey); err != nil {
+			return
+		}
+		list2.Indexed = list.Indexed
+		return wallet.saveHistoryList(writer, publicKey, list2)
+	})
+}
+
+// GetHistory returns the most recent transactions first
+func (wallet *Wallet) GetHistory(publicKey []byte, start, count int) (out []*WalletHistoryTx, total int, err error) {
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, 0, errors.New("Wallet was not loaded!")
+	}
+
+	out = []*WalletHistoryTx{}
+
+	if err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var list *walletHistoryList
+		if list, err = wallet.loadHistoryList(reader, publicKey); err != nil {
+			return
+		}
+		total = len(list.Txs)
+
+		var contacts map[string]string
+		if contacts, err = wallet.getContactsNames(reader); err != nil {
+			return
+		}
+
+		for i := total - 1 - start; i >= 0 && len(out) < count; i-- {
+
+			var data []byte
+			if data, err = wallet.Encryption.decryptData(reader.Get("walletHistoryTx:" + string(publicKey) + ":" + string(list.Txs[i]))); err != nil {
+				return
+			}
+
+			historyTx := &WalletHistoryTx{}
+			if err = msgpack.Unmarshal(data, historyTx); err != nil {
+				return
+			}
+			for _, payload := range historyTx.Payloads {
+				if payload.Recipient != nil {
+					payload.Contact = contacts[string(payload.Recipient)]
+				}
+			}
+			out = append(out, historyTx)
+		}
+
+		return
+	}); err != nil {
+		return
+	}
+
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		chainHeight, _ := binary.Uvarint(reader.Get("chainHeight"))
+		for _, historyTx := range out {
+			if chainHeight > historyTx.BlockHeight {
+				historyTx.Confirmations = chainHeight - historyTx.BlockHeight
+			}
+		}
+		return
+	}); err != nil {
+		return
+	}
+
+	return
+}
@@ -0,0 +1,209 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"pandora-pay/blockchain/data_storage/assets"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers/files"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type WalletHistoryExportRow struct {
+	Address   string `json:"address"`
+	Timestamp uint64 `json:"timestamp"`
+	Height    uint64 `json:"height"`
+	TxId      string `json:"txId"`
+	Asset     string `json:"asset"`
+	Amount    string `json:"amount"`
+	Fee       string `json:"fee"`
+	Direction string `json:"direction"`
+	PaymentID string `json:"paymentID"`
+	Message   string `json:"message"`
+}
+
+var walletHistoryExportHeader = []string{"address", "timestamp", "height", "txId", "asset", "amount", "fee", "direction", "paymentID", "message"}
+
+type walletHistoryExportAsset struct {
+	ticker string
+	ast    *asset.Asset //nil for the native asset or for unknown assets
+	native bool
+}
+
+// formatUnits formats the amount out of the integer units. float64 loses the precision of the large amounts
+func formatUnits(amount uint64, decimals int) string {
+	str := strconv.FormatUint(amount, 10)
+	if decimals <= 0 {
+		return str
+	}
+	if len(str) <= decimals {
+		str = strings.Repeat("0", decimals-len(str)+1) + str
+	}
+	return str[:len(str)-decimals] + "." + str[len(str)-decimals:]
+}
+
+func (exportAsset *walletHistoryExportAsset) format(amount uint64) string {
+	if exportAsset.native {
+		return formatUnits(amount, config_coins.DECIMAL_SEPARATOR)
+	}
+	if exportAsset.ast != nil {
+		return formatUnits(amount, int(exportAsset.ast.DecimalSeparator))
+	}
+	return strconv.FormatUint(amount, 10)
+}
+
+// ExportHistory returns the decrypted history of the given addresses between fromHeight and toHeight (inclusive), sorted ascending by height
+// in case publicKeys is empty, all the addresses of the wallet are exported
+func (wallet *Wallet) ExportHistory(publicKeys [][]byte, fromHeight, toHeight uint64) ([]*WalletHistoryExportRow, error) {
+
+	if len(publicKeys) == 0 {
+		wallet.Lock.RLock()
+		for _, addr := range wallet.Addresses {
+			publicKeys = append(publicKeys, addr.PublicKey)
+		}
+		wallet.Lock.RUnlock()
+	}
+
+	rows := []*WalletHistoryExportRow{}
+	exportAssets := make(map[string]*walletHistoryExportAsset)
+
+	for _, publicKey := range publicKeys {
+
+		addr := wallet.GetWalletAddressByPublicKey(publicKey, true)
+		if addr == nil {
+			return nil, errors.New("Address was not found")
+		}
+
+		historyTxs, _, err := wallet.GetHistory(publicKey, 0, math.MaxInt)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := len(historyTxs) - 1; i >= 0; i-- {
+
+			historyTx := historyTxs[i]
+			if historyTx.BlockHeight < fromHeight || historyTx.BlockHeight > toHeight {
+				continue
+			}
+
+			for _, payload := range historyTx.Payloads {
+
+				exportAsset := exportAssets[string(payload.Asset)]
+				if exportAsset == nil {
+					if exportAsset, err = getHistoryExportAsset(payload.Asset); err != nil {
+						return nil, err
+					}
+					exportAssets[string(payload.Asset)] = exportAsset
+				}
+
+				direction := "RECEIVED"
+				if payload.Sent {
+					direction = "SENT"
+				}
+
+				rows = append(rows, &WalletHistoryExportRow{
+					addr.AddressEncoded,
+					historyTx.BlockTimestamp,
+					historyTx.BlockHeight,
+					base64.StdEncoding.EncodeToString(historyTx.TxHash),
+					exportAsset.ticker,
+					exportAsset.format(payload.Amount),
+					exportAsset.format(payload.Fee),
+					direction,
+					hex.EncodeToString(payload.PaymentID),
+					string(payload.Message),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Height < rows[j].Height
+	})
+
+	return rows, nil
+}
+
+func getHistoryExportAsset(assetId []byte) (exportAsset *walletHistoryExportAsset, err error) {
+
+	if bytes.Equal(assetId, config_coins.NATIVE_ASSET_FULL) {
+		return &walletHistoryExportAsset{config_coins.NATIVE_ASSET_TICKER, nil, true}, nil
+	}
+
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		var ast *asset.Asset
+		if ast, err = assets.NewAssets(reader).Get(string(assetId)); err != nil {
+			return
+		}
+		if ast == nil {
+			exportAsset = &walletHistoryExportAsset{base64.StdEncoding.EncodeToString(assetId), nil, false}
+			return
+		}
+		exportAsset = &walletHistoryExportAsset{ast.Ticker, ast, false}
+		return
+	})
+
+	return
+}
+
+func (row *WalletHistoryExportRow) csvRecord() []string {
+	return []string{row.Address, strconv.FormatUint(row.Timestamp, 10), strconv.FormatUint(row.Height, 10), row.TxId, row.Asset, row.Amount, row.Fee, row.Direction, row.PaymentID, row.Message}
+}
+
+func ExportHistoryCSV(rows []*WalletHistoryExportRow) (string, error) {
+
+	var buffer strings.Builder
+	writer := csv.NewWriter(&buffer)
+
+	if err := writer.Write(walletHistoryExportHeader); err != nil {
+		return "", err
+	}
+	for _, row := range rows {
+		if err := writer.Write(row.csvRecord()); err != nil {
+			return "", err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+// ExportHistoryToFile writes the history in JSON when the filename ends in .json, otherwise in CSV
+func (wallet *Wallet) ExportHistoryToFile(filename string, publicKeys [][]byte, fromHeight, toHeight uint64) (int, error) {
+
+	rows, err := wallet.ExportHistory(publicKeys, fromHeight, toHeight)
+	if err != nil {
+		return 0, err
+	}
+
+	var data string
+	if strings.HasSuffix(strings.ToLower(filename), ".json") {
+		var marshal []byte
+		if marshal, err = json.MarshalIndent(rows, "", "  "); err != nil {
+			return 0, err
+		}
+		data = string(marshal)
+	} else if data, err = ExportHistoryCSV(rows); err != nil {
+		return 0, err
+	}
+
+	if err = files.WriteFile(filename, data); err != nil {
+		return 0, err
+	}
+
+	return len(rows), nil
+}
@@ -0,0 +1,571 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/data_storage/registrations"
+	"pandora-pay/config"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/helpers/multicast"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type WalletInvoiceStatus uint8
+
+const (
+	INVOICE_STATUS_PENDING WalletInvoiceStatus = iota //nothing received or not enough confirmations
+	INVOICE_STATUS_CONFIRMED
+	INVOICE_STATUS_UNDERPAID
+	INVOICE_STATUS_OVERPAID
+)
+
+func (status WalletInvoiceStatus) String() string {
+	switch status {
+	case INVOICE_STATUS_PENDING:
+		return "PENDING"
+	case INVOICE_STATUS_CONFIRMED:
+		return "CONFIRMED"
+	case INVOICE_STATUS_UNDERPAID:
+		return "UNDERPAID"
+	case INVOICE_STATUS_OVERPAID:
+		return "OVERPAID"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+type WalletInvoicePayment struct {
+	TxHash      []byte `json:"txHash" msgpack:"txHash"`
+	Amount      uint64 `json:"amount" msgpack:"amount"`
+	BlockHeight uint64 `json:"blockHeight" msgpack:"blockHeight"`
+}
+
+type WalletInvoice struct {
+	PaymentID             []byte                  `json:"paymentID" msgpack:"paymentID"`
+	PublicKey             []byte                  `json:"publicKey" msgpack:"publicKey"`
+	Address               string                  `json:"address" msgpack:"address"` //integrated address that must be paid
+	Asset                 []byte                  `json:"asset" msgpack:"asset"`
+	Amount                uint64                  `json:"amount" msgpack:"amount"`
+	RequiredConfirmations uint64                  `json:"requiredConfirmations" msgpack:"requiredConfirmations"`
+	CreatedHeight         uint64                  `json:"createdHeight" msgpack:"createdHeight"`
+	Received              uint64                  `json:"received" msgpack:"received"`
+	Payments              []*WalletInvoicePayment `json:"payments" msgpack:"payments"`
+	Status                WalletInvoiceStatus     `json:"status" msgpack:"status"`
+	Confirmations         uint64                  `json:"confirmations" msgpack:"-"`
+}
+
+// walletInvoicesList keeps the invoices in the order they were created.
+// Unconfirmed contains the invoices that received payments which still wait for confirmations
+type walletInvoicesList struct {
+	PaymentIDs  [][]byte `msgpack:"paymentIDs"`
+	Unconfirmed [][]byte `msgpack:"unconfirmed"`
+}
+
+func (invoice *WalletInvoice) updateStatus(chainHeight uint64) {
+
+	invoice.Confirmations = 0
+	invoice.Received = 0
+
+	if len(invoice.Payments) == 0 {
+		invoice.Status = INVOICE_STATUS_PENDING
+		return
+	}
+
+	var lastHeight uint64
+	for _, payment := range invoice.Payments {
+		invoice.Received += payment.Amount
+		if payment.BlockHeight > lastHeight {
+			lastHeight = payment.BlockHeight
+		}
+	}
+
+	if chainHeight > lastHeight {
+		invoice.Confirmations = chainHeight - lastHeight
+	}
+
+	switch {
+	case invoice.Confirmations < invoice.RequiredConfirmations:
+		invoice.Status = INVOICE_STATUS_PENDING
+	case invoice.Received < invoice.Amount:
+		invoice.Status = INVOICE_STATUS_UNDERPAID
+	case invoice.Received > invoice.Amount:
+		invoice.Status = INVOICE_STATUS_OVERPAID
+	default:
+		invoice.Status = INVOICE_STATUS_CONFIRMED
+	}
+}
+
+func removeInvoiceFromList(list [][]byte, paymentID []byte) [][]byte {
+	out := list[:0]
+	for _, id := range list {
+		if !bytes.Equal(id, paymentID) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+//must be locked before
+func (wallet *Wallet) loadInvoicesList(reader store_db_interface.StoreDBTransactionInterface) (*walletInvoicesList, error) {
+
+	list := &walletInvoicesList{[][]byte{}, [][]byte{}}
+
+	data := reader.Get("walletInvoices")
+	if data == nil {
+		return list, nil
+	}
+
+	data, err := wallet.Encryption.decryptData(data)
+	if err != nil {
+		return nil, err
+	}
+	if err = msgpack.Unmarshal(data, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+//must be locked before
+func (wallet *Wallet) saveInvoicesList(writer store_db_interface.StoreDBTransactionInterface, list *walletInvoicesList) error {
+
+	data, err := msgpack.Marshal(list)
+	if err != nil {
+		return err
+	}
+	if data, err = wallet.Encryption.encryptData(data); err != nil {
+		return err
+	}
+
+	writer.Put("walletInvoices", data)
+	return nil
+}
+
+//must be locked before
+func (wallet *Wallet) loadInvoice(reader store_db_interface.StoreDBTransactionInterface, paymentID []byte) (*WalletInvoice, error) {
+
+	data := reader.Get("walletInvoice:" + string(paymentID))
+	if data == nil {
+		return nil, nil
+	}
+
+	data, err := wallet.Encryption.decryptData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &WalletInvoice{}
+	if err = msgpack.Unmarshal(data, invoice); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// saveInvoice also keeps the list of unconfirmed invoices up to date
+//must be locked before
+func (wallet *Wallet) saveInvoice(writer store_db_interface.StoreDBTransactionInterface, list *walletInvoicesList, invoice *WalletInvoice) error {
+
+	list.Unconfirmed = removeInvoiceFromList(list.Unconfirmed, invoice.PaymentID)
+	if invoice.Status == INVOICE_STATUS_PENDING && len(invoice.Payments) > 0 {
+		list.Unconfirmed = append(list.Unconfirmed, invoice.PaymentID)
+	}
+
+	data, err := msgpack.Marshal(invoice)
+	if err != nil {
+		return err
+	}
+	if data, err = wallet.Encryption.encryptData(data); err != nil {
+		return err
+	}
+
+	writer.Put("walletInvoice:"+string(invoice.PaymentID), data)
+	return nil
+}
+
+// readInvoicesRaw adds all the invoices decrypted to out. It is used to encrypt the invoices again when the wallet encryption is changed
+//must be locked before
+func (wallet *Wallet) readInvoicesRaw(out map[string][]byte) error {
+	return wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var list *walletInvoicesList
+		if list, err = wallet.loadInvoicesList(reader); err != nil {
+			return
+		}
+
+		for _, paymentID := range list.PaymentIDs {
+			key := "walletInvoice:" + string(paymentID)
+			if out[key], err = wallet.Encryption.decryptData(reader.Get(key)); err != nil {
+				return
+			}
+		}
+
+		out["walletInvoices"], err = msgpack.Marshal(list)
+		return
+	})
+}
+
+func (wallet *Wallet) CreateInvoice(publicKey, asset []byte, amount, requiredConfirmations uint64) (*WalletInvoice, error) {
+
+	if len(asset) == 0 {
+		asset = config_coins.NATIVE_ASSET_FULL
+	}
+	if len(asset) != config_coins.ASSET_LENGTH {
+		return nil, errors.New("Invalid asset")
+	}
+	if amount == 0 {
+		return nil, errors.New("Invoice amount must be greater than zero")
+	}
+	if requiredConfirmations == 0 {
+		requiredConfirmations = config.WALLET_INVOICE_CONFIRMATIONS
+	}
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, errors.New("Wallet was not loaded!")
+	}
+
+	addr := wallet.addressesMap[string(publicKey)]
+	if addr == nil {
+		return nil, errors.New("Address was not found")
+	}
+
+	var chainHeight uint64
+	var isReg bool
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		chainHeight, _ = binary.Uvarint(reader.Get("chainHeight"))
+		isReg, err = registrations.NewRegistrations(reader).Exists(string(publicKey))
+		return
+	}); err != nil {
+		return nil, err
+	}
+
+	address, err := addresses.DecodeAddr(addr.GetAddress(isReg))
+	if err != nil {
+		return nil, err
+	}
+
+	var invoice *WalletInvoice
+	if err = wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var list *walletInvoicesList
+		if list, err = wallet.loadInvoicesList(writer); err != nil {
+			return
+		}
+
+		paymentID := helpers.RandomBytes(addresses.PAYMENT_ID_LENGTH)
+		for writer.Exists("walletInvoice:" + string(paymentID)) {
+			paymentID = helpers.RandomBytes(addresses.PAYMENT_ID_LENGTH)
+		}
+
+		var integrated *addresses.Address
+		if integrated, err = addresses.CreateAddr(address.PublicKey, address.Staked, address.SpendPublicKey, address.Registration, paymentID, amount, asset); err != nil {
+			return
+		}
+
+		invoice = &WalletInvoice{
+			PaymentID:             paymentID,
+			PublicKey:             publicKey,
+			Address:               integrated.EncodeAddr(),
+			Asset:                 asset,
+			Amount:                amount,
+			RequiredConfirmations: requiredConfirmations,
+			CreatedHeight:         chainHeight,
+			Payments:              []*WalletInvoicePayment{},
+			Status:                INVOICE_STATUS_PENDING,
+		}
+
+		list.PaymentIDs = append(list.PaymentIDs, paymentID)
+		if err = wallet.saveInvoice(writer, list, invoice); err != nil {
+			return
+		}
+		return wallet.saveInvoicesList(writer, list)
+	}); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+func (wallet *Wallet) getChainHeight() (chainHeight uint64, err error) {
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		chainHeight, _ = binary.Uvarint(reader.Get("chainHeight"))
+		return nil
+	})
+	return
+}
+
+func (wallet *Wallet) GetInvoice(paymentID []byte) (invoice *WalletInvoice, err error) {
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, errors.New("Wallet was not loaded!")
+	}
+
+	if err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		invoice, err = wallet.loadInvoice(reader, paymentID)
+		return
+	}); err != nil {
+		return
+	}
+	if invoice == nil {
+		return nil, errors.New("Invoice was not found")
+	}
+
+	var chainHeight uint64
+	if chainHeight, err = wallet.getChainHeight(); err != nil {
+		return
+	}
+	invoice.updateStatus(chainHeight)
+
+	return
+}
+
+// GetInvoices returns the invoices, most recent first
+func (wallet *Wallet) GetInvoices(start, count int) (out []*WalletInvoice, total int, err error) {
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, 0, errors.New("Wallet was not loaded!")
+	}
+
+	var chainHeight uint64
+	if chainHeight, err = wallet.getChainHeight(); err != nil {
+		return
+	}
+
+	out = []*WalletInvoice{}
+
+	err = wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var list *walletInvoicesList
+		if list, err = wallet.loadInvoicesList(reader); err != nil {
+			return
+		}
+		total = len(list.PaymentIDs)
+
+		for i := total - 1 - start; i >= 0 && len(out) < count; i-- {
+			var invoice *WalletInvoice
+			if invoice, err = wallet.loadInvoice(reader, list.PaymentIDs[i]); err != nil {
+				return
+			}
+			if invoice == nil {
+				continue
+			}
+			invoice.updateStatus(chainHeight)
+			out = append(out, invoice)
+		}
+
+		return
+	})
+
+	return
+}
+
+// processInvoicesPayments matches the received payloads with the invoices using the PaymentID
+//must be locked before
+func (wallet *Wallet) processInvoicesPayments(writer store_db_interface.StoreDBTransactionInterface, publicKey []byte, historyTx *WalletHistoryTx, chainHeight uint64, updated map[string]*WalletInvoice) (err error) {
+
+	var list *walletInvoicesList
+
+	for _, payload := range historyTx.Payloads {
+
+		if payload.Sent || len(payload.PaymentID) != addresses.PAYMENT_ID_LENGTH {
+			continue
+		}
+
+		invoice := updated[string(payload.PaymentID)]
+		if invoice == nil {
+			if invoice, err = wallet.loadInvoice(writer, payload.PaymentID); err != nil {
+				return
+			}
+		}
+		if invoice == nil || !bytes.Equal(invoice.PublicKey, publicKey) || !bytes.Equal(invoice.Asset, payload.Asset) {
+			continue
+		}
+
+		found := false
+		for _, payment := range invoice.Payments {
+			if bytes.Equal(payment.TxHash, historyTx.TxHash) {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		invoice.Payments = append(invoice.Payments, &WalletInvoicePayment{historyTx.TxHash, payload.Amount, historyTx.BlockHeight})
+		invoice.updateStatus(chainHeight)
+
+		if list == nil {
+			if list, err = wallet.loadInvoicesList(writer); err != nil {
+				return
+			}
+		}
+		if err = wallet.saveInvoice(writer, list, invoice); err != nil {
+			return
+		}
+		updated[string(invoice.PaymentID)] = invoice
+	}
+
+	if list != nil {
+		return wallet.saveInvoicesList(writer, list)
+	}
+	return
+}
+
+// removeInvoicesPayments removes the payments of a transaction that was removed from the chain
+//must be locked before
+func (wallet *Wallet) removeInvoicesPayments(writer store_db_interface.StoreDBTransactionInterface, publicKey, txHash []byte, chainHeight uint64, updated map[string]*WalletInvoice) (err error) {
+
+	data := writer.Get("walletHistoryTx:" + string(publicKey) + ":" + string(txHash))
+	if data == nil {
+		return
+	}
+	if data, err = wallet.Encryption.decryptData(data); err != nil {
+		return
+	}
+
+	historyTx := &WalletHistoryTx{}
+	if err = msgpack.Unmarshal(data, historyTx); err != nil {
+		return
+	}
+
+	var list *walletInvoicesList
+
+	for _, payload := range historyTx.Payloads {
+
+		if payload.Sent || len(payload.PaymentID) != addresses.PAYMENT_ID_LENGTH {
+			continue
+		}
+
+		invoice := updated[string(payload.PaymentID)]
+		if invoice == nil {
+			if invoice, err = wallet.loadInvoice(writer, payload.PaymentID); err != nil {
+				return
+			}
+		}
+		if invoice == nil {
+			continue
+		}
+
+		payments := invoice.Payments[:0]
+		for _, payment := range invoice.Payments {
+			if !bytes.Equal(payment.TxHash, txHash) {
+				payments = append(payments, payment)
+			}
+		}
+		if len(payments) == len(invoice.Payments) {
+			continue
+		}
+		invoice.Payments = payments
+		invoice.updateStatus(chainHeight)
+
+		if list == nil {
+			if list, err = wallet.loadInvoicesList(writer); err != nil {
+				return
+			}
+		}
+		if err = wallet.saveInvoice(writer, list, invoice); err != nil {
+			return
+		}
+		updated[string(invoice.PaymentID)] = invoice
+	}
+
+	if list != nil {
+		return wallet.saveInvoicesList(writer, list)
+	}
+	return
+}
+
+// processInvoicesConfirmations updates the status of the invoices that wait for confirmations
+func (wallet *Wallet) processInvoicesConfirmations(chainHeight uint64) (updated []*WalletInvoice, err error) {
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return
+	}
+
+	err = wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var list *walletInvoicesList
+		if list, err = wallet.loadInvoicesList(writer); err != nil {
+			return
+		}
+		if len(list.Unconfirmed) == 0 {
+			return
+		}
+
+		unconfirmed := append([][]byte{}, list.Unconfirmed...)
+		for _, paymentID := range unconfirmed {
+
+			var invoice *WalletInvoice
+			if invoice, err = wallet.loadInvoice(writer, paymentID); err != nil {
+				return
+			}
+			if invoice == nil {
+				list.Unconfirmed = removeInvoiceFromList(list.Unconfirmed, paymentID)
+				continue
+			}
+
+			invoice.updateStatus(chainHeight)
+			if invoice.Status == INVOICE_STATUS_PENDING {
+				continue
+			}
+
+			if err = wallet.saveInvoice(writer, list, invoice); err != nil {
+				return
+			}
+			updated = append(updated, invoice)
+		}
+
+		return wallet.saveInvoicesList(writer, list)
+	})
+
+	return
+}
+
+func (wallet *Wallet) processInvoices(updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) {
+	recovery.SafeGo(func() {
+
+		updateNewChainUpdateCn := updateNewChainUpdate.AddListener()
+		defer updateNewChainUpdate.RemoveChannel(updateNewChainUpdateCn)
+
+		for {
+			update, ok := <-updateNewChainUpdateCn
+			if !ok {
+				return
+			}
+
+			for _, loaded := range wallet.getLoadedWallets() {
+
+				updated, err := loaded.processInvoicesConfirmations(update.BlockHeight + 1)
+				if err != nil {
+					gui.GUI.Error("Error processing wallet invoices", err)
+					continue
+				}
+
+				for _, invoice := range updated {
+					wallet.UpdateInvoices.Broadcast(invoice)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package wallet
+
+import (
+	"errors"
+	"pandora-pay/config"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/recovery"
+	"sync"
+	"time"
+)
+
+// walletAutoLock keeps the activity of the wallet. An encrypted wallet is locked after config.WALLET_AUTO_LOCK without activity
+// or when the window opened by Unlock expires
+type walletAutoLock struct {
+	lastActivity  time.Time
+	unlockedUntil time.Time //while it is set, the idle timeout is not used
+	spendUntil    time.Time
+	lock          sync.Mutex
+}
+
+func (wallet *Wallet) touch() {
+	wallet.autoLock.lock.Lock()
+	wallet.autoLock.lastActivity = time.Now()
+	wallet.autoLock.lock.Unlock()
+}
+
+// unlocked starts the windows after the wallet was decrypted. A zero duration uses only the idle timeout
+func (wallet *Wallet) unlocked(duration time.Duration) {
+
+	wallet.autoLock.lock.Lock()
+	defer wallet.autoLock.lock.Unlock()
+
+	now := time.Now()
+	wallet.autoLock.lastActivity = now
+	wallet.autoLock.unlockedUntil = time.Time{}
+
+	spend := config.WALLET_SPEND_UNLOCK
+	if duration > 0 {
+		wallet.autoLock.unlockedUntil = now.Add(duration)
+		if spend == 0 || duration < spend {
+			spend = duration
+		}
+	}
+	wallet.autoLock.spendUntil = now.Add(spend)
+}
+
+func (wallet *Wallet) lockExpired(now time.Time) bool {
+
+	wallet.autoLock.lock.Lock()
+	defer wallet.autoLock.lock.Unlock()
+
+	if !wallet.autoLock.unlockedUntil.IsZero() {
+		return now.After(wallet.autoLock.unlockedUntil)
+	}
+	if config.WALLET_AUTO_LOCK > 0 {
+		return now.After(wallet.autoLock.lastActivity.Add(config.WALLET_AUTO_LOCK))
+	}
+	return false
+}
+
+func (wallet *Wallet) isUnlockedEncrypted() bool {
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+	return wallet.Loaded && wallet.Encryption.Encrypted != ENCRYPTED_VERSION_PLAIN_TEXT
+}
+
+// Unlock decrypts the wallet for the duration. After it, the wallet is locked even if it was used
+func (wallet *Wallet) Unlock(password string, duration time.Duration) (err error) {
+
+	if duration <= 0 {
+		return errors.New("Unlock duration is invalid")
+	}
+
+	wallet.Lock.RLock()
+	loaded, encrypted := wallet.Loaded, wallet.Encryption.Encrypted
+	wallet.Lock.RUnlock()
+
+	if loaded {
+		if encrypted == ENCRYPTED_VERSION_PLAIN_TEXT {
+			return errors.New("Wallet is not encrypted!")
+		}
+		err = wallet.Encryption.CheckPassword(password, true)
+	} else {
+		err = wallet.Encryption.Decrypt(password)
+	}
+	if err != nil {
+		return
+	}
+
+	wallet.unlocked(duration)
+	return
+}
+
+// LockWallet removes the decrypted keys of the wallet from memory. The wallet logs out, but the named wallets stay loaded and are unlocked again with their password.
+// The staking keys were already given to forging, so forging keeps working
+func (wallet *Wallet) LockWallet() error {
+
+	wallet.Lock.RLock()
+	publicKeys := make([][]byte, len(wallet.Addresses))
+	for i, addr := range wallet.Addresses {
+		publicKeys[i] = addr.PublicKey
+	}
+	wallet.Lock.RUnlock()
+
+	if err := wallet.Encryption.Logout(); err != nil {
+		return err
+	}
+
+	//the addresses are cleared, but they are still forging until the wallet is unloaded
+	wallet.Lock.Lock()
+	wallet.lockedForging = append(wallet.lockedForging, publicKeys...)
+	wallet.Lock.Unlock()
+
+	return nil
+}
+
+// CheckSpendUnlocked verifies that the encrypted wallet was unlocked recently enough to spend. It is required by the API calls with the spend scope
+func (wallet *Wallet) CheckSpendUnlocked() error {
+
+	if config.WALLET_SPEND_UNLOCK == 0 || !wallet.isUnlockedEncrypted() {
+		return nil
+	}
+
+	wallet.autoLock.lock.Lock()
+	defer wallet.autoLock.lock.Unlock()
+
+	if time.Now().After(wallet.autoLock.spendUntil) {
+		return errors.New("Wallet spending is locked. Unlock the wallet again")
+	}
+	return nil
+}
+
+func (wallet *Wallet) processAutoLock() {
+	recovery.SafeGo(func() {
+		for {
+			time.Sleep(time.Second)
+
+			now := time.Now()
+			for _, loaded := range wallet.getLoadedWallets() {
+				if !loaded.isUnlockedEncrypted() || !loaded.lockExpired(now) {
+					continue
+				}
+				if err := loaded.LockWallet(); err != nil {
+					gui.GUI.Error("Error locking wallet", loaded.Name, err)
+					continue
+				}
+				gui.GUI.Log("Wallet " + loaded.Name + " was locked")
+			}
+		}
+	})
+}
+
+// wipeKeys overwrites the decrypted key material before the wallet is cleared. It must be locked before.
+// The forging wallet holds the SharedStaked of the addresses, not a copy of it. The PrivateKey of an address
+// is kept when it aliases SharedStaked.PrivateKey, otherwise wiping it would stop forging. The other keys are wiped
+func (wallet *Wallet) wipeKeys() {
+
+	wipe := func(data []byte) {
+		for i := range data {
+			data[i] = 0
+		}
+	}
+
+	wipe(wallet.Seed)
+	for _, addr := range wallet.Addresses {
+		wipe(addr.SecretKey)
+		if addr.SpendPrivateKey != nil {
+			wipe(addr.SpendPrivateKey.Key)
+		}
+		if addr.PrivateKey == nil || len(addr.PrivateKey.Key) == 0 {
+			continue
+		}
+		if addr.SharedStaked != nil && addr.SharedStaked.PrivateKey != nil && len(addr.SharedStaked.PrivateKey.Key) > 0 && &addr.SharedStaked.PrivateKey.Key[0] == &addr.PrivateKey.Key[0] {
+			continue
+		}
+		wipe(addr.PrivateKey.Key)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"pandora-pay/config/globals"
 	"pandora-pay/cryptography"
 	"pandora-pay/cryptography/crypto"
+	"pandora-pay/store/store_db/store_db_interface"
 	"pandora-pay/wallet/wallet_address"
 	"pandora-pay/wallet/wallet_address/shared_staked"
 	"strconv"
@@ -163,7 +164,7 @@ func (wallet *Wallet) AddSharedStakedAddress(addr *wallet_address.WalletAddress,
 		return errors.New("Wallet was not loaded!")
 	}
 
-	if wallet.Count > config_nodes.DELEGATES_MAXIMUM {
+	if wallet.DelegatesCount >= config_nodes.DELEGATES_MAXIMUM {
 		return errors.New("DELEGATES_MAXIMUM exceeded")
 	}
 
@@ -184,6 +185,7 @@ func (wallet *Wallet) AddSharedStakedAddress(addr *wallet_address.WalletAddress,
 	wallet.forging.Wallet.AddWallet(addr.PublicKey, addr.SharedStaked, false, nil, nil, 0)
 
 	wallet.Count += 1
+	wallet.DelegatesCount += 1
 
 	wallet.updateWallet()
 
@@ -401,6 +403,52 @@ func (wallet *Wallet) AddNewAddress(lock bool, name string, staked, spendRequire
 	return addr.Clone(), nil
 }
 
+// AddNewCosignedAddress creates a new address whose Spend Private Key is held by a co-signer
+func (wallet *Wallet) AddNewCosignedAddress(name string, staked bool, spendPublicKey []byte, remoteSpendSigner *wallet_address.WalletAddressRemoteSpendSigner) (*wallet_address.WalletAddress, error) {
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	if !wallet.Loaded {
+		return nil, errors.New("Wallet was not loaded!")
+	}
+
+	if remoteSpendSigner == nil || remoteSpendSigner.URL == "" {
+		return nil, errors.New("Co-signer URL is missing")
+	}
+
+	secret, privateKey, _, err := wallet.GenerateKeys(wallet.SeedIndex, false)
+	if err != nil {
+		return nil, err
+	}
+
+	privKey, err := addresses.NewPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = "Addr_" + strconv.FormatUint(uint64(wallet.SeedIndex), 10)
+	}
+
+	addr := &wallet_address.WalletAddress{
+		Version:           wallet_address.VERSION_NORMAL,
+		Name:              name,
+		SecretKey:         secret,
+		PrivateKey:        privKey,
+		SpendPublicKey:    spendPublicKey,
+		RemoteSpendSigner: remoteSpendSigner,
+		SeedIndex:         wallet.SeedIndex,
+		IsMine:            true,
+	}
+
+	if err = wallet.AddAddress(addr, staked, true, false, true, false, true); err != nil {
+		return nil, err
+	}
+
+	return addr.Clone(), nil
+}
+
 func (wallet *Wallet) RemoveAddressByIndex(index int, lock bool) (bool, error) {
 
 	if lock {
@@ -432,6 +480,11 @@ func (wallet *Wallet) RemoveAddressByIndex(index int, lock bool) (bool, error) {
 	if err := wallet.saveWallet(index, index+1, wallet.Count, false); err != nil {
 		return false, err
 	}
+	if err := wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		return wallet.deleteHistory(writer, removing.PublicKey)
+	}); err != nil {
+		return false, err
+	}
 	globals.MainEvents.BroadcastEvent("wallet/removed", adr)
 
 	return true, nil
@@ -523,11 +576,20 @@ func (wallet *Wallet) ImportWalletAddressJSON(data []byte) (*wallet_address.Wall
 		return nil, errors.New("Private Key is missing")
 	}
 
+	if addr.WatchOnly {
+		if addr.SpendPrivateKey != nil || addr.SecretKey != nil {
+			return nil, errors.New("Watch-only address must not contain the Spend Private Key or the Secret Key")
+		}
+		if !addr.SpendRequired {
+			return nil, errors.New("Watch-only address must require the Spend Key")
+		}
+	}
+
 	wallet.Lock.RLock()
 	defer wallet.Lock.RUnlock()
 
 	isMine := false
-	if wallet.SeedIndex != 0 {
+	if wallet.SeedIndex != 0 && !addr.WatchOnly {
 		key, _, _, err := wallet.GenerateKeys(addr.SeedIndex, false)
 		if err == nil && key != nil && bytes.Equal(key, addr.PrivateKey.Key) {
 			isMine = true
@@ -591,7 +653,7 @@ func (wallet *Wallet) TryDecryptBalance(addr *wallet_address.WalletAddress, encr
 
 func (wallet *Wallet) ImportWalletJSON(data []byte) (err error) {
 
-	wallet2 := createWallet(wallet.forging, wallet.mempool, wallet.addressBalanceDecryptor, wallet.updateNewChainUpdate)
+	wallet2 := createWallet(wallet.Name, wallet.store, wallet.wallets, wallet.forging, wallet.mempool, wallet.addressBalanceDecryptor, wallet.updateNewChainUpdate)
 	if err = json.Unmarshal(data, wallet2); err != nil {
 		return errors.New("Error unmarshaling wallet")
 	}
@@ -629,5 +691,5 @@ func (wallet *Wallet) SetNonHardening(value bool) {
 }
 
 func (wallet *Wallet) Close() {
-
+	wallet.closeNamedWallets()
 }
@@ -144,6 +144,9 @@ func (wallet *Wallet) ImportEntropy(entropy []byte) (err error) {
 }
 
 func (wallet *Wallet) updateWallet() {
+	if !wallet.isActive() {
+		return
+	}
 	gui.GUI.InfoUpdate("Wallet Addrs", fmt.Sprintf("%d  %s", wallet.Count, wallet.Encryption.Encrypted))
 }
 
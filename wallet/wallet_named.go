@@ -0,0 +1,358 @@
+package wallet
+
+import (
+	"errors"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/msgpack"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+const WALLET_DEFAULT_NAME = "default"
+
+var walletNameRegexp = regexp.MustCompile("^[a-zA-Z0-9_-]{1,32}$")
+
+// walletsList keeps the wallets loaded in memory. The default wallet is always loaded, the named ones only after they are decrypted
+type walletsList struct {
+	main        *Wallet
+	active      *Wallet
+	loaded      map[string]*Wallet
+	lock        sync.RWMutex
+	updateMutex sync.Mutex //create, load, unload and switch are done one by one
+}
+
+type WalletInfo struct {
+	Name      string           `json:"name" msgpack:"name"`
+	Loaded    bool             `json:"loaded" msgpack:"loaded"`
+	Active    bool             `json:"active" msgpack:"active"`
+	Encrypted EncryptedVersion `json:"encrypted" msgpack:"encrypted"`
+	Count     int              `json:"count" msgpack:"count"`
+}
+
+func createWalletsList(main *Wallet) *walletsList {
+	return &walletsList{
+		main:   main,
+		active: main,
+		loaded: map[string]*Wallet{WALLET_DEFAULT_NAME: main},
+	}
+}
+
+func (wallet *Wallet) isActive() bool {
+	wallet.wallets.lock.RLock()
+	defer wallet.wallets.lock.RUnlock()
+	return wallet.wallets.active == wallet
+}
+
+// GetActiveWallet returns the wallet used by the CLI and by the API calls without a wallet name
+func (wallet *Wallet) GetActiveWallet() *Wallet {
+	wallet.wallets.lock.RLock()
+	defer wallet.wallets.lock.RUnlock()
+	wallet.wallets.active.touch()
+	return wallet.wallets.active
+}
+
+// GetWallet returns a loaded wallet by name. An empty name returns the active wallet
+func (wallet *Wallet) GetWallet(name string) (*Wallet, error) {
+	wallet.wallets.lock.RLock()
+	defer wallet.wallets.lock.RUnlock()
+
+	found := wallet.wallets.active
+	if name != "" {
+		if found = wallet.wallets.loaded[name]; found == nil {
+			return nil, errors.New("Wallet is not loaded")
+		}
+	}
+
+	found.touch()
+	return found, nil
+}
+
+// getLoadedWallets returns the active wallet first
+func (wallet *Wallet) getLoadedWallets() []*Wallet {
+	wallet.wallets.lock.RLock()
+	defer wallet.wallets.lock.RUnlock()
+
+	names := make([]string, 0, len(wallet.wallets.loaded))
+	for name, loaded := range wallet.wallets.loaded {
+		if loaded != wallet.wallets.active {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	list := []*Wallet{wallet.wallets.active}
+	for _, name := range names {
+		list = append(list, wallet.wallets.loaded[name])
+	}
+	return list
+}
+
+// GetWalletByPublicKey returns the loaded wallet which owns the address. Locked wallets are not searched
+func (wallet *Wallet) GetWalletByPublicKey(publicKey []byte) *Wallet {
+	for _, loaded := range wallet.getLoadedWallets() {
+		if loaded.GetWalletAddressByPublicKey(publicKey, true) != nil {
+			return loaded
+		}
+	}
+	return nil
+}
+
+func (wallet *Wallet) readWalletsNames() (names []string, err error) {
+	err = wallet.wallets.main.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		if data := reader.Get("wallets"); data != nil {
+			return msgpack.Unmarshal(data, &names)
+		}
+		return nil
+	})
+	return
+}
+
+func (wallet *Wallet) writeWalletsNames(names []string) error {
+	return wallet.wallets.main.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		data, err := msgpack.Marshal(names)
+		if err != nil {
+			return err
+		}
+		writer.Put("wallets", data)
+		return nil
+	})
+}
+
+// GetWallets returns the default wallet and all the named wallets stored
+func (wallet *Wallet) GetWallets() ([]*WalletInfo, error) {
+
+	names, err := wallet.readWalletsNames()
+	if err != nil {
+		return nil, err
+	}
+
+	wallet.wallets.lock.RLock()
+	loaded := make(map[string]*Wallet, len(wallet.wallets.loaded))
+	for name, w := range wallet.wallets.loaded {
+		loaded[name] = w
+	}
+	active := wallet.wallets.active
+	wallet.wallets.lock.RUnlock()
+
+	list := make([]*WalletInfo, 0, len(names)+1)
+	for _, name := range append([]string{WALLET_DEFAULT_NAME}, names...) {
+		info := &WalletInfo{Name: name}
+		if w := loaded[name]; w != nil {
+			w.Lock.RLock()
+			info.Loaded = w.Loaded
+			info.Active = w == active
+			info.Encrypted = w.Encryption.Encrypted
+			info.Count = w.Count
+			w.Lock.RUnlock()
+		}
+		list = append(list, info)
+	}
+
+	return list, nil
+}
+
+func (wallet *Wallet) createNamedWallet(name string, walletStore *store.Store) *Wallet {
+	main := wallet.wallets.main
+	return createWallet(name, walletStore, wallet.wallets, main.forging, main.mempool, main.addressBalanceDecryptor, main.updateNewChainUpdate)
+}
+
+// CreateNamedWallet creates a new wallet with its own seed. The new wallet is loaded, but it is not activated
+func (wallet *Wallet) CreateNamedWallet(name string) (err error) {
+
+	if !walletNameRegexp.MatchString(name) {
+		return errors.New("Wallet name can have only letters, digits, - and _ and at most 32 characters")
+	}
+
+	wallet.wallets.updateMutex.Lock()
+	defer wallet.wallets.updateMutex.Unlock()
+
+	names, err := wallet.readWalletsNames()
+	if err != nil {
+		return
+	}
+	if name == WALLET_DEFAULT_NAME {
+		return errors.New("Wallet already exists")
+	}
+	for _, existing := range names {
+		if existing == name {
+			return errors.New("Wallet already exists")
+		}
+	}
+
+	walletStore, err := store.CreateWalletStore(name)
+	if err != nil {
+		return
+	}
+
+	named := wallet.createNamedWallet(name, walletStore)
+	if err = named.CreateEmptyWallet(); err != nil {
+		walletStore.Close()
+		return
+	}
+
+	if err = wallet.writeWalletsNames(append(names, name)); err != nil {
+		walletStore.Close()
+		return
+	}
+
+	wallet.wallets.lock.Lock()
+	wallet.wallets.loaded[name] = named
+	wallet.wallets.lock.Unlock()
+
+	gui.GUI.Log("Wallet " + name + " created")
+	return
+}
+
+// LoadNamedWallet decrypts a named wallet and adds its staked addresses to forging. A locked wallet is unlocked
+func (wallet *Wallet) LoadNamedWallet(name, password string) (err error) {
+
+	if !walletNameRegexp.MatchString(name) {
+		return errors.New("Wallet name is invalid")
+	}
+
+	wallet.wallets.updateMutex.Lock()
+	defer wallet.wallets.updateMutex.Unlock()
+
+	if named, err := wallet.GetWallet(name); err == nil {
+		named.Lock.RLock()
+		loaded := named.Loaded
+		named.Lock.RUnlock()
+		if loaded {
+			return errors.New("Wallet is already loaded")
+		}
+		//a locked wallet is decrypted in place
+		if err = named.Encryption.Decrypt(password); err == nil && !named.isUnlockedEncrypted() {
+			err = errors.New("Wallet is encrypted. The password is required")
+		}
+		if err != nil {
+			return err
+		}
+		gui.GUI.Log("Wallet " + name + " unlocked")
+		return nil
+	}
+
+	names, err := wallet.readWalletsNames()
+	if err != nil {
+		return
+	}
+
+	found := false
+	for _, existing := range names {
+		if existing == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return errors.New("Wallet doesn't exist")
+	}
+
+	walletStore, err := store.CreateWalletStore(name)
+	if err != nil {
+		return
+	}
+
+	named := wallet.createNamedWallet(name, walletStore)
+	if err = named.loadWallet(password, true); err == nil && !named.Loaded {
+		err = errors.New("Wallet is encrypted. The password is required")
+	}
+	if err == nil {
+		err = named.InitForgingWallet()
+	}
+	if err != nil {
+		walletStore.Close()
+		return
+	}
+
+	wallet.wallets.lock.Lock()
+	wallet.wallets.loaded[name] = named
+	wallet.wallets.lock.Unlock()
+
+	gui.GUI.Log("Wallet " + name + " loaded")
+	return
+}
+
+// UnloadNamedWallet removes the wallet and its private keys from memory. Its addresses stop forging
+func (wallet *Wallet) UnloadNamedWallet(name string) (err error) {
+
+	if name == WALLET_DEFAULT_NAME {
+		return errors.New("The default wallet can't be unloaded")
+	}
+
+	wallet.wallets.updateMutex.Lock()
+	defer wallet.wallets.updateMutex.Unlock()
+
+	wallet.wallets.lock.Lock()
+	named := wallet.wallets.loaded[name]
+	if named == nil {
+		wallet.wallets.lock.Unlock()
+		return errors.New("Wallet is not loaded")
+	}
+	delete(wallet.wallets.loaded, name)
+	switched := wallet.wallets.active == named
+	if switched {
+		wallet.wallets.active = wallet.wallets.main
+	}
+	wallet.wallets.lock.Unlock()
+
+	named.Lock.Lock()
+	for _, addr := range named.Addresses {
+		named.forging.Wallet.RemoveWallet(addr.PublicKey, false, nil, nil, 0)
+	}
+	for _, publicKey := range named.lockedForging {
+		named.forging.Wallet.RemoveWallet(publicKey, false, nil, nil, 0)
+	}
+	named.lockedForging = nil
+	named.wipeKeys()
+	named.clearWallet()
+	err = named.store.Close()
+	named.Lock.Unlock()
+
+	if switched {
+		wallet.wallets.main.initWalletCLI()
+		wallet.wallets.main.updateWallet()
+	}
+
+	gui.GUI.Log("Wallet " + name + " unloaded")
+	return
+}
+
+// SwitchWallet activates a loaded wallet for the CLI and for the API calls without a wallet name
+func (wallet *Wallet) SwitchWallet(name string) error {
+
+	wallet.wallets.updateMutex.Lock()
+	defer wallet.wallets.updateMutex.Unlock()
+
+	active, err := wallet.GetWallet(name)
+	if err != nil {
+		return err
+	}
+
+	wallet.wallets.lock.Lock()
+	wallet.wallets.active = active
+	wallet.wallets.lock.Unlock()
+
+	active.initWalletCLI()
+	active.updateWallet()
+
+	gui.GUI.Log("Wallet " + name + " activated")
+	return nil
+}
+
+// closeNamedWallets closes the stores of the named wallets
+func (wallet *Wallet) closeNamedWallets() {
+
+	wallet.wallets.lock.Lock()
+	defer wallet.wallets.lock.Unlock()
+
+	for name, loaded := range wallet.wallets.loaded {
+		if loaded != wallet.wallets.main {
+			loaded.store.Close()
+			delete(wallet.wallets.loaded, name)
+		}
+	}
+	wallet.wallets.active = wallet.wallets.main
+}
@@ -0,0 +1,84 @@
+package wallet
+
+import (
+	"errors"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payment_proof"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+func loadStoredTx(hash []byte) (*transaction.Transaction, error) {
+
+	tx := &transaction.Transaction{}
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		data := reader.Get("tx:" + string(hash))
+		if data == nil {
+			return errors.New("Tx was not found in the storage")
+		}
+		return tx.Deserialize(advanced_buffers.NewBufferReader(data))
+	}); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// CreatePaymentProof proves the amount paid by the payload. The address can be either the sender or the recipient of the payload
+func (wallet *Wallet) CreatePaymentProof(tx *transaction.Transaction, payloadIndex int, publicKey []byte) (*transaction_zether_payment_proof.PaymentProof, error) {
+
+	if len(publicKey) == 0 {
+		return nil, errors.New("Address is missing")
+	}
+
+	decrypted, err := wallet.DecryptTx(tx, publicKey, true)
+	if err != nil {
+		return nil, err
+	}
+	if decrypted.ZetherTx == nil {
+		return nil, errors.New("Only zether transactions have payment proofs")
+	}
+	if payloadIndex < 0 || payloadIndex >= len(decrypted.ZetherTx.Payloads) {
+		return nil, errors.New("Payload index is invalid")
+	}
+
+	output := decrypted.ZetherTx.Payloads[payloadIndex]
+	if output == nil {
+		return nil, errors.New("Address is not part of the ring")
+	}
+
+	addr := wallet.GetWalletAddressByPublicKey(publicKey, true)
+	if addr == nil {
+		return nil, errors.New("Address was not found")
+	}
+
+	txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+	payload := txBase.Payloads[payloadIndex]
+	secret := new(crypto.BNRed).SetBytes(addr.PrivateKey.Key).BigInt()
+
+	switch {
+	case output.WhisperSenderValid:
+
+		amount := output.SentAmount - payload.Statement.Fee - payload.BurnValue
+		r := computeZetherPayloadR(txBase, payloadIndex, secret)
+
+		//the recipient is the member whose commitment is matching the amount
+		for k := range payload.Statement.C {
+			if (k%2 == 0) == payload.Parity {
+				continue
+			}
+			if proof, err := transaction_zether_payment_proof.CreatePaymentProof(tx, transaction_zether_payment_proof.PAYMENT_PROOF_SENDER, payloadIndex, k, amount, r); err == nil {
+				return proof, nil
+			}
+		}
+
+		return nil, errors.New("Recipient was not found")
+	case output.WhisperRecipientValid:
+		return transaction_zether_payment_proof.CreatePaymentProof(tx, transaction_zether_payment_proof.PAYMENT_PROOF_RECIPIENT, payloadIndex, output.RecipientIndex, output.ReceivedAmount, secret)
+	default:
+		return nil, errors.New("Payload was not sent or received by the address")
+	}
+}
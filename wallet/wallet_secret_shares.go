@@ -0,0 +1,58 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"errors"
+	"github.com/tyler-smith/go-bip39"
+	"pandora-pay/cryptography/shamir"
+)
+
+// GetSecretShares splits the entropy of the wallet into count share phrases. Any threshold of them recovers the wallet
+func (wallet *Wallet) GetSecretShares(threshold, count int) ([]string, error) {
+
+	wallet.Lock.RLock()
+	mnemonic := wallet.Mnemonic
+	loaded := wallet.Loaded
+	wallet.Lock.RUnlock()
+
+	if !loaded {
+		return nil, errors.New("Wallet was not loaded!")
+	}
+
+	entropy, err := bip39.EntropyFromMnemonic(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := shamir.Split(entropy, threshold, count, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	phrases := make([]string, len(shares))
+	for i, share := range shares {
+		phrases[i] = share.EncodePhrase()
+	}
+
+	return phrases, nil
+}
+
+// ImportSecretShares recovers the entropy from the share phrases. It will delete the existing wallet
+func (wallet *Wallet) ImportSecretShares(phrases []string) error {
+
+	shares := make([]*shamir.Share, len(phrases))
+	for i, phrase := range phrases {
+		share, err := shamir.DecodeSharePhrase(phrase)
+		if err != nil {
+			return err
+		}
+		shares[i] = share
+	}
+
+	entropy, err := shamir.Combine(shares)
+	if err != nil {
+		return err
+	}
+
+	return wallet.ImportEntropy(entropy)
+}
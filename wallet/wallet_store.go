@@ -59,7 +59,7 @@ func (wallet *Wallet) saveWallet(start, end, deleteIndex int, lock bool) error {
 		return errors.New("Can't save your wallet because your stored wallet on the drive was not successfully loaded")
 	}
 
-	return store.StoreWallet.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+	return wallet.store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
 
 		var marshal []byte
 
@@ -107,7 +107,7 @@ func (wallet *Wallet) loadWallet(password string, firstTime bool) error {
 
 	wallet.clearWallet()
 
-	return store.StoreWallet.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+	return wallet.store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
 
 		saved := reader.Get("saved") //safe only internal
 		if saved == nil {
@@ -171,6 +171,7 @@ func (wallet *Wallet) loadWallet(password string, firstTime bool) error {
 			}
 
 			wallet.setLoaded(true)
+			wallet.unlocked(0)
 			if !firstTime {
 				if err = wallet.walletLoaded(firstTime); err != nil {
 					return